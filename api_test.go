@@ -4,11 +4,42 @@
 package glaze
 
 import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"html/template"
 	"io"
+	"math/big"
+	"mime/multipart"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"testing/fstest"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -130,3 +161,3944 @@ func TestHttpWithMiddlewareSuccess(t *testing.T) {
 	assert.Equal(t, http.StatusOK, resp.StatusCode, "they should be equal")
 	assert.Equal(t, "world", string(body), "they should be equal")
 }
+
+func TestStaticFilePrecompressed(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "app.js"), []byte("plain"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "app.js.gz"), []byte("gzipped"), 0o644))
+
+	r := New()
+	r.StaticFile("/app.js", filepath.Join(dir, "app.js"))
+
+	req := httptest.NewRequest("GET", "/app.js", nil)
+	req.Header.Set("Accept-Encoding", "br;q=0, gzip")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "gzipped", w.Body.String())
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+	assert.Contains(t, w.Header().Get("Content-Type"), "javascript")
+
+	req = httptest.NewRequest("GET", "/app.js", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, "plain", w.Body.String())
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+}
+
+func TestMaintenance(t *testing.T) {
+	var down atomic.Bool
+
+	r := New()
+	r.Use(MaintenanceWithConfig(MaintenanceConfig{
+		Enabled:    func(*Context) bool { return down.Load() },
+		RetryAfter: 2 * time.Minute,
+	}))
+	r.Get("/ping", func(c *Context) {
+		c.String(200, "pong")
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/ping", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	down.Store(true)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/ping", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "120", w.Header().Get("Retry-After"))
+	assert.Equal(t, "Service Unavailable", w.Body.String())
+
+	assert.Equal(t, "1", retryAfterSeconds(300*time.Millisecond))
+	assert.Equal(t, "2", retryAfterSeconds(1500*time.Millisecond))
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	var changes []string
+	fail := true
+
+	r := New()
+	r.Use(CircuitBreaker(CircuitBreakerConfig{
+		MinRequests: 2,
+		OpenTimeout: 20 * time.Millisecond,
+		OnStateChange: func(from, to CircuitState) {
+			changes = append(changes, from.String()+"->"+to.String())
+		},
+	}))
+	r.Get("/report", func(c *Context) {
+		if fail {
+			c.String(http.StatusBadGateway, "downstream error")
+			return
+		}
+		c.String(http.StatusOK, "ok")
+	})
+
+	serve := func() int {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", "/report", nil))
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusBadGateway, serve())
+	assert.Equal(t, http.StatusBadGateway, serve())
+	assert.Equal(t, http.StatusServiceUnavailable, serve(), "circuit should be open")
+
+	time.Sleep(25 * time.Millisecond)
+	fail = false
+	assert.Equal(t, http.StatusOK, serve(), "half-open probe should pass")
+	assert.Equal(t, http.StatusOK, serve())
+	assert.Equal(t, []string{"closed->open", "open->half-open", "half-open->closed"}, changes)
+}
+
+func TestConcurrencyLimit(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	r := New()
+	r.Get("/slow", ConcurrencyLimitWithConfig(ConcurrencyLimitConfig{MaxInFlight: 1, RetryAfter: 200 * time.Millisecond}), func(c *Context) {
+		started <- struct{}{}
+		<-release
+		c.String(http.StatusOK, "done")
+	})
+
+	first := httptest.NewRecorder()
+	finished := make(chan struct{})
+	go func() {
+		r.ServeHTTP(first, httptest.NewRequest("GET", "/slow", nil))
+		close(finished)
+	}()
+	<-started
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/slow", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+
+	close(release)
+	<-finished
+	assert.Equal(t, http.StatusOK, first.Code)
+}
+
+func TestRecoveryWithHandler(t *testing.T) {
+	var recovered any
+
+	r := New(WithOutput(io.Discard), WithErrorOutput(io.Discard))
+	r.Use(RecoveryWithHandler(func(c *Context, err any) {
+		recovered = err
+		c.JSON(http.StatusInternalServerError, M{"error": "boom"})
+	}))
+	r.Get("/panic", func(c *Context) {
+		panic("something went wrong")
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/panic", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.JSONEq(t, `{"error":"boom"}`, w.Body.String())
+	assert.Equal(t, "something went wrong", recovered)
+}
+
+func TestRecoveryReportRedactsSecrets(t *testing.T) {
+	var report PanicReport
+
+	r := New()
+	r.Use(RecoveryWithConfig(RecoveryConfig{
+		Report: func(c *Context, p PanicReport) { report = p },
+	}))
+	r.Get("/panic", func(c *Context) {
+		panic("boom")
+	})
+
+	req := httptest.NewRequest("GET", "/panic?x=1&access_token=secret", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("Accept", "text/plain")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "boom", report.Error)
+	assert.Equal(t, "/panic?x=[REDACTED]&access_token=[REDACTED]", report.URI)
+	assert.Equal(t, "[REDACTED]", report.Header.Get("Authorization"))
+	assert.Equal(t, "text/plain", report.Header.Get("Accept"))
+	assert.NotZero(t, report.Goroutine)
+	assert.NotContains(t, report.String(), "secret")
+
+	r = New()
+	r.Use(RecoveryWithConfig(RecoveryConfig{
+		Report:      func(c *Context, p PanicReport) { report = p },
+		RedactQuery: []string{"access_token"},
+	}))
+	r.Get("/panic", func(c *Context) { panic("boom") })
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/panic?x=1&access%5Ftoken=secret&flag", nil))
+	assert.Equal(t, "/panic?x=1&access%5Ftoken=[REDACTED]&flag", report.URI)
+}
+
+func TestRecoveryRePanic(t *testing.T) {
+	r := New(WithOutput(io.Discard), WithErrorOutput(io.Discard))
+	r.Use(RecoveryWithConfig(RecoveryConfig{RePanic: true}))
+	r.Get("/panic", func(c *Context) {
+		panic("boom")
+	})
+
+	assert.PanicsWithValue(t, "boom", func() {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/panic", nil))
+	})
+}
+
+func TestLoggerSkipAndSample(t *testing.T) {
+	var buf strings.Builder
+
+	r := New()
+	r.Use(LoggerWithConfig(LoggerConfig{
+		Output:      &buf,
+		SkipPaths:   []string{"/health"},
+		SampleRates: map[string]float64{"/events": 0},
+	}))
+	r.Get("/health", func(c *Context) { c.String(200, "ok") })
+	r.Get("/events", func(c *Context) { c.String(200, "ok") })
+	r.Get("/users", func(c *Context) { c.String(200, "ok") })
+
+	for _, p := range []string{"/health", "/events", "/users"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", p, nil))
+	}
+
+	assert.Equal(t, 1, strings.Count(buf.String(), "\n"))
+	assert.Contains(t, buf.String(), "GET     /users")
+}
+
+func TestI18n(t *testing.T) {
+	bundle := NewBundle("en")
+	bundle.AddMessages("en", map[string]string{"hello": "Hello %s"})
+	fsys := fstest.MapFS{
+		"locales/id.json": {Data: []byte(`{"hello": "Halo %s", "user": {"bye": "Sampai jumpa"}}`)},
+	}
+	assert.NoError(t, bundle.LoadFS(fsys, "locales"))
+
+	r := New()
+	r.Use(I18n(bundle))
+	r.Get("/hello", func(c *Context) {
+		c.String(200, c.Locale()+": "+c.T("hello", "bob")+" / "+c.T("user.bye"))
+	})
+
+	req := httptest.NewRequest("GET", "/hello", nil)
+	req.Header.Set("Accept-Language", "fr;q=0.9, id-ID, en;q=0.5")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, "id: Halo bob / Sampai jumpa", w.Body.String())
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/hello?lang=en", nil))
+	assert.Equal(t, "en: Hello bob / user.bye", w.Body.String())
+}
+
+type testPrincipal struct {
+	roles []string
+}
+
+func (p testPrincipal) HasRole(role string) bool {
+	for _, r := range p.roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+func (p testPrincipal) HasPermission(string) bool { return false }
+
+func TestAuthorizeRouteMeta(t *testing.T) {
+	r := New()
+	r.Use(func(c *Context) {
+		if role := c.GetHeader("X-Role"); role != "" {
+			c.SetPrincipal(testPrincipal{roles: []string{role}})
+		}
+	}, Authorize())
+
+	r.Get("/public", func(c *Context) { c.String(200, "public") })
+	r.Group("/admin").Roles("admin").Get("/users/:id", func(c *Context) {
+		c.String(200, c.FullPath())
+	})
+
+	serve := func(path, role string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		if role != "" {
+			req.Header.Set("X-Role", role)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, serve("/public", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, serve("/admin/users/1", "").Code)
+	assert.Equal(t, http.StatusForbidden, serve("/admin/users/1", "user").Code)
+
+	w := serve("/admin/users/1", "admin")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "/admin/users/:id", w.Body.String())
+}
+
+func TestVerifySignatureStripe(t *testing.T) {
+	secret := []byte("whsec_test")
+	body := `{"id":"evt_1"}`
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(ts + "." + body))
+	sig := hex.EncodeToString(mac.Sum(nil))
+
+	r := New()
+	r.Post("/webhook", VerifySignature(StripeSignature(secret, 5*time.Minute)), func(c *Context) {
+		var evt M
+		if err := c.BindJSON(&evt); err != nil {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		c.String(http.StatusOK, evt["id"].(string))
+	})
+
+	serve := func(signature string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/webhook", strings.NewReader(body))
+		req.Header.Set("Content-Type", MIME_JSON)
+		req.Header.Set("Stripe-Signature", signature)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve("t=" + ts + ",v1=deadbeef,v1=" + sig)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "evt_1", w.Body.String())
+
+	assert.Equal(t, http.StatusUnauthorized, serve("t="+ts+",v1=deadbeef").Code)
+	assert.Equal(t, http.StatusUnauthorized, serve("t=1000,v1="+sig).Code)
+}
+
+func TestIdempotency(t *testing.T) {
+	var created atomic.Int32
+
+	r := New()
+	r.Use(Idempotency())
+	r.Post("/orders", func(c *Context) {
+		n := created.Add(1)
+		c.Writer.Header().Set("Location", "/orders/"+strconv.Itoa(int(n)))
+		c.String(http.StatusCreated, "order "+strconv.Itoa(int(n)))
+	})
+
+	serve := func(key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/orders", nil)
+		if key != "" {
+			req.Header.Set("Idempotency-Key", key)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	first := serve("abc")
+	retry := serve("abc")
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.Equal(t, first.Body.String(), retry.Body.String())
+	assert.Equal(t, "/orders/1", retry.Header().Get("Location"))
+	assert.Equal(t, "true", retry.Header().Get("Idempotent-Replayed"))
+	assert.Equal(t, int32(1), created.Load())
+
+	assert.Equal(t, "order 2", serve("other").Body.String())
+	assert.Equal(t, "order 3", serve("").Body.String())
+}
+
+func TestIdempotencyBodyAndCompress(t *testing.T) {
+	var created atomic.Int32
+	receipt := strings.Repeat("receipt ", 100)
+
+	r := New()
+	r.Use(Compress(CompressConfig{MinLength: 10}), Idempotency())
+	r.Post("/payments", func(c *Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		created.Add(1)
+		c.String(http.StatusCreated, receipt+string(body))
+	})
+
+	serve := func(body, encoding string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/payments", strings.NewReader(body))
+		req.Header.Set("Idempotency-Key", "pay-1")
+		if encoding != "" {
+			req.Header.Set("Accept-Encoding", encoding)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve(`{"amount":10}`, "gzip")
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+
+	w = serve(`{"amount":10}`, "")
+	assert.Equal(t, "true", w.Header().Get("Idempotent-Replayed"))
+	assert.Empty(t, w.Header().Get("Content-Encoding"), "replayed uncompressed to a client without gzip")
+	assert.Equal(t, receipt+`{"amount":10}`, w.Body.String())
+
+	w = serve(`{"amount":99}`, "")
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code, "same key, other body")
+	assert.Equal(t, int32(1), created.Load())
+}
+
+func TestHTTPSRedirect(t *testing.T) {
+	r := New()
+	r.Use(HTTPSRedirect(HTTPSRedirectConfig{
+		TrustForwardedProto: true,
+		AllowedHosts:        []string{"example.com"},
+		SkipPaths:           []string{"/health"},
+	}))
+	r.Get("/page", func(c *Context) { c.String(200, "secure") })
+	r.Get("/health", func(c *Context) { c.String(200, "ok") })
+
+	serve := func(host, path, proto string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Host = host
+		if proto != "" {
+			req.Header.Set("X-Forwarded-Proto", proto)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve("example.com:8080", "/page?x=1", "")
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "https://example.com/page?x=1", w.Header().Get("Location"))
+
+	assert.Equal(t, http.StatusOK, serve("example.com", "/page", "https").Code)
+	assert.Equal(t, http.StatusOK, serve("example.com", "/health", "").Code)
+	assert.Equal(t, http.StatusBadRequest, serve("evil.com", "/page", "").Code)
+
+	r = New()
+	r.Use(HTTPSRedirect())
+	r.Get("/page", func(c *Context) { c.String(200, "secure") })
+	for _, host := range []string{"[::1]:8080", "[::1]"} {
+		w = serve(host, "/page", "")
+		assert.Equal(t, "https://[::1]/page", w.Header().Get("Location"), host)
+	}
+}
+
+func TestForwarded(t *testing.T) {
+	r := New()
+	r.Use(Forwarded(ForwardedConfig{TrustedProxies: []string{"10.0.0.0/8"}}))
+	r.Get("/ip", func(c *Context) {
+		c.String(200, c.ClientIP()+" "+c.Scheme()+" "+c.Host())
+	})
+
+	serve := func(remote string, header http.Header) string {
+		req := httptest.NewRequest("GET", "/ip", nil)
+		req.RemoteAddr = remote
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Body.String()
+	}
+
+	assert.Equal(t, "203.0.113.7 https api.example.com", serve("10.0.0.2:5000", http.Header{
+		"Forwarded": {`for=198.51.100.1, for="203.0.113.7:4711";proto=https;host=api.example.com, for=10.0.0.9`},
+	}))
+	assert.Equal(t, "203.0.113.7 https example.com", serve("10.0.0.2:5000", http.Header{
+		"X-Forwarded-For":   {"203.0.113.7, 10.0.0.5"},
+		"X-Forwarded-Proto": {"https"},
+	}))
+	// untrusted peer, headers ignored
+	assert.Equal(t, "192.0.2.1 http example.com", serve("192.0.2.1:5000", http.Header{
+		"X-Forwarded-For": {"203.0.113.7"},
+	}))
+}
+
+func TestUsePhase(t *testing.T) {
+	var order []string
+	mw := func(name string) HandlerFunc {
+		return func(c *Context) {
+			order = append(order, name+">")
+			c.Next()
+			order = append(order, "<"+name)
+		}
+	}
+
+	r := New()
+	r.UsePhase(PhasePreRouting, 20, mw("logging"))
+	r.UsePhase(PhasePreRouting, 10, mw("tracing"), func(c *Context) {
+		c.Request.URL.Path = strings.TrimPrefix(c.Request.URL.Path, "/v1")
+	})
+	r.UsePhase(PhasePostRouting, 0, func(c *Context) {
+		order = append(order, "route "+c.FullPath())
+	})
+	r.UsePhase(PhasePostResponse, 0, func(c *Context) {
+		order = append(order, "done "+strconv.Itoa(c.Writer.Status()))
+	})
+	r.Use(mw("group"))
+	r.Get("/users", func(c *Context) {
+		order = append(order, "handler")
+		c.String(200, "ok")
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/v1/users", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{
+		"tracing>", "logging>", "route /users", "group>", "handler", "<group", "<logging", "<tracing", "done 200",
+	}, order)
+
+	order = nil
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/missing", nil))
+	assert.Equal(t, []string{"tracing>", "logging>", "<logging", "<tracing", "done 404"}, order)
+}
+
+func TestWhen(t *testing.T) {
+	tag := func(c *Context) { c.Writer.Header().Set("X-Tag", "api") }
+
+	r := New()
+	r.Use(When(IfPathPrefix("/api"), tag))
+	r.Use(When(Not(IfHeader("X-Debug")), func(c *Context) { c.Set("quiet", true) }))
+	r.Get("/api/users", func(c *Context) { c.String(200, "ok") })
+	r.Get("/page", func(c *Context) {
+		_, quiet := c.Get("quiet")
+		c.String(200, strconv.FormatBool(quiet))
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/api/users", nil))
+	assert.Equal(t, "api", w.Header().Get("X-Tag"))
+
+	req := httptest.NewRequest("GET", "/page", nil)
+	req.Header.Set("X-Debug", "1")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Empty(t, w.Header().Get("X-Tag"))
+	assert.Equal(t, "false", w.Body.String())
+}
+
+type testValidationError struct{ field string }
+
+func (e *testValidationError) Error() string { return e.field + " is invalid" }
+
+func TestErrorHandlerMapping(t *testing.T) {
+	errNotFound := errors.New("record not found")
+
+	r := New()
+	r.MapError(errNotFound, http.StatusNotFound, M{"error": "not found"})
+	MapErrorAs[*testValidationError](r, http.StatusUnprocessableEntity)
+	r.Use(ErrorHandler())
+	r.Get("/users/:id", func(c *Context) {
+		switch c.Param("id") {
+		case "missing":
+			c.Error(fmt.Errorf("load user: %w", errNotFound))
+		case "invalid":
+			c.Error(&testValidationError{field: "id"})
+		case "boom":
+			c.Error(errors.New("db password is hunter2"))
+		default:
+			c.String(200, "ok")
+		}
+	})
+
+	serve := func(id string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", "/users/"+id, nil))
+		return w
+	}
+
+	w := serve("missing")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.JSONEq(t, `{"error":"not found"}`, w.Body.String())
+
+	w = serve("invalid")
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.JSONEq(t, `{"error":"id is invalid"}`, w.Body.String())
+
+	w = serve("boom")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "hunter2")
+
+	assert.Equal(t, "ok", serve("1").Body.String())
+}
+
+type testReporter struct {
+	panics []any
+	errs   []error
+}
+
+func (r *testReporter) ReportPanic(c *Context, p PanicReport) { r.panics = append(r.panics, p.Error) }
+func (r *testReporter) ReportError(c *Context, err error)     { r.errs = append(r.errs, err) }
+
+func TestReporter(t *testing.T) {
+	rep := &testReporter{}
+	errMissing := errors.New("missing")
+
+	r := New(WithReporter(rep), WithOutput(io.Discard), WithErrorOutput(io.Discard))
+	r.MapError(errMissing, http.StatusNotFound)
+	r.Use(Recovery(), ErrorHandler())
+	r.Get("/panic", func(c *Context) { panic("boom") })
+	r.Get("/missing", func(c *Context) { c.Error(errMissing) })
+	r.Get("/fail", func(c *Context) { c.Error(errors.New("db down")) })
+
+	for _, p := range []string{"/panic", "/missing", "/fail"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", p, nil))
+	}
+
+	assert.Equal(t, []any{"boom"}, rep.panics)
+	assert.Len(t, rep.errs, 1)
+	assert.EqualError(t, rep.errs[0], "db down")
+}
+
+func TestCacheControl(t *testing.T) {
+	r := New()
+	api := r.Group("/api", NoStore())
+	api.Get("/me", func(c *Context) { c.String(200, "me") })
+	r.Get("/logo", CacheControl("public, max-age=3600"), func(c *Context) { c.String(200, "logo") })
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/api/me", nil))
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/logo", nil))
+	assert.Equal(t, "public, max-age=3600", w.Header().Get("Cache-Control"))
+}
+
+func TestRunListener(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	r := New(func(e *Engine) { e.writer = io.Discard })
+	r.Get("/ping", func(c *Context) { c.String(200, "pong") })
+
+	done := make(chan error, 1)
+	go func() { done <- r.RunListener(listener) }()
+
+	resp, err := http.Get("http://" + listener.Addr().String() + "/ping")
+	assert.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, "pong", string(body))
+
+	listener.Close()
+	assert.Error(t, <-done)
+}
+
+func TestServerConfig(t *testing.T) {
+	e := New()
+	srv := e.newServer(":8080")
+	assert.Equal(t, ":8080", srv.Addr)
+	assert.Equal(t, 10*time.Second, srv.ReadHeaderTimeout)
+	assert.Equal(t, 120*time.Second, srv.IdleTimeout)
+	assert.Equal(t, time.Duration(0), srv.WriteTimeout)
+
+	e = New(
+		WithReadTimeout(5*time.Second),
+		WithWriteTimeout(30*time.Second),
+		WithMaxHeaderBytes(1<<16),
+	)
+	srv = e.newServer(":8080")
+	assert.Equal(t, 5*time.Second, srv.ReadTimeout)
+	assert.Equal(t, 30*time.Second, srv.WriteTimeout)
+	assert.Equal(t, 1<<16, srv.MaxHeaderBytes)
+	assert.Equal(t, e, srv.Handler)
+
+	custom := &http.Server{ReadTimeout: time.Second, TLSConfig: &tls.Config{MinVersion: tls.VersionTLS13}}
+	e = New(WithServer(custom))
+	srv = e.newServer(":9090")
+	assert.NotSame(t, custom, srv)
+	assert.Equal(t, ":9090", srv.Addr)
+	assert.Equal(t, time.Second, srv.ReadTimeout)
+	assert.Equal(t, e, srv.Handler)
+	assert.Equal(t, uint16(tls.VersionTLS13), srv.TLSConfig.MinVersion)
+
+	// one server per Run helper, TLS settings of one do not leak
+	srv.TLSConfig.ClientAuth = tls.RequireAnyClientCert
+	other := e.newServer(":9091")
+	assert.NotSame(t, srv, other)
+	assert.Equal(t, ":9091", other.Addr)
+	assert.Equal(t, tls.NoClientCert, other.TLSConfig.ClientAuth)
+	assert.Empty(t, custom.Addr)
+}
+
+func TestOnStart(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	r := New(func(e *Engine) { e.writer = io.Discard })
+	r.Get("/ping", func(c *Context) { c.String(200, "pong") })
+
+	var called []string
+	boom := errors.New("cache unavailable")
+	r.OnStart(
+		func(ctx context.Context) error { called = append(called, "warm"); return nil },
+		func(ctx context.Context) error { called = append(called, "fail"); return boom },
+		func(ctx context.Context) error { called = append(called, "never"); return nil },
+	)
+
+	err = r.RunListener(listener)
+	assert.ErrorIs(t, err, boom)
+	assert.Equal(t, []string{"warm", "fail"}, called)
+
+	// listener is closed, nothing was served
+	_, err = net.Dial("tcp", listener.Addr().String())
+	assert.Error(t, err)
+}
+
+func TestEngineShutdown(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	r := New(func(e *Engine) { e.writer = io.Discard })
+	r.Get("/ping", func(c *Context) { c.String(200, "pong") })
+
+	done := make(chan error, 1)
+	go func() { done <- r.RunListener(listener) }()
+
+	resp, err := http.Get("http://" + listener.Addr().String() + "/ping")
+	assert.NoError(t, err)
+	resp.Body.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, r.Shutdown(ctx))
+	assert.ErrorIs(t, <-done, http.ErrServerClosed)
+
+	// nothing running anymore
+	assert.NoError(t, r.Close())
+}
+
+func TestSetMode(t *testing.T) {
+	defer SetMode(Mode())
+
+	assert.Equal(t, DebugMode, Mode())
+	assert.True(t, IsDebugging())
+
+	var out strings.Builder
+	r := New(func(e *Engine) { e.writer = &out })
+	r.Get("/ping", func(c *Context) {})
+
+	SetMode(ReleaseMode)
+	assert.False(t, IsDebugging())
+	r.debugPrintRoutes()
+	assert.Empty(t, out.String())
+
+	SetMode(TestMode)
+	assert.Equal(t, TestMode, Mode())
+
+	SetMode("")
+	r.debugPrintRoutes()
+	assert.Contains(t, out.String(), "/ping")
+
+	assert.Panics(t, func() { SetMode("prod") })
+}
+
+func TestEngineLogOutputs(t *testing.T) {
+	var out, errOut strings.Builder
+	r := New(WithOutput(&out), WithErrorOutput(&errOut))
+	r.Use(Recovery())
+	r.Get("/panic", func(c *Context) { panic("boom") })
+
+	r.debugPrintRoutes()
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/panic", nil))
+
+	assert.Contains(t, out.String(), "/panic")
+	assert.NotContains(t, out.String(), "boom")
+	assert.Contains(t, errOut.String(), "boom")
+
+	out.Reset()
+	errOut.Reset()
+	r.Config(WithLogLevel(LogLevelError))
+	r.debugPrintRoutes()
+	r.logf(LogLevelInfo, "Server exiting\n")
+	assert.Empty(t, out.String())
+
+	r.Config(WithLogLevel(LogLevelSilent))
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/panic", nil))
+	assert.Empty(t, errOut.String())
+}
+
+func TestClientCertificates(t *testing.T) {
+	e := New()
+	assert.Nil(t, e.tlsConfig(nil))
+
+	pool := x509.NewCertPool()
+	var checked *x509.Certificate
+	e = New(WithClientCAs(pool), WithClientVerify(func(cert *x509.Certificate) error {
+		checked = cert
+		return nil
+	}))
+	base := &tls.Config{MinVersion: tls.VersionTLS13}
+	cfg := e.tlsConfig(base)
+	assert.Equal(t, tls.RequireAndVerifyClientCert, cfg.ClientAuth)
+	assert.Same(t, pool, cfg.ClientCAs)
+	assert.Equal(t, uint16(tls.VersionTLS13), cfg.MinVersion)
+	assert.Equal(t, tls.NoClientCert, base.ClientAuth)
+
+	leaf := &x509.Certificate{Subject: pkix.Name{CommonName: "billing"}}
+	assert.NoError(t, cfg.VerifyConnection(tls.ConnectionState{PeerCertificates: []*x509.Certificate{leaf}}))
+	assert.Same(t, leaf, checked)
+
+	e = New(WithClientAuth(tls.VerifyClientCertIfGiven), WithClientCAs(pool))
+	assert.Equal(t, tls.VerifyClientCertIfGiven, e.tlsConfig(nil).ClientAuth)
+
+	r := New()
+	r.Get("/whoami", func(c *Context) {
+		if cert := c.PeerCertificate(); cert != nil {
+			c.String(200, cert.Subject.CommonName)
+			return
+		}
+		c.String(401, "no certificate")
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/whoami", nil))
+	assert.Equal(t, 401, w.Code)
+
+	req := httptest.NewRequest("GET", "/whoami", nil)
+	req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{leaf}}
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 401, w.Code) // not verified
+
+	req.TLS.VerifiedChains = [][]*x509.Certificate{{leaf}}
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, "billing", w.Body.String())
+}
+
+func TestConnStats(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	var states atomic.Int32
+	inHandler := make(chan ConnStats, 1)
+	r := New(WithOutput(io.Discard), WithConnState(func(net.Conn, http.ConnState) {
+		states.Add(1)
+	}))
+	r.Get("/ping", func(c *Context) {
+		inHandler <- r.ConnStats()
+		c.String(200, "pong")
+	})
+	go r.RunListener(listener)
+	defer r.Close()
+
+	client := &http.Client{Transport: &http.Transport{}}
+	resp, err := client.Get("http://" + listener.Addr().String() + "/ping")
+	assert.NoError(t, err)
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	assert.Equal(t, ConnStats{Open: 1, Active: 1}, <-inHandler)
+	assert.Eventually(t, func() bool {
+		return r.ConnStats() == ConnStats{Open: 1, Idle: 1}
+	}, time.Second, 5*time.Millisecond)
+
+	client.CloseIdleConnections()
+	assert.Eventually(t, func() bool {
+		return r.ConnStats() == ConnStats{}
+	}, time.Second, 5*time.Millisecond)
+	assert.GreaterOrEqual(t, states.Load(), int32(3))
+}
+
+func TestEngineServer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	r := New(WithOutput(io.Discard))
+	r.Get("/ping", func(c *Context) {
+		c.String(200, c.Request.Context().Value(ctxKey("tenant")).(string))
+	})
+
+	srv := r.Server()
+	assert.Same(t, srv, r.Server())
+	srv.BaseContext = func(net.Listener) context.Context {
+		return context.WithValue(context.Background(), ctxKey("tenant"), "acme")
+	}
+
+	go r.RunListener(listener)
+	defer r.Close()
+
+	resp, err := http.Get("http://" + listener.Addr().String() + "/ping")
+	assert.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, "acme", string(body))
+}
+
+func TestContextInjection(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	r := New(WithOutput(io.Discard), WithBaseContext(func(net.Listener) context.Context {
+		return context.WithValue(context.Background(), ctxKey("region"), "eu")
+	}))
+	r.ContextValue(ctxKey("version"), "1.2.0")
+	r.InjectContext(func(ctx context.Context, c *Context) context.Context {
+		return context.WithValue(ctx, ctxKey("user"), c.GetHeader("X-User"))
+	})
+	var notFound string
+	r.OnResponse(func(c *Context) {
+		notFound, _ = c.Request.Context().Value(ctxKey("version")).(string)
+	})
+	r.Get("/ctx", func(c *Context) {
+		ctx := c.Request.Context()
+		c.String(200, fmt.Sprint(ctx.Value(ctxKey("region")), " ", ctx.Value(ctxKey("version")), " ", ctx.Value(ctxKey("user"))))
+	})
+
+	go r.RunListener(listener)
+	defer r.Close()
+
+	req, _ := http.NewRequest("GET", "http://"+listener.Addr().String()+"/ctx", nil)
+	req.Header.Set("X-User", "jalu")
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, "eu 1.2.0 jalu", string(body))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/missing", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "1.2.0", notFound)
+}
+
+type ctxKey string
+
+func pingHandler(c *Context) { c.String(200, "pong") }
+
+func TestDebugPrintRouteFunc(t *testing.T) {
+	defer func() { DebugPrintRouteFunc = nil }()
+
+	var out strings.Builder
+	r := New(WithOutput(&out))
+	r.Group("/api", Logger()).Get("/ping", pingHandler)
+
+	DebugPrintRouteFunc = func(w io.Writer, method, path, handler string, handlers int) {
+		fmt.Fprintf(w, "%s %s %s %d\n", method, path, handler, handlers)
+	}
+	r.debugPrintRoutes()
+	assert.Equal(t, "GET /api/ping github.com/nrhox/glaze.pingHandler 2\n"+
+		"[WARNING] Recovery middleware is not used by 1 routes, a panic will drop the connection\n", out.String())
+
+	info := r.RoutesInfo()[0]
+	assert.Equal(t, "github.com/nrhox/glaze.pingHandler", info.Handler)
+	assert.Equal(t, 2, info.Handlers)
+}
+
+func TestLint(t *testing.T) {
+	r := New()
+	api := r.Group("/api", Logger())
+	api.Get("/users/:id", pingHandler)
+	api.Get("/users/:name/posts", pingHandler)
+	api.Get("/status")
+	r.Group("/admin")
+	r.Get("/empty")
+
+	var got []string
+	for _, issue := range r.Lint() {
+		got = append(got, issue.String())
+	}
+	assert.ElementsMatch(t, []string{
+		`GET /api/users/:name/posts: param ':name' is shadowed by ':id', c.Param("name") is empty`,
+		"GET /api/status: route has only middleware, no handler of its own",
+		"GET /empty: route has no handlers",
+		"/admin: group has no routes",
+		"Recovery middleware is not used by 4 routes, a panic will drop the connection",
+	}, got)
+
+	r = New()
+	r.Use(Recovery())
+	r.Get("/ping", pingHandler)
+	assert.Empty(t, r.Lint())
+
+	// only the marked middleware count, not the names
+	r = New()
+	r.Get("/ping", MarkRecovery(func(c *Context) { c.Next() }), pingHandler)
+	r.Get("/pong", func(c *Context) { c.Next() }, pingHandler)
+	assert.Equal(t, []LintIssue{{Message: "Recovery middleware is not used by 1 routes, a panic will drop the connection"}}, r.Lint())
+}
+
+func TestSetTrustedProxies(t *testing.T) {
+	r := New()
+	r.Get("/ip", func(c *Context) {
+		c.String(200, c.ClientIP()+" "+c.Scheme())
+	})
+
+	req := httptest.NewRequest("GET", "/ip", nil)
+	req.RemoteAddr = "10.0.0.5:4000"
+	req.Header.Set("X-Forwarded-For", "203.0.113.7")
+	req.Header.Set("X-Forwarded-Proto", "https")
+
+	// trust nothing by default
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, "10.0.0.5 http", w.Body.String())
+
+	assert.Error(t, r.SetTrustedProxies([]string{"not-an-ip"}))
+	assert.NoError(t, r.SetTrustedProxies([]string{"10.0.0.0/8"}))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, "203.0.113.7 https", w.Body.String())
+
+	// untrusted peer
+	req.RemoteAddr = "198.51.100.1:4000"
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, "198.51.100.1 http", w.Body.String())
+}
+
+func TestRequestHooks(t *testing.T) {
+	var events []string
+	r := New()
+	r.OnRequest(func(c *Context) { events = append(events, "request "+c.Request.URL.Path) })
+	r.OnResponse(func(c *Context) {
+		events = append(events, fmt.Sprintf("response %s %d", c.Request.URL.Path, c.Writer.Status()))
+	})
+	r.Get("/ok", func(c *Context) { c.String(201, "created") })
+	r.Get("/panic", func(c *Context) { panic("boom") })
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/ok", nil))
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/missing", nil))
+	assert.Panics(t, func() {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/panic", nil))
+	})
+
+	assert.Equal(t, []string{
+		"request /ok", "response /ok 201",
+		"request /missing", "response /missing 404",
+		"request /panic", "response /panic 200",
+	}, events)
+}
+
+type appContext struct {
+	*Context
+	user string
+}
+
+func (c *appContext) User() string { return c.user }
+
+func TestContextFactory(t *testing.T) {
+	calls := 0
+	r := New()
+	r.ContextFactory(func(c *Context) any {
+		calls++
+		return &appContext{Context: c, user: c.GetHeader("X-User")}
+	})
+	r.Use(func(c *Context) { Ext[*appContext](c).Set("seen", true) })
+	r.Get("/me", Extend(func(c *appContext) {
+		_, seen := c.Get("seen")
+		c.String(200, fmt.Sprintf("%s %v", c.User(), seen))
+	}))
+	r.Get("/wrong", func(c *Context) { Ext[string](c) })
+
+	req := httptest.NewRequest("GET", "/me", nil)
+	req.Header.Set("X-User", "ana")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, "ana true", w.Body.String())
+	assert.Equal(t, 1, calls)
+
+	assert.Panics(t, func() {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/wrong", nil))
+	})
+}
+
+func TestDefaultResponses(t *testing.T) {
+	r := New(WithErrorOutput(io.Discard))
+	r.Use(Recovery())
+	r.Get("/users", pingHandler)
+	r.Post("/users", pingHandler)
+	r.Get("/panic", func(c *Context) { panic("boom") })
+	r.ErrorTemplate(http.StatusNotFound, template.Must(template.New("404").Parse("missing {{.Path}}")))
+
+	do := func(method, path, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("GET", "/nope", "")
+	assert.Equal(t, 404, w.Code)
+	assert.Equal(t, "404 Not Found", w.Body.String())
+
+	w = do("GET", "/nope", "application/json")
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"error":"Not Found"}`, w.Body.String())
+
+	w = do("GET", "/nope", "text/html,application/xhtml+xml,*/*;q=0.8")
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "missing /nope", w.Body.String())
+
+	w = do("DELETE", "/users", "application/problem+json")
+	assert.Equal(t, 405, w.Code)
+	assert.Equal(t, "GET, POST", w.Header().Get("Allow"))
+	assert.JSONEq(t, `{"error":"Method Not Allowed"}`, w.Body.String())
+
+	w = do("GET", "/panic", "text/html")
+	assert.Equal(t, 500, w.Code)
+	assert.Contains(t, w.Body.String(), "<h1>500 Internal Server Error</h1>")
+}
+
+func TestJSONConfig(t *testing.T) {
+	r := New(WithJSON(JSONConfig{
+		EscapeHTML: true,
+		Indent:     "  ",
+		Transform: func(c *Context, code int, data any) any {
+			return M{"data": data, "ok": code < 400}
+		},
+	}))
+	r.Get("/item", func(c *Context) { c.JSON(200, M{"name": "<b>"}) })
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/item", nil))
+	assert.Equal(t, "{\n  \"data\": {\n    \"name\": \"\\u003cb\\u003e\"\n  },\n  \"ok\": true\n}\n", w.Body.String())
+
+	// built-in responses use the same settings
+	req := httptest.NewRequest("GET", "/missing", nil)
+	req.Header.Set("Accept", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.JSONEq(t, `{"data":{"error":"Not Found"},"ok":false}`, w.Body.String())
+
+	defer SetMode(Mode())
+	SetMode(ReleaseMode)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/item", nil))
+	assert.Equal(t, `{"data":{"name":"\u003cb\u003e"},"ok":true}`+"\n", w.Body.String())
+}
+
+func TestJSONTimeFormatAndEncodeError(t *testing.T) {
+	type event struct {
+		Name  string    `json:"name"`
+		At    time.Time `json:"at"`
+		Until time.Time `json:"until,omitzero"`
+	}
+	at := time.Date(2025, 3, 4, 5, 6, 7, 8, time.UTC)
+	var errs []error
+	r := New(WithJSON(JSONConfig{TimeFormat: time.DateTime}), WithErrorOutput(io.Discard))
+	r.Use(func(c *Context) { c.Next(); errs = c.Errors() })
+	r.Get("/event", func(c *Context) {
+		c.JSON(200, M{"event": event{Name: `say "hi"`, At: at}, "2025-03-04T05:06:07Z": 1})
+	})
+	r.Get("/bad", func(c *Context) { c.JSON(200, M{"f": func() {}}) })
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/event", nil))
+	assert.JSONEq(t, `{"event":{"name":"say \"hi\"","at":"2025-03-04 05:06:07"},"2025-03-04T05:06:07Z":1}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/bad", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Len(t, errs, 1)
+}
+
+func TestListenReusePort(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("SO_REUSEPORT not supported")
+	}
+	first, err := listenReusePort("127.0.0.1:0")
+	assert.NoError(t, err)
+	defer first.Close()
+
+	second, err := listenReusePort(first.Addr().String())
+	assert.NoError(t, err)
+	second.Close()
+
+	assert.False(t, IsPreforkChild())
+}
+
+func TestContextDoneOnShutdown(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	waiting := make(chan struct{})
+	r := New(WithOutput(io.Discard))
+	r.Get("/draining", func(c *Context) {
+		select {
+		case <-c.Done():
+			c.String(200, "yes")
+		default:
+			c.String(200, "no")
+		}
+	})
+	r.Get("/poll", func(c *Context) {
+		close(waiting)
+		select {
+		case <-c.Done():
+			c.String(200, "reconnect")
+		case <-time.After(5 * time.Second):
+			c.String(200, "timeout")
+		}
+	})
+	stopped := make(chan error, 1)
+	go func() { stopped <- r.RunListener(listener) }()
+
+	body := make(chan string, 1)
+	go func() {
+		resp, err := http.Get("http://" + listener.Addr().String() + "/poll")
+		if err != nil {
+			body <- err.Error()
+			return
+		}
+		b, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		body <- string(b)
+	}()
+
+	<-waiting
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	assert.NoError(t, r.Shutdown(ctx))
+	assert.Equal(t, "reconnect", <-body)
+	<-stopped
+
+	// started again, the requests are not draining
+	listener, err = net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	go r.RunListener(listener)
+	defer r.Close()
+	assert.Eventually(t, func() bool {
+		resp, err := http.Get("http://" + listener.Addr().String() + "/draining")
+		if err != nil {
+			return false
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		return assert.Equal(t, "no", string(b))
+	}, time.Second, 10*time.Millisecond)
+}
+
+func writeTestCert(t *testing.T, dir, cn string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	assert.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	assert.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	assert.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certFile, keyFile
+}
+
+// freeAddr return a local address nobody listen on.
+func freeAddr(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer l.Close()
+	return l.Addr().String()
+}
+
+// getTLS fetch path from addr over HTTPS, trusting any certificate.
+func getTLS(addr, path string) (string, error) {
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}
+	defer client.CloseIdleConnections()
+	resp, err := client.Get("https://" + addr + path)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	return resp.TLS.PeerCertificates[0].Subject.CommonName + " " + string(b), err
+}
+
+func TestRunTLS(t *testing.T) {
+	certFile, keyFile := writeTestCert(t, t.TempDir(), "files")
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	assert.NoError(t, err)
+
+	for name, run := range map[string]func(*Engine, string) error{
+		"files": func(r *Engine, addr string) error { return r.RunTLS(addr, certFile, keyFile) },
+		"config": func(r *Engine, addr string) error {
+			return r.RunTLSConfig(addr, &tls.Config{Certificates: []tls.Certificate{cert}})
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			r := New(func(e *Engine) { e.writer = io.Discard })
+			r.Get("/ping", func(c *Context) { c.String(200, "pong") })
+
+			addr := freeAddr(t)
+			done := make(chan error, 1)
+			go func() { done <- run(r, addr) }()
+
+			assert.Eventually(t, func() bool {
+				got, err := getTLS(addr, "/ping")
+				return err == nil && assert.Equal(t, "files pong", got)
+			}, time.Second, 10*time.Millisecond)
+
+			assert.NoError(t, r.Shutdown(context.Background()))
+			assert.ErrorIs(t, <-done, http.ErrServerClosed)
+		})
+	}
+}
+
+func TestListenAndGracefulTLS(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("interrupt signal not supported")
+	}
+	// keep the test process alive until ListenAndGracefulTLS catch the signal
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt)
+	defer signal.Stop(sig)
+
+	certFile, keyFile := writeTestCert(t, t.TempDir(), "graceful")
+	r := New(func(e *Engine) { e.writer = io.Discard })
+	entered, released := make(chan struct{}), make(chan struct{})
+	r.Get("/slow", func(c *Context) {
+		close(entered)
+		<-released
+		c.String(200, "done")
+	})
+
+	addr := freeAddr(t)
+	done := make(chan error, 1)
+	go func() { done <- r.ListenAndGracefulTLS(addr, certFile, keyFile) }()
+	assert.Eventually(t, func() bool {
+		conn, err := tls.Dial("tcp", addr, &tls.Config{InsecureSkipVerify: true})
+		if err == nil {
+			conn.Close()
+		}
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+
+	// the in-flight request is finished before the server stop
+	slow := make(chan string, 1)
+	go func() {
+		got, err := getTLS(addr, "/slow")
+		assert.NoError(t, err)
+		slow <- got
+	}()
+	<-entered
+
+	self, err := os.FindProcess(os.Getpid())
+	assert.NoError(t, err)
+	assert.NoError(t, self.Signal(os.Interrupt))
+	time.Sleep(50 * time.Millisecond)
+	select {
+	case err := <-done:
+		t.Fatalf("stopped before the request finished: %v", err)
+	default:
+	}
+
+	close(released)
+	assert.Equal(t, "graceful done", <-slow)
+	assert.NoError(t, <-done)
+	_, err = tls.Dial("tcp", addr, &tls.Config{InsecureSkipVerify: true})
+	assert.Error(t, err, "listener closed")
+}
+
+func TestRunUnix(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix sockets not supported")
+	}
+	// short directory, socket paths are limited to about 100 bytes
+	dir, err := os.MkdirTemp("", "glaze")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "app.sock")
+
+	// stale socket left by a crashed process
+	stale, err := net.Listen("unix", file)
+	assert.NoError(t, err)
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+
+	r := New(func(e *Engine) { e.writer = io.Discard })
+	r.Get("/ping", func(c *Context) { c.String(200, "pong") })
+	done := make(chan error, 1)
+	go func() { done <- r.RunUnix(file, 0o660) }()
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return new(net.Dialer).DialContext(ctx, "unix", file)
+		},
+	}}
+	defer client.CloseIdleConnections()
+	assert.Eventually(t, func() bool {
+		resp, err := client.Get("http://unix/ping")
+		if err != nil {
+			return false
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		return assert.Equal(t, "pong", string(b))
+	}, time.Second, 10*time.Millisecond)
+
+	info, err := os.Stat(file)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o660), info.Mode().Perm())
+
+	// a live socket is not stolen
+	err = New(func(e *Engine) { e.writer = io.Discard }).RunUnix(file, 0o660)
+	assert.ErrorContains(t, err, "in use by another process")
+
+	assert.NoError(t, r.Shutdown(context.Background()))
+	assert.ErrorIs(t, <-done, http.ErrServerClosed)
+
+	// other files are never deleted
+	plain := filepath.Join(dir, "data.txt")
+	assert.NoError(t, os.WriteFile(plain, []byte("keep"), 0o644))
+	err = New(func(e *Engine) { e.writer = io.Discard }).RunUnix(plain, 0o660)
+	assert.ErrorContains(t, err, "is not a socket")
+	assert.FileExists(t, plain)
+}
+
+func TestCertReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeTestCert(t, dir, "old")
+
+	rl, err := NewCertReloader(certFile, keyFile)
+	assert.NoError(t, err)
+	commonName := func() string {
+		cert, _ := rl.GetCertificate(nil)
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		assert.NoError(t, err)
+		return leaf.Subject.CommonName
+	}
+	assert.Equal(t, "old", commonName())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go rl.Watch(ctx, 10*time.Millisecond)
+
+	writeTestCert(t, dir, "new")
+	future := time.Now().Add(time.Minute)
+	os.Chtimes(certFile, future, future)
+	assert.Eventually(t, func() bool { return commonName() == "new" }, time.Second, 10*time.Millisecond)
+
+	// a broken file keep the current certificate
+	os.WriteFile(keyFile, []byte("garbage"), 0o600)
+	assert.Error(t, rl.Reload())
+	assert.Equal(t, "new", commonName())
+}
+
+// wsClientFrame build a masked client frame.
+func wsClientFrame(opcode byte, payload []byte) []byte {
+	frame := []byte{0x80 | opcode, 0x80 | byte(len(payload))}
+	mask := []byte{1, 2, 3, 4}
+	frame = append(frame, mask...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	return frame
+}
+
+func TestWebSocketEcho(t *testing.T) {
+	r := New()
+	r.Get("/ws", func(c *Context) {
+		ws, err := c.Upgrade(WSOptions{Subprotocols: []string{"chat"}})
+		if err != nil {
+			return
+		}
+		defer ws.Close(WSCloseNormal, "")
+		for {
+			typ, msg, err := ws.ReadMessage()
+			if err != nil {
+				return
+			}
+			ws.WriteMessage(typ, append([]byte("echo: "), msg...))
+		}
+	})
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	// plain request is rejected
+	resp, err := http.Get(srv.URL + "/ws")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(srv.URL, "http://"))
+	assert.NoError(t, err)
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(2 * time.Second))
+
+	fmt.Fprintf(conn, "GET /ws HTTP/1.1\r\nHost: %s\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n"+
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\n"+
+		"Sec-WebSocket-Protocol: v2, chat\r\n\r\n", srv.Listener.Addr())
+	br := bufio.NewReader(conn)
+	resp, err = http.ReadResponse(br, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+	assert.Equal(t, "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", resp.Header.Get("Sec-WebSocket-Accept"))
+	assert.Equal(t, "chat", resp.Header.Get("Sec-WebSocket-Protocol"))
+
+	// ping is answered with pong, then the text message is echoed
+	conn.Write(wsClientFrame(WSPing, []byte("hb")))
+	conn.Write(wsClientFrame(WSText, []byte("hello")))
+
+	head := make([]byte, 2)
+	io.ReadFull(br, head)
+	assert.Equal(t, []byte{0x80 | WSPong, 2}, head)
+	pong := make([]byte, 2)
+	io.ReadFull(br, pong)
+	assert.Equal(t, "hb", string(pong))
+
+	io.ReadFull(br, head)
+	assert.Equal(t, byte(0x80|WSText), head[0])
+	msg := make([]byte, head[1])
+	io.ReadFull(br, msg)
+	assert.Equal(t, "echo: hello", string(msg))
+
+	// close handshake
+	conn.Write(wsClientFrame(WSClose, []byte{0x03, 0xe8}))
+	io.ReadFull(br, head)
+	assert.Equal(t, []byte{0x80 | WSClose, 2}, head)
+}
+
+func TestValidate(t *testing.T) {
+	type address struct {
+		City string `json:"city" validate:"required"`
+	}
+	type user struct {
+		Name    string   `json:"name" validate:"required,min=3,max=10"`
+		Email   string   `json:"email" validate:"omitempty,email"`
+		Role    string   `json:"role" validate:"oneof=admin member"`
+		Tags    []string `json:"tags" validate:"max=2"`
+		Age     int      `json:"age" validate:"min=18"`
+		Address address  `json:"address"`
+	}
+
+	assert.NoError(t, Validate(&user{Name: "jalu", Role: "admin", Age: 20, Address: address{City: "Jakarta"}}))
+
+	err := Validate(user{Name: "ja", Email: "nope", Role: "root", Tags: []string{"a", "b", "c"}, Age: 10})
+	var verrs ValidationErrors
+	assert.True(t, errors.As(err, &verrs))
+	assert.Equal(t, map[string]string{
+		"name":         "must be at least 3 characters",
+		"email":        "must be a valid email address",
+		"role":         "must be one of [admin member]",
+		"tags":         "must be at most 2 items",
+		"age":          "must be at least 18",
+		"address.city": "is required",
+	}, verrs.Fields())
+	assert.Panics(t, func() {
+		Validate(struct {
+			A string `validate:"bogus"`
+		}{})
+	})
+}
+
+func TestBind(t *testing.T) {
+	type input struct {
+		ID      int           `path:"id"`
+		Force   bool          `query:"force"`
+		Tags    []string      `query:"tag"`
+		Timeout time.Duration `query:"timeout"`
+		Token   *string       `header:"X-Token"`
+		Name    string        `json:"name" form:"name" validate:"required"`
+	}
+	var got input
+	r := New()
+	r.Post("/users/:id", func(c *Context) {
+		got = input{}
+		if err := c.Bind(&got); err != nil {
+			c.String(http.StatusBadRequest, err.Error())
+		}
+	})
+
+	req := httptest.NewRequest("POST", "/users/7?force=true&tag=a&tag=b&timeout=2s", strings.NewReader(`{"name":"jalu"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Token", "secret")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 7, got.ID)
+	assert.True(t, got.Force)
+	assert.Equal(t, []string{"a", "b"}, got.Tags)
+	assert.Equal(t, 2*time.Second, got.Timeout)
+	assert.Equal(t, "secret", *got.Token)
+	assert.Equal(t, "jalu", got.Name)
+
+	// form body
+	req = httptest.NewRequest("POST", "/users/7", strings.NewReader("name=form"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "form", got.Name)
+
+	// bad path value, then validation failure
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("POST", "/users/x", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `path parameter "id"`)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("POST", "/users/7", nil))
+	assert.Equal(t, "name is required", w.Body.String())
+}
+
+type createdItem struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func (createdItem) StatusCode() int { return http.StatusCreated }
+
+func TestTypedHandler(t *testing.T) {
+	type createItem struct {
+		Name string `json:"name" validate:"required,max=5"`
+	}
+	errTaken := errors.New("name taken")
+
+	r := New()
+	r.MapError(errTaken, http.StatusConflict)
+	r.Post("/items", Handler(func(c *Context, in createItem) (createdItem, error) {
+		if in.Name == "taken" {
+			return createdItem{}, errTaken
+		}
+		if in.Name == "boom" {
+			return createdItem{}, errors.New("db down")
+		}
+		return createdItem{ID: 1, Name: in.Name}, nil
+	}))
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/items", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post(`{"name":"cup"}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.JSONEq(t, `{"id":1,"name":"cup"}`, w.Body.String())
+
+	w = post(`{"name":"teapots"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.JSONEq(t, `{"error":"validation failed","fields":{"name":"must be at most 5 characters"}}`, w.Body.String())
+
+	w = post(`{"name":`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = post(`{"name":"taken"}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.JSONEq(t, `{"error":"name taken"}`, w.Body.String())
+
+	w = post(`{"name":"boom"}`)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.JSONEq(t, `{"error":"Internal Server Error"}`, w.Body.String())
+
+	// pointer request and response
+	r.Get("/items/:id", Handler(func(c *Context, in *struct {
+		ID int `path:"id"`
+	}) (*createdItem, error) {
+		return &createdItem{ID: in.ID}, nil
+	}))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/items/9", nil))
+	assert.JSONEq(t, `{"id":9,"name":""}`, w.Body.String())
+}
+
+type testUserService struct{}
+
+type testUserInput struct {
+	ID int `path:"id" json:"id" validate:"required"`
+}
+
+func (testUserService) GetUser(c *Context, in testUserInput) (M, error) {
+	return M{"id": in.ID}, nil
+}
+func (testUserService) ListUsers(ctx context.Context) ([]string, error) {
+	return []string{"jalu"}, nil
+}
+func (testUserService) CreateUserByID(ctx context.Context, in *testUserInput) (M, error) {
+	if in.ID == 13 {
+		return nil, errors.New("unlucky")
+	}
+	return M{"created": in.ID}, nil
+}
+func (testUserService) Helper(s string) string { return s }
+func (testUserService) Internal(ctx context.Context) (M, error) {
+	return M{}, nil
+}
+func (testUserService) ServiceRoutes() map[string]string {
+	return map[string]string{"GetUser": "GET /users/:id", "Internal": "-"}
+}
+
+func TestRegisterService(t *testing.T) {
+	r := New()
+	RegisterService(r.Group("/rpc"), testUserService{})
+
+	var routes []string
+	for _, info := range r.RoutesInfo() {
+		routes = append(routes, info.Method+" "+info.Path)
+	}
+	assert.ElementsMatch(t, []string{"GET /rpc/users/:id", "GET /rpc/list-users", "POST /rpc/create-user-by-id"}, routes)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/rpc/users/4", nil))
+	assert.JSONEq(t, `{"id":4}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/rpc/list-users", nil))
+	assert.JSONEq(t, `["jalu"]`, w.Body.String())
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/rpc/create-user-by-id", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	assert.JSONEq(t, `{"created":2}`, post(`{"id":2}`).Body.String())
+	assert.Equal(t, http.StatusUnprocessableEntity, post(`{}`).Code)
+	assert.Equal(t, http.StatusInternalServerError, post(`{"id":13}`).Code)
+
+	assert.Equal(t, "get-user-by-id", kebabCase("GetUserByID"))
+	assert.Equal(t, "parse-html-page", kebabCase("ParseHTMLPage"))
+}
+
+func TestProxy(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Seen-Path", r.URL.Path)
+		w.Header().Set("X-Seen-Forwarded", r.Header.Get("X-Forwarded-For")+"|"+r.Header.Get("X-Forwarded-Host"))
+		w.Header().Set("X-Seen-Tenant", r.Header.Get("X-Tenant"))
+		fmt.Fprintf(w, "%s %s?%s", r.Method, r.URL.Path, r.URL.RawQuery)
+	}))
+	defer backend.Close()
+	target, _ := url.Parse(backend.URL + "/v2")
+
+	r := New()
+	r.Get("/users/:id", func(c *Context) {
+		c.Proxy(target, ProxyOptions{
+			Rewrite: func(c *Context, out *http.Request) { out.Header.Set("X-Tenant", "acme") },
+		})
+	})
+	api := r.Group("/api", func(c *Context) { c.Writer.Header().Set("X-Gateway", "glaze") })
+	api.Get("/billing/health", func(c *Context) { c.String(200, "local") })
+	api.Proxy("/billing", target)
+
+	req := httptest.NewRequest("GET", "/users/7?full=1", nil)
+	req.RemoteAddr = "10.1.2.3:5555"
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, "GET /v2/users/7?full=1", w.Body.String())
+	assert.Equal(t, "10.1.2.3|example.com", w.Header().Get("X-Seen-Forwarded"))
+	assert.Equal(t, "acme", w.Header().Get("X-Seen-Tenant"))
+
+	// prefix is stripped, any method, group middleware applied
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("DELETE", "/api/billing/invoices/3", nil))
+	assert.Equal(t, "DELETE /v2/invoices/3?", w.Body.String())
+	assert.Equal(t, "glaze", w.Header().Get("X-Gateway"))
+
+	// routes win over the mount, other paths are not proxied
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/api/billing/health", nil))
+	assert.Equal(t, "local", w.Body.String())
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("POST", "/api/billing/health", nil))
+	assert.Equal(t, "POST /v2/health?", w.Body.String(), "proxied, not 405")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/api/billingx", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// unreachable target
+	down, _ := url.Parse("http://127.0.0.1:1")
+	r.Get("/down", func(c *Context) { c.Proxy(down) })
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/down", nil))
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+}
+
+func TestDebugRoutes(t *testing.T) {
+	r := New()
+	r.UsePhase(PhasePreRouting, 0, func(c *Context) { c.Next() })
+	r.Meta("audit", true).Example(Example{Name: "found", Response: M{"id": 7}}).Get("/users/:id", func(c *Context) {})
+	r.DebugRoutes()
+
+	defer SetMode(Mode())
+	SetMode(DebugMode)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", DebugRoutesPath, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var report DebugReport
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, DebugMode, report.Mode)
+	assert.Len(t, report.Routes, 2)
+	assert.Equal(t, "/users/:id", report.Routes[1].Path)
+	assert.Equal(t, map[string]any{"audit": true}, report.Routes[1].Meta)
+	assert.Equal(t, []Example{{Name: "found", ContentType: MIME_JSON, Status: 200, Response: map[string]any{"id": 7.0}}}, report.Routes[1].Examples)
+	assert.Len(t, report.Middleware["preRouting"], 1)
+	assert.Equal(t, "10s", report.Config["readHeaderTimeout"])
+
+	// hidden in release mode unless protected
+	SetMode(ReleaseMode)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", DebugRoutesPath, nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	p := New().DebugRoutes(func(c *Context) {
+		if c.GetHeader("X-Admin") == "" {
+			c.Abort()
+			c.String(http.StatusForbidden, "forbidden")
+		}
+	})
+	w = httptest.NewRecorder()
+	p.ServeHTTP(w, httptest.NewRequest("GET", DebugRoutesPath, nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	req := httptest.NewRequest("GET", DebugRoutesPath, nil)
+	req.Header.Set("X-Admin", "1")
+	w = httptest.NewRecorder()
+	p.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+type testDBKey struct{}
+type testRepoKey struct{}
+
+type testRepo struct {
+	db     *strings.Builder
+	tenant string
+}
+
+func TestProvide(t *testing.T) {
+	builds, repos := 0, 0
+	r := New()
+	r.Provide(testDBKey{}, func() (any, error) {
+		builds++
+		return &strings.Builder{}, nil
+	})
+	r.ProvideScoped(testRepoKey{}, func(c *Context) (any, error) {
+		repos++
+		db, err := Resolve[*strings.Builder](c, testDBKey{})
+		return &testRepo{db: db, tenant: c.GetHeader("X-Tenant")}, err
+	})
+	r.Get("/", func(c *Context) {
+		a, _ := Resolve[*testRepo](c, testRepoKey{})
+		b, _ := Resolve[*testRepo](c, testRepoKey{})
+		_, err := c.Resolve("missing")
+		_, typeErr := Resolve[int](c, testDBKey{})
+		c.String(200, fmt.Sprint(a == b, " ", a.tenant, " ", errors.Is(err, ErrNoProvider), " ", typeErr != nil))
+	})
+
+	for _, tenant := range []string{"acme", "globex"} {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("X-Tenant", tenant)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, "true "+tenant+" true true", w.Body.String())
+	}
+	assert.Equal(t, 1, builds)
+	assert.Equal(t, 2, repos)
+	assert.Panics(t, func() { r.Provide(testDBKey{}, func() (any, error) { return nil, nil }) }, "duplicate")
+	assert.PanicsWithValue(t, "glaze: nil constructor for new", func() { r.Provide("new", nil) })
+	assert.PanicsWithValue(t, "glaze: nil constructor for new", func() { r.ProvideScoped("new", nil) })
+}
+
+func TestContextGo(t *testing.T) {
+	rep := &testReporter{}
+	r := New(WithReporter(rep), WithErrorOutput(io.Discard))
+
+	var canceled atomic.Bool
+	started := make(chan struct{})
+	r.Get("/users/:id", func(c *Context) {
+		c.Set("user", "jalu")
+		c.Go(func(ctx context.Context) {
+			close(started)
+			<-ctx.Done()
+			canceled.Store(true)
+		})
+		c.Go(func(ctx context.Context) { panic("background boom") })
+		cp := c.Copy()
+		user, _ := cp.Get("user")
+		c.String(200, cp.Param("id")+" "+user.(string))
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/users/7", nil))
+	assert.Equal(t, "7 jalu", w.Body.String())
+	<-started
+
+	// the request end does not cancel the goroutine, shutdown wait then cancel it
+	assert.False(t, canceled.Load())
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, r.Shutdown(ctx), context.DeadlineExceeded)
+	assert.NoError(t, r.Shutdown(context.Background()))
+	assert.True(t, canceled.Load())
+	assert.Equal(t, []any{"background boom"}, rep.panics)
+}
+
+type testRenderer struct{ calls []string }
+
+func (r *testRenderer) Render(ctx context.Context, w io.Writer, name string, data any) error {
+	r.calls = append(r.calls, name)
+	if name == "broken" {
+		io.WriteString(w, "partial")
+		return errors.New("missing field")
+	}
+	_, err := fmt.Fprintf(w, "<p>%s %v</p>", name, data)
+	return err
+}
+
+func TestHTMLRenderer(t *testing.T) {
+	fsys := fstest.MapFS{
+		"views/index.html": {Data: []byte(`{{define "index.html"}}<h1>{{upper .title}}</h1>{{end}}`)},
+	}
+	r := New().SetFuncMap(template.FuncMap{"upper": strings.ToUpper}).LoadHTMLFS(fsys, "views/*.html")
+	r.Get("/", func(c *Context) { c.HTML(http.StatusOK, "index.html", M{"title": "<home>"}) })
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, "<h1>&lt;HOME&gt;</h1>", w.Body.String())
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+
+	// custom renderer, errors send the default 500
+	tr := &testRenderer{}
+	r = New(WithHTMLRenderer(tr), WithErrorOutput(io.Discard))
+	r.Get("/page", func(c *Context) { c.HTML(http.StatusCreated, "page", 42) })
+	r.Get("/broken", func(c *Context) { c.HTML(http.StatusOK, "broken", nil) })
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/page", nil))
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "<p>page 42</p>", w.Body.String())
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/broken", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "partial")
+	assert.Equal(t, []string{"page", "broken"}, tr.calls)
+
+	assert.Panics(t, func() {
+		e := New()
+		e.Get("/", func(c *Context) { c.HTML(200, "x", nil) })
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	})
+}
+
+func TestViewData(t *testing.T) {
+	fsys := fstest.MapFS{"page.html": {Data: []byte(`{{.user}}|{{.csrf}}|{{.title}}`)}}
+	r := New().LoadHTMLFS(fsys, "page.html")
+	r.Use(func(c *Context) {
+		c.SetViewData("user", "jalu")
+		c.SetViewData("csrf", "tok")
+		c.SetViewData("title", "default")
+		c.Next()
+	})
+	r.Get("/a", func(c *Context) { c.HTML(200, "page.html", nil) })
+	r.Get("/b", func(c *Context) { c.HTML(200, "page.html", M{"title": "Orders", "csrf": "own"}) })
+	r.Get("/c", func(c *Context) { c.HTML(200, "page.html", map[string]any{"title": "Plain map"}) })
+
+	for path, want := range map[string]string{
+		"/a": "jalu|tok|default",
+		"/b": "jalu|own|Orders",
+		"/c": "jalu|tok|Plain map",
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		assert.Equal(t, want, w.Body.String(), path)
+	}
+}
+
+func TestStaticDirectory(t *testing.T) {
+	fsys := fstest.MapFS{
+		"index.html":         {Data: []byte("home")},
+		"docs/b.txt":         {Data: []byte("bbbb")},
+		"docs/a.txt":         {Data: []byte("a")},
+		"docs/.secret":       {Data: []byte("x")},
+		"docs/sub/c.txt":     {Data: []byte("c")},
+		"docs/sub/index.htm": {Data: []byte("-")},
+		".env":               {Data: []byte("KEY=1")},
+	}
+	r := New()
+	r.StaticFS("/files", http.FS(fsys), StaticConfig{Browse: true, CacheControl: "no-cache"})
+	r.StaticFS("/plain", http.FS(fsys))
+	r.Get("/files/status", func(c *Context) { c.String(200, "route") })
+
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+
+	w := serve("GET", "/files/")
+	assert.Equal(t, "home", w.Body.String())
+	assert.Equal(t, "no-cache", w.Header().Get("Cache-Control"))
+	assert.Equal(t, "bbbb", serve("GET", "/files/docs/b.txt").Body.String())
+	assert.Equal(t, "route", serve("GET", "/files/status").Body.String())
+
+	// redirect, listing with hidden files filtered
+	w = serve("GET", "/files/docs")
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "/files/docs/", w.Header().Get("Location"))
+
+	w = serve("GET", "/files/docs/")
+	body := w.Body.String()
+	assert.Contains(t, body, `<a href="./sub/">sub/</a>`)
+	assert.NotContains(t, body, ".secret")
+	assert.Less(t, strings.Index(body, "sub/"), strings.Index(body, "a.txt"))
+	assert.Less(t, strings.Index(body, "a.txt"), strings.Index(body, "b.txt"))
+
+	body = serve("GET", "/files/docs/?sort=size&order=desc").Body.String()
+	assert.Less(t, strings.Index(body, "b.txt"), strings.Index(body, "a.txt"))
+
+	assert.Equal(t, http.StatusNotFound, serve("GET", "/files/.env").Code)
+	assert.Equal(t, http.StatusNotFound, serve("GET", "/files/docs/.secret").Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, serve("POST", "/files/index.html").Code)
+
+	// without Browse, directories without index are not listed
+	assert.Equal(t, http.StatusNotFound, serve("GET", "/plain/docs/").Code)
+	assert.Equal(t, "c", serve("GET", "/plain/docs/sub/c.txt").Body.String())
+}
+
+func TestStaticSPAFallback(t *testing.T) {
+	fsys := fstest.MapFS{
+		"index.html":    {Data: []byte("<app>")},
+		"assets/app.js": {Data: []byte("js")},
+	}
+	r := New()
+	r.Get("/api/users", func(c *Context) { c.String(200, "users") })
+	r.StaticFS("/", http.FS(fsys), StaticConfig{Fallback: "index.html", FallbackExclude: []string{"/api"}})
+
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+
+	for _, p := range []string{"/", "/orders", "/orders/42/edit"} {
+		w := serve("GET", p)
+		assert.Equal(t, http.StatusOK, w.Code, p)
+		assert.Equal(t, "<app>", w.Body.String(), p)
+	}
+	assert.Equal(t, "js", serve("GET", "/assets/app.js").Body.String())
+	assert.Equal(t, http.StatusNotFound, serve("GET", "/assets/missing.js").Code)
+	assert.Equal(t, "users", serve("GET", "/api/users").Body.String())
+	assert.Equal(t, http.StatusNotFound, serve("GET", "/api/orders").Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, serve("POST", "/api/users").Code)
+}
+
+type testMemStorage struct {
+	files   map[string]string
+	deleted []string
+}
+
+func (s *testMemStorage) Put(ctx context.Context, key string, r io.Reader, size int64, ctype string) (string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	s.files[key] = string(data)
+	return "mem://" + key, nil
+}
+
+func (s *testMemStorage) Delete(ctx context.Context, key string) error {
+	s.deleted = append(s.deleted, key)
+	delete(s.files, key)
+	return nil
+}
+
+func TestUpload(t *testing.T) {
+	type newPost struct {
+		ID     int            `path:"id"`
+		Title  string         `form:"title" validate:"required"`
+		Cover  *UploadedFile  `file:"cover,required,accept=image/png"`
+		Photos []UploadedFile `file:"photos,max=2"`
+	}
+	png := "\x89PNG\r\n\x1a\n" + strings.Repeat("x", 20)
+
+	store := &testMemStorage{files: map[string]string{}}
+	n := 0
+	cfg := UploadConfig{
+		Storage:     store,
+		MaxFileSize: 64,
+		Key: func(c *Context, field, filename string) string {
+			n++
+			return fmt.Sprintf("%s-%d", field, n)
+		},
+	}
+	var got newPost
+	r := New()
+	r.Post("/posts/:id", func(c *Context) {
+		got = newPost{}
+		if err := c.Upload(&got, cfg); err != nil {
+			c.String(http.StatusBadRequest, err.Error())
+		}
+	})
+
+	send := func(build func(w *multipart.Writer)) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		build(mw)
+		mw.Close()
+		req := httptest.NewRequest("POST", "/posts/3", &body)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	file := func(mw *multipart.Writer, field, name, content string) {
+		fw, _ := mw.CreateFormFile(field, name)
+		io.WriteString(fw, content)
+	}
+
+	w := send(func(mw *multipart.Writer) {
+		mw.WriteField("title", "Trip")
+		file(mw, "cover", "c.png", png)
+		file(mw, "photos", "a.txt", "hello")
+		file(mw, "photos", "b.txt", "world")
+	})
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, 3, got.ID)
+	assert.Equal(t, "Trip", got.Title)
+	assert.Equal(t, "image/png", got.Cover.ContentType)
+	assert.Equal(t, "mem://cover-1", got.Cover.Location)
+	assert.Equal(t, int64(len(png)), got.Cover.Size)
+	assert.Len(t, got.Photos, 2)
+	assert.Equal(t, "world", store.files[got.Photos[1].Key])
+
+	// a failure roll back the stored files
+	w = send(func(mw *multipart.Writer) {
+		mw.WriteField("title", "Trip")
+		file(mw, "cover", "c.png", png)
+		file(mw, "photos", "big.txt", strings.Repeat("y", 100))
+	})
+	assert.Equal(t, "photos must be at most 64 bytes", w.Body.String())
+	assert.Equal(t, []string{"cover-4", "photos-5"}, store.deleted)
+
+	w = send(func(mw *multipart.Writer) { file(mw, "cover", "c.txt", "plain text") })
+	assert.Equal(t, "cover must be of type [image/png]", w.Body.String())
+
+	w = send(func(mw *multipart.Writer) { mw.WriteField("title", "x") })
+	assert.Equal(t, "cover is required", w.Body.String())
+
+	w = send(func(mw *multipart.Writer) {
+		file(mw, "cover", "c.png", png)
+		for i := 0; i < 3; i++ {
+			file(mw, "photos", "p.txt", "p")
+		}
+	})
+	assert.Equal(t, "photos must be at most 2 items", w.Body.String())
+
+	w = send(func(mw *multipart.Writer) { file(mw, "cover", "c.png", png) })
+	assert.Equal(t, "title is required", w.Body.String())
+}
+
+func TestDevReload(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "index.html")
+	assert.NoError(t, os.WriteFile(file, []byte(`v1`), 0o644))
+
+	r := New(WithErrorOutput(io.Discard)).LoadHTMLGlob(filepath.Join(dir, "*.html"))
+	r.Get("/", func(c *Context) { c.HTML(http.StatusOK, "index.html", nil) })
+	render := func() string {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+		return w.Body.String()
+	}
+	assert.Equal(t, "v1", render())
+
+	var bundle atomic.Int32
+	locales := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(locales, "en.json"), []byte(`{}`), 0o644))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go r.watchDev(ctx, 10*time.Millisecond, []DevWatch{*r.htmlWatch, {
+		Name:   "locales",
+		FS:     os.DirFS(locales),
+		Reload: func() error { bundle.Add(1); return nil },
+	}})
+	time.Sleep(30 * time.Millisecond)
+
+	later := time.Now().Add(time.Hour)
+	assert.NoError(t, os.WriteFile(file, []byte(`v2`), 0o644))
+	assert.NoError(t, os.Chtimes(file, later, later))
+	assert.Eventually(t, func() bool { return render() == "v2" }, time.Second, 10*time.Millisecond)
+
+	// a broken template keep the previous version; it is renamed into place
+	// so the watcher never see the truncated file
+	next := filepath.Join(dir, "next")
+	assert.NoError(t, os.WriteFile(next, []byte(`{{`), 0o644))
+	later = later.Add(time.Hour)
+	assert.NoError(t, os.Chtimes(next, later, later))
+	assert.NoError(t, os.Rename(next, file))
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, "v2", render())
+
+	assert.NoError(t, os.WriteFile(filepath.Join(locales, "fr.json"), []byte(`{}`), 0o644))
+	assert.Eventually(t, func() bool { return bundle.Load() == 1 }, time.Second, 10*time.Millisecond)
+}
+
+func TestLoadHTMLGlobNested(t *testing.T) {
+	dir := t.TempDir()
+	for _, sub := range []string{"users", "orders"} {
+		assert.NoError(t, os.Mkdir(filepath.Join(dir, sub), 0o755))
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, sub, sub+".html"), []byte(sub+" page"), 0o644))
+	}
+
+	r := New().LoadHTMLGlob(filepath.Join(dir, "*", "*.html"))
+	r.Get("/:name", func(c *Context) { c.HTML(http.StatusOK, c.Param("name")+".html", nil) })
+	for _, name := range []string{"users", "orders"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", "/"+name, nil))
+		assert.Equal(t, name+" page", w.Body.String())
+	}
+
+	for pattern, want := range map[string][2]string{
+		"*.html":            {".", "*.html"},
+		"views/*.html":      {"views", "*.html"},
+		"views/*/*.html":    {"views", "*/*.html"},
+		"/srv/views/*.html": {"/srv/views", "*.html"},
+		"/*.html":           {"/", "*.html"},
+		"views/[ab]/x.html": {"views", "[ab]/x.html"},
+	} {
+		dir, rest := splitGlob(pattern)
+		assert.Equal(t, want, [2]string{filepath.ToSlash(dir), rest}, pattern)
+	}
+}
+
+func TestNegotiateFormatQuality(t *testing.T) {
+	for accept, want := range map[string]string{
+		"":                                       MIME_HTML,
+		"application/json":                       MIME_JSON,
+		"*/*, application/json;q=0":              MIME_HTML,
+		"application/json;q=0, */*":              MIME_HTML,
+		"text/*;q=0, */*":                        MIME_JSON,
+		"*/*;q=0":                                "",
+		"text/html;q=0.5, application/*":         MIME_JSON,
+		`text/html;level="1,2";q=0.9, */*;q=0.1`: MIME_HTML,
+		"application/problem+json":               MIME_JSON,
+	} {
+		assert.Equal(t, want, negotiateFormat(accept, MIME_HTML, MIME_JSON), accept)
+	}
+}
+
+func TestVersioned(t *testing.T) {
+	r := New()
+	handler := func(name string) HandlerFunc {
+		return func(c *Context) { c.JSON(http.StatusOK, M{"handler": name, "version": c.APIVersion()}) }
+	}
+	r.Get("/users", Versioned(VersionConfig{Vendor: "myapp", Default: "1"}, map[string]HandlerFunc{
+		"1": handler("v1"),
+		"2": handler("v2"),
+	}))
+
+	do := func(accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/users", nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("")
+	assert.JSONEq(t, `{"handler":"v1","version":"1"}`, w.Body.String())
+	assert.Equal(t, "Accept", w.Header().Get("Vary"))
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+
+	w = do("application/vnd.myapp.v2+json")
+	assert.JSONEq(t, `{"handler":"v2","version":"2"}`, w.Body.String())
+	assert.Equal(t, "application/vnd.myapp.v2+json", w.Header().Get("Content-Type"))
+
+	w = do("application/vnd.myapp+json; version=2")
+	assert.Equal(t, "application/vnd.myapp.v2+json", w.Header().Get("Content-Type"))
+
+	w = do("application/vnd.myapp.v1+json;q=0.5, application/vnd.myapp.v2+json")
+	assert.JSONEq(t, `{"handler":"v2","version":"2"}`, w.Body.String())
+
+	// unknown versions fall back to the next acceptable one, or 406
+	w = do("application/vnd.myapp.v9+json, application/vnd.myapp.v1+json;q=0.1")
+	assert.JSONEq(t, `{"handler":"v1","version":"1"}`, w.Body.String())
+	w = do("application/vnd.myapp.v9+json")
+	assert.Equal(t, http.StatusNotAcceptable, w.Code)
+
+	w = do("application/vnd.myapp+json")
+	assert.JSONEq(t, `{"handler":"v1","version":"1"}`, w.Body.String(), "no version is the default")
+
+	w = do("application/vnd.myappx.v2+json, application/json")
+	assert.JSONEq(t, `{"handler":"v1","version":"1"}`, w.Body.String())
+
+	assert.Panics(t, func() { Versioned(VersionConfig{Vendor: "myapp", Default: "3"}, nil) })
+}
+
+func TestCompress(t *testing.T) {
+	r := New()
+	r.Use(Compress(CompressConfig{MinLength: 64, ExcludePaths: []string{"/raw"}}))
+	big := strings.Repeat("glaze ", 100)
+	r.Get("/big", func(c *Context) { c.String(http.StatusOK, big) })
+	r.Get("/small", func(c *Context) { c.String(http.StatusOK, "tiny") })
+	r.Get("/raw", func(c *Context) { c.String(http.StatusOK, big) })
+	r.Get("/png", func(c *Context) {
+		c.Writer.Header().Set("Content-Type", "image/png")
+		c.String(http.StatusOK, big)
+	})
+
+	do := func(path, encoding string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Accept-Encoding", encoding)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("/big", "br;q=1, gzip;q=0.8, deflate;q=0.5")
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+	zr, err := gzip.NewReader(w.Body)
+	assert.NoError(t, err)
+	body, _ := io.ReadAll(zr)
+	assert.Equal(t, big, string(body))
+
+	w = do("/big", "deflate")
+	assert.Equal(t, "deflate", w.Header().Get("Content-Encoding"))
+	body, _ = io.ReadAll(flate.NewReader(w.Body))
+	assert.Equal(t, big, string(body))
+
+	w = do("/big", "gzip;q=0")
+	assert.Equal(t, "", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, big, w.Body.String())
+
+	for _, path := range []string{"/small", "/raw", "/png"} {
+		w = do(path, "gzip")
+		assert.Equal(t, "", w.Header().Get("Content-Encoding"), path)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+	assert.Equal(t, "tiny", do("/small", "gzip").Body.String())
+}
+
+func TestStreamCompressed(t *testing.T) {
+	r := New()
+	r.Use(Compress())
+	var flushed []string
+	rec := httptest.NewRecorder()
+	r.Get("/stream", func(c *Context) {
+		n := 0
+		c.Stream(func(w io.Writer) bool {
+			n++
+			fmt.Fprintf(w, "chunk%d\n", n)
+			return n < 2
+		})
+		// each flush reach the client, decodable before the end of the stream
+		zr, err := gzip.NewReader(bytes.NewReader(rec.Body.Bytes()))
+		assert.NoError(t, err)
+		partial, _ := io.ReadAll(zr)
+		flushed = append(flushed, string(partial))
+	})
+	r.Get("/events", func(c *Context) {
+		c.SSEvent("tick", M{"n": 1})
+		c.SSEvent("", "line1\nline2")
+	})
+
+	req := httptest.NewRequest("GET", "/stream", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	r.ServeHTTP(rec, req)
+	assert.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+	assert.Equal(t, []string{"chunk1\nchunk2\n"}, flushed)
+	assert.True(t, rec.Flushed)
+
+	req = httptest.NewRequest("GET", "/events", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, "", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, MIME_EVENT_STREAM, w.Header().Get("Content-Type"))
+	assert.Equal(t, "event: tick\ndata: {\"n\":1}\n\ndata: line1\ndata: line2\n\n", w.Body.String())
+}
+
+func TestHTTPError(t *testing.T) {
+	errUserNotFound := NewError(http.StatusNotFound, "user_not_found", "user not found")
+	errDB := errors.New("connection reset")
+
+	r := New()
+	r.Use(ErrorHandler())
+	r.Get("/users/:id", func(c *Context) {
+		c.Error(fmt.Errorf("load: %w", errUserNotFound.WithDetails(M{"id": c.Param("id")}).Wrap(errDB)))
+	})
+	r.Get("/typed", Handler(func(c *Context, _ struct{}) (M, error) {
+		return nil, NewError(http.StatusConflict, "", "")
+	}))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/users/7", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.JSONEq(t, `{"error":"user not found","code":"user_not_found","details":{"id":"7"}}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/typed", nil))
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.JSONEq(t, `{"error":"Conflict"}`, w.Body.String())
+
+	err := errUserNotFound.WithDetails(1).Wrap(errDB)
+	assert.ErrorIs(t, err, errUserNotFound)
+	assert.ErrorIs(t, err, errDB)
+	assert.Nil(t, errUserNotFound.Details, "sentinel not modified")
+	assert.Equal(t, "404 user_not_found: user not found: connection reset", err.Error())
+
+	// mappings take precedence
+	r.MapError(errUserNotFound, http.StatusGone)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/users/7", nil))
+	assert.Equal(t, http.StatusGone, w.Code)
+}
+
+func TestLocalizedValidation(t *testing.T) {
+	bundle := NewBundle("en")
+	assert.NoError(t, bundle.LoadFS(fstest.MapFS{"fr.json": {Data: []byte(`{
+		"validation": {
+			"failed": "validation échouée",
+			"required": "est obligatoire",
+			"min": {"string": "doit contenir au moins {param} caractères", "array": "au moins {param} éléments"}
+		},
+		"errors": {"quota_exceeded": "quota dépassé"}
+	}`)}}, "."))
+
+	type input struct {
+		Name string   `json:"name" validate:"required,min=3"`
+		Tags []string `json:"tags" validate:"min=1"`
+		Role string   `json:"role" validate:"oneof=admin member"`
+	}
+	r := New()
+	r.Use(I18n(bundle), ErrorHandler())
+	r.Post("/users", Handler(func(c *Context, in input) (M, error) { return M{}, nil }))
+	r.Get("/quota", func(c *Context) {
+		c.Error(NewError(http.StatusTooManyRequests, "quota_exceeded", "quota exceeded"))
+	})
+
+	do := func(method, path, body, lang string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept-Language", lang)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("POST", "/users", `{"name":"Al","role":"root"}`, "fr-FR,fr;q=0.9")
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.JSONEq(t, `{"error":"validation échouée","fields":{
+		"name":"doit contenir au moins 3 caractères",
+		"tags":"au moins 1 éléments",
+		"role":"must be one of [admin member]"}}`, w.Body.String())
+
+	w = do("POST", "/users", `{}`, "en")
+	assert.JSONEq(t, `{"error":"validation failed","fields":{
+		"name":"is required","tags":"must be at least 1 items","role":"must be one of [admin member]"}}`, w.Body.String())
+
+	assert.JSONEq(t, `{"error":"quota dépassé","code":"quota_exceeded"}`, do("GET", "/quota", "", "fr").Body.String())
+	assert.JSONEq(t, `{"error":"quota exceeded","code":"quota_exceeded"}`, do("GET", "/quota", "", "de").Body.String())
+
+	// custom english template
+	r = New(WithValidationMessage("oneof", "must be {param}, not {value}"))
+	r.Use(I18n(bundle), ErrorHandler())
+	r.Post("/users", Handler(func(c *Context, in input) (M, error) { return M{}, nil }))
+	w = do("POST", "/users", `{"name":"Alice","tags":["a"],"role":"root"}`, "fr")
+	assert.JSONEq(t, `{"error":"validation échouée","fields":{"role":"must be admin member, not root"}}`, w.Body.String())
+}
+
+func TestPoll(t *testing.T) {
+	r := New()
+	source := make(chan string, 1)
+	r.Get("/poll", func(c *Context) {
+		Poll(c.Request.Context(), c, 50*time.Millisecond, source, func(msg string) {
+			c.String(http.StatusOK, msg)
+		})
+	})
+
+	source <- "hello"
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/poll", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "hello", w.Body.String())
+
+	// wait expire
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/poll", nil))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	// client gone, nothing written
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/poll", nil).WithContext(ctx))
+	assert.False(t, w.Flushed)
+	assert.Empty(t, w.Body.String())
+	assert.Equal(t, http.StatusOK, w.Code, "recorder default, no WriteHeader")
+
+	// shutdown release the pollers
+	start := time.Now()
+	r.beginDrain()
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/poll", nil))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Less(t, time.Since(start), 40*time.Millisecond)
+}
+
+func TestPathPolicy(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "app.js"), []byte("js"), 0o644))
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "evil.com"), 0o755))
+
+	r := New()
+	r.Get("/files/:name", func(c *Context) { c.String(http.StatusOK, c.Param("name")) })
+	r.Static("/assets", dir)
+
+	do := func(e *Engine, target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		e.ServeHTTP(w, httptest.NewRequest("GET", target, nil))
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, do(r, "/files/report.pdf").Code)
+	assert.Equal(t, http.StatusOK, do(r, "//files//report.pdf").Code, "empty segments ignored")
+	for _, target := range []string{
+		"/files/%2e%2e",
+		"/files/..",
+		"/assets/%2e%2e/%2e%2e/etc/passwd",
+		"/assets/./app.js",
+		"/files/a%00b",
+		"/files/a%2Fb",
+		"/files/a%2fb",
+		"/files/a%5cb",
+		"/files/a%5Cb",
+	} {
+		assert.Equal(t, http.StatusBadRequest, do(r, target).Code, target)
+	}
+	assert.Equal(t, http.StatusRequestURITooLong, do(r, "/files/"+strings.Repeat("a", 2000)).Code)
+
+	lax := New(WithPathPolicy(PathPolicy{AllowEncodedSlash: true, MaxSegmentLength: 4096}))
+	lax.Get("/files/:dir/:name", func(c *Context) { c.String(http.StatusOK, c.Param("dir")+"|"+c.Param("name")) })
+	assert.Equal(t, "a|b", do(lax, "/files/a%2Fb").Body.String())
+	assert.Equal(t, http.StatusOK, do(lax, "/files/x/a%5Cb").Code)
+	assert.Equal(t, http.StatusOK, do(lax, "/files/x/"+strings.Repeat("a", 2000)).Code)
+
+	// static: a single leading slash in directory redirects
+	w := do(r, "/assets/evil.com")
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "/assets/evil.com/", w.Header().Get("Location"))
+	assert.Equal(t, "js", do(r, "/assets/app.js").Body.String())
+}
+
+func FuzzSplitClean(f *testing.F) {
+	for _, seed := range []string{"", "/", "//a//b/", "/users/:id", "a/b/c"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, p string) {
+		parts := splitClean(p)
+		for _, part := range parts {
+			if part == "" || strings.Contains(part, "/") {
+				t.Fatalf("splitClean(%q) = %q", p, parts)
+			}
+		}
+		if strings.Join(parts, "") != strings.ReplaceAll(p, "/", "") {
+			t.Fatalf("splitClean(%q) lost characters: %q", p, parts)
+		}
+	})
+}
+
+func FuzzFindRoute(f *testing.F) {
+	r := New()
+	for _, route := range []string{"/", "/users", "/users/:id", "/users/:id/posts/:post", "/static/app.js"} {
+		r.Get(route, func(c *Context) {})
+	}
+	for _, seed := range []string{"/users/7", "/users/7/posts/9", "//users//7", "/static/../users", "/users/%2e%2e", "/\x00"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, p string) {
+		var params Params
+		n := r.findRoute("GET", p, &params)
+		if n == nil || n.handlers == nil {
+			return
+		}
+		if got, want := len(splitClean(n.route.Path)), len(splitClean(p)); got != want {
+			t.Fatalf("%q matched %s", p, n.route.Path)
+		}
+		for _, param := range params {
+			if param.Value == "" || strings.Contains(param.Value, "/") {
+				t.Fatalf("%q: invalid param %q", p, param.Value)
+			}
+		}
+
+		// requests accepted by the path policy carry no dot segment
+		req := &http.Request{Method: "GET", URL: &url.URL{Path: p}}
+		if defaultPathPolicy.check(req) == 0 {
+			for _, part := range splitClean(p) {
+				if part == "." || part == ".." {
+					t.Fatalf("%q: dot segment accepted", p)
+				}
+			}
+		}
+	})
+}
+
+func TestRemoteIP(t *testing.T) {
+	r := New()
+	assert.NoError(t, r.SetTrustedProxies([]string{"10.0.0.0/8"}))
+	r.Get("/ip", func(c *Context) {
+		c.String(200, c.ClientIP()+" "+c.RemoteIP()+" "+c.RemotePort())
+	})
+
+	do := func(addr string) string {
+		req := httptest.NewRequest("GET", "/ip", nil)
+		req.RemoteAddr = addr
+		req.Header.Set("X-Forwarded-For", "203.0.113.7")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Body.String()
+	}
+	assert.Equal(t, "203.0.113.7 10.0.0.5 4000", do("10.0.0.5:4000"))
+	assert.Equal(t, "2001:db8::1 2001:db8::1 443", do("[2001:db8::1]:443"))
+	assert.Equal(t, "192.0.2.1 192.0.2.1 ", do("192.0.2.1"))
+}
+
+func TestResponseShortcuts(t *testing.T) {
+	r := New()
+	r.Get("/text", func(c *Context) { c.Text(http.StatusAccepted, "hello %s, %d", "ann", 3) })
+	r.Get("/html", func(c *Context) { c.HTMLString(http.StatusOK, "<b>hi</b>") })
+	api := r.Group("/api", func(c *Context) { c.SetAccepted(MIME_JSON) })
+	api.Get("/format", func(c *Context) { c.String(http.StatusOK, c.NegotiateFormat(MIME_HTML, MIME_JSON)) })
+	api.Get("/fail", func(c *Context) { c.defaultResponse(http.StatusInternalServerError) })
+	r.Get("/format", func(c *Context) { c.String(http.StatusOK, c.NegotiateFormat(MIME_HTML, MIME_JSON)) })
+
+	do := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Accept", "text/html,*/*;q=0.8")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("/text")
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, "hello ann, 3", w.Body.String())
+	assert.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"))
+
+	w = do("/html")
+	assert.Equal(t, "<b>hi</b>", w.Body.String())
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+
+	assert.Equal(t, MIME_HTML, do("/format").Body.String())
+	assert.Equal(t, MIME_JSON, do("/api/format").Body.String())
+	w = do("/api/fail")
+	assert.JSONEq(t, `{"error":"Internal Server Error"}`, w.Body.String())
+}
+
+func traceRequest(c *Context) { c.Next() }
+func requireAuth(c *Context)  { c.Next() }
+func requireAdmin(c *Context) { c.Next() }
+func listUsers(c *Context)    {}
+
+func TestGroupHandlers(t *testing.T) {
+	r := New()
+	r.UsePhase(PhasePreRouting, 0, traceRequest)
+	r.Use(requireAuth)
+	admin := r.Group("/admin", requireAdmin)
+	admin.Get("/users/:id", listUsers)
+	public := r.Group("/public")
+
+	assert.Equal(t, []string{
+		"github.com/nrhox/glaze.traceRequest",
+		"github.com/nrhox/glaze.requireAuth",
+		"github.com/nrhox/glaze.requireAdmin",
+	}, admin.Handlers())
+	assert.NotContains(t, strings.Join(public.Handlers(), " "), "requireAdmin")
+
+	names, ok := r.RouteHandlers("GET", "/admin/users/:id")
+	assert.True(t, ok)
+	assert.Equal(t, "github.com/nrhox/glaze.listUsers", names[len(names)-1])
+	assert.Contains(t, names, "github.com/nrhox/glaze.requireAdmin")
+
+	_, ok = r.RouteHandlers("GET", "/admin/users/7")
+	assert.False(t, ok, "registered path only")
+	_, ok = r.RouteHandlers("POST", "/admin/users/:id")
+	assert.False(t, ok)
+}
+
+func TestMultipartLimits(t *testing.T) {
+	dir := t.TempDir()
+	r := New()
+	handler := func(c *Context) {
+		if _, err := c.FormFile("file"); err != nil {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		c.String(http.StatusOK, "ok")
+	}
+	r.Post("/upload", handler)
+	admin := r.Group("/admin").MultipartLimits(MultipartLimits{MaxFileSize: 1 << 20})
+	admin.Post("/upload", handler)
+	small := r.Group("/small").MultipartLimits(MultipartLimits{MaxFileSize: 8, MaxBodySize: 1 << 10})
+	small.Post("/upload", handler)
+	imports := admin.MultipartLimits(MultipartLimits{TempDir: dir})
+	imports.Post("/import", func(c *Context) {
+		var in struct {
+			File *UploadedFile `file:"file,required"`
+		}
+		if err := c.Upload(&in, UploadConfig{}); err != nil {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		c.String(http.StatusOK, in.File.Location)
+	})
+
+	send := func(path string, size int) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		fw, _ := mw.CreateFormFile("file", "data.csv")
+		fw.Write(bytes.Repeat([]byte("a"), size))
+		mw.Close()
+		req := httptest.NewRequest("POST", path, &body)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, send("/upload", 100).Code)
+	assert.Equal(t, http.StatusOK, send("/admin/upload", 100).Code)
+	w := send("/small/upload", 100)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "file must be at most 8 bytes", w.Body.String())
+	w = send("/small/upload", 4<<10)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "body limit")
+
+	// nested group keep the parent limits, Upload store into TempDir
+	w = send("/admin/import", 100)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, strings.HasPrefix(w.Body.String(), dir), w.Body.String())
+	w = send("/admin/import", 2<<20)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "must be at most 1048576 bytes")
+}
+
+func TestSessionsAndFlash(t *testing.T) {
+	fsys := fstest.MapFS{"page.html": {Data: []byte(`{{range .flashes}}[{{.Level}}:{{.Message}}]{{end}}{{.title}}`)}}
+	r := New().LoadHTMLFS(fsys, "page.html")
+	r.Use(Sessions(SessionConfig{Secret: bytes.Repeat([]byte("k"), 32)}))
+	r.Post("/login", func(c *Context) {
+		c.Session().Set("user", "jalu")
+		c.Flash("success", "Welcome")
+		c.Writer.WriteHeader(http.StatusSeeOther)
+	})
+	r.Get("/me", func(c *Context) { c.String(200, fmt.Sprint(c.Session().Get("user"))) })
+	r.Get("/page", func(c *Context) { c.HTML(200, "page.html", M{"title": "Home"}) })
+	r.Post("/logout", func(c *Context) { c.Session().Clear() })
+
+	send := func(method, path string, cookie *http.Cookie) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	lastCookie := func(w *httptest.ResponseRecorder) *http.Cookie {
+		cookies := w.Result().Cookies()
+		if !assert.Len(t, cookies, 1) {
+			t.FailNow()
+		}
+		return cookies[0]
+	}
+
+	w := send("POST", "/login", nil)
+	cookie := lastCookie(w)
+	assert.Equal(t, "glaze_session", cookie.Name)
+	assert.True(t, cookie.HttpOnly)
+	assert.Equal(t, http.SameSiteLaxMode, cookie.SameSite)
+	assert.NotContains(t, cookie.Value, "jalu", "encrypted")
+
+	assert.Equal(t, "jalu", send("GET", "/me", cookie).Body.String())
+	tampered := *cookie
+	b := []byte(cookie.Value)
+	b[len(b)/2] ^= 1
+	tampered.Value = string(b)
+	assert.Equal(t, "<nil>", send("GET", "/me", &tampered).Body.String())
+
+	// flashes are rendered once
+	w = send("GET", "/page", cookie)
+	assert.Equal(t, "[success:Welcome]Home", w.Body.String())
+	cookie = lastCookie(w)
+	w = send("GET", "/page", cookie)
+	assert.Equal(t, "Home", w.Body.String())
+	assert.Empty(t, w.Result().Cookies())
+	assert.Equal(t, "jalu", send("GET", "/me", cookie).Body.String())
+
+	w = send("POST", "/logout", cookie)
+	assert.Equal(t, -1, lastCookie(w).MaxAge)
+
+	assert.Panics(t, func() { Sessions(SessionConfig{Secret: []byte("short")}) })
+}
+
+func TestNDJSON(t *testing.T) {
+	type record struct {
+		Name string `json:"name" validate:"required"`
+	}
+	r := New()
+	r.Get("/export", func(c *Context) {
+		i := 0
+		c.NDJSONStream(200, func() (any, bool) {
+			i++
+			return record{Name: fmt.Sprint("r", i)}, i <= 3
+		})
+	})
+	var imported []string
+	r.Post("/import", func(c *Context) {
+		err := BindNDJSON(c, func(rec record) error {
+			imported = append(imported, rec.Name)
+			return nil
+		})
+		if err != nil {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		c.String(200, "ok")
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/export", nil))
+	assert.Equal(t, MIME_NDJSON, w.Header().Get("Content-Type"))
+	assert.Equal(t, "{\"name\":\"r1\"}\n{\"name\":\"r2\"}\n{\"name\":\"r3\"}\n", w.Body.String())
+	assert.True(t, w.Flushed)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("POST", "/import", strings.NewReader("{\"name\":\"a\"}\n\n{\"name\":\"b\"}")))
+	assert.Equal(t, "ok", w.Body.String())
+	assert.Equal(t, []string{"a", "b"}, imported)
+
+	imported = nil
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("POST", "/import", strings.NewReader("{\"name\":\"a\"}\n{\"name\":\"\"}\n{\"name\":\"c\"}\n")))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "NDJSON record 2: name is required", w.Body.String())
+	assert.Equal(t, []string{"a"}, imported)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("POST", "/import", strings.NewReader("{\"name\":\"a\"}\n{oops\n")))
+	assert.Contains(t, w.Body.String(), "invalid NDJSON record 2")
+}
+
+func TestProblemDetails(t *testing.T) {
+	errQuota := NewError(http.StatusTooManyRequests, "quota_exceeded", "quota exceeded")
+	errLegacy := errors.New("legacy")
+
+	r := New(WithProblemDetails())
+	r.Use(ErrorHandler())
+	r.MapError(errLegacy, http.StatusGone, M{"error": "gone", "since": 2024})
+	r.Get("/credit", func(c *Context) {
+		c.Problem(http.StatusForbidden, "https://example.com/probs/out-of-credit", "", "balance is 30", M{"balance": 30, "status": 1})
+	})
+	r.Get("/quota", func(c *Context) { c.Error(errQuota.WithDetails(M{"limit": 10})) })
+	r.Get("/legacy", func(c *Context) { c.Error(errLegacy) })
+	r.Get("/boom", func(c *Context) { c.Error(errors.New("db down")) })
+	r.Post("/users", Handler(func(c *Context, in struct {
+		Name string `json:"name" validate:"required"`
+	}) (M, error) {
+		return M{}, nil
+	}))
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", MIME_JSON)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send("GET", "/credit", "")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, MIME_PROBLEM_JSON, w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"type":"https://example.com/probs/out-of-credit","title":"Forbidden","status":403,"detail":"balance is 30","balance":30}`, w.Body.String())
+
+	w = send("GET", "/quota", "")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.JSONEq(t, `{"title":"Too Many Requests","status":429,"detail":"quota exceeded","instance":"/quota","code":"quota_exceeded","details":{"limit":10}}`, w.Body.String())
+
+	w = send("GET", "/boom", "")
+	assert.JSONEq(t, `{"title":"Internal Server Error","status":500,"detail":"Internal Server Error","instance":"/boom"}`, w.Body.String())
+
+	w = send("POST", "/users", `{}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.JSONEq(t, `{"title":"Unprocessable Entity","status":422,"detail":"validation failed","fields":{"name":"is required"}}`, w.Body.String())
+
+	w = send("POST", "/users", `{`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, MIME_PROBLEM_JSON, w.Header().Get("Content-Type"))
+
+	// custom mapping bodies are kept
+	w = send("GET", "/legacy", "")
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"error":"gone","since":2024}`, w.Body.String())
+}
+
+func TestTransformBody(t *testing.T) {
+	unwrap := func(c *Context, body []byte) ([]byte, error) {
+		var env struct {
+			Data json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal(body, &env); err != nil || env.Data == nil {
+			return nil, NewError(http.StatusBadRequest, "bad_envelope", "")
+		}
+		return env.Data, nil
+	}
+	fromLegacy := func(c *Context, body []byte) ([]byte, error) {
+		c.Request.Header.Set("Content-Type", MIME_JSON)
+		return []byte(`{"name":"` + strings.TrimPrefix(string(body), "name=") + `"}`), nil
+	}
+
+	r := New()
+	handler := func(c *Context) {
+		var in struct {
+			Name string `json:"name" validate:"required"`
+		}
+		if err := c.Bind(&in); err != nil {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		raw, _ := c.OriginalBody()
+		c.String(200, in.Name+"|"+string(raw))
+	}
+	r.Post("/enveloped", TransformBody(unwrap), handler)
+	r.Post("/legacy", TransformBody(fromLegacy), handler)
+	r.Post("/plain", handler)
+
+	send := func(path, contentType, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", path, strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send("/enveloped", MIME_JSON, `{"data":{"name":"jalu"}}`)
+	assert.Equal(t, `jalu|{"data":{"name":"jalu"}}`, w.Body.String())
+	w = send("/legacy", MIME_PLAIN, "name=ana")
+	assert.Equal(t, "ana|name=ana", w.Body.String())
+	w = send("/plain", MIME_JSON, `{"name":"raw"}`)
+	assert.Equal(t, "raw|", w.Body.String())
+
+	w = send("/enveloped", MIME_JSON, `{"name":"jalu"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error":"Bad Request","code":"bad_envelope"}`, w.Body.String())
+}
+
+func TestReady(t *testing.T) {
+	r := New(WithOutput(io.Discard))
+	r.Get("/ping", pingHandler)
+	var hooked atomic.Bool
+	r.OnStart(func(context.Context) error {
+		hooked.Store(true)
+		return nil
+	})
+	assert.Nil(t, r.Addr())
+
+	go r.RunAndListen("127.0.0.1:0")
+	defer r.Close()
+	select {
+	case <-r.Ready():
+	case <-time.After(5 * time.Second):
+		t.Fatal("server not ready")
+	}
+	assert.True(t, hooked.Load())
+
+	resp, err := http.Get("http://" + r.Addr().String() + "/ping")
+	assert.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, "pong", string(body))
+}
+
+func TestTenants(t *testing.T) {
+	type settings struct{ Plan string }
+	known := map[string]*settings{"acme": {Plan: "pro"}, "globex": {Plan: "free"}}
+
+	r := New()
+	r.Use(Tenants(TenantConfig{
+		Param:  "tenant",
+		Header: "X-Tenant",
+		Domain: "example.com",
+		Lookup: func(c *Context, id string) (any, error) {
+			if s, ok := known[id]; ok {
+				return s, nil
+			}
+			return nil, ErrTenantNotFound
+		},
+	}))
+	show := func(c *Context) {
+		conf, _ := TenantConfigOf[*settings](c)
+		c.String(200, c.Tenant().ID+" "+conf.Plan+" "+TenantFromContext(c.Request.Context()).ID)
+	}
+	r.Get("/orders", show)
+	r.Get("/t/:tenant/orders", show)
+
+	send := func(host, path, header string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Host = host
+		if header != "" {
+			req.Header.Set("X-Tenant", header)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, "acme pro acme", send("acme.example.com:8080", "/orders", "").Body.String())
+	assert.Equal(t, "globex free globex", send("api.local", "/orders", "globex").Body.String())
+	assert.Equal(t, "globex free globex", send("acme.example.com", "/t/globex/orders", "acme").Body.String(), "param first")
+
+	w := send("example.com", "/orders", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error":"tenant required","code":"tenant_required"}`, w.Body.String())
+	w = send("a.b.example.com", "/orders", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code, "nested subdomain")
+	assert.Equal(t, http.StatusNotFound, send("initech.example.com", "/orders", "").Code)
+
+	o := New()
+	o.Use(Tenants(TenantConfig{Header: "X-Tenant", Optional: true}))
+	o.Get("/", func(c *Context) { c.String(200, fmt.Sprint(c.Tenant() == nil)) })
+	w = httptest.NewRecorder()
+	o.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, "true", w.Body.String())
+
+	assert.Panics(t, func() { Tenants(TenantConfig{}) })
+}
+
+func TestAssets(t *testing.T) {
+	fsys := fstest.MapFS{
+		"app.js":       {Data: []byte(`console.log(1)`)},
+		"css/site.css": {Data: []byte(`body{}`)},
+		"page.html":    {Data: []byte(`<script src="{{asset "app.js"}}"></script>`)},
+	}
+	r := New()
+	assets := r.Assets("/static", fstest.MapFS{"app.js": fsys["app.js"], "css/site.css": fsys["css/site.css"]})
+	r.LoadHTMLFS(fsys, "*.html")
+	r.Get("/", func(c *Context) { c.HTML(200, "page.html", nil) })
+
+	jsPath, err := assets.Path("app.js")
+	assert.NoError(t, err)
+	assert.Regexp(t, `^/static/app\.[0-9a-f]{10}\.js$`, jsPath)
+	cssPath, _ := assets.Path("/css/site.css")
+	assert.Regexp(t, `^/static/css/site\.[0-9a-f]{10}\.css$`, cssPath)
+	_, err = assets.Path("missing.js")
+	assert.Error(t, err)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, `<script src="`+jsPath+`"></script>`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", jsPath, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "console.log(1)", w.Body.String())
+	assert.Equal(t, "public, max-age=31536000, immutable", w.Header().Get("Cache-Control"))
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/static/css/site.css", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "no-cache", w.Header().Get("Cache-Control"))
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/static/app.0000000000.js", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("POST", jsPath, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+
+	assert.Panics(t, func() { r.Assets("/other", fsys) })
+}
+
+func TestBodyStats(t *testing.T) {
+	var events []BodyEvent
+	r := New(WithBodyObserver(func(c *Context, ev BodyEvent) { events = append(events, ev) }))
+	r.MultipartMemory = 1 << 10
+	type input struct {
+		Name string `json:"name" form:"name" validate:"required"`
+		Page int    `query:"page"`
+	}
+	r.Post("/bind", func(c *Context) {
+		var in input
+		c.Bind(&in)
+	})
+	r.Post("/upload", func(c *Context) { c.MultipartForm() })
+
+	send := func(path, contentType string, body io.Reader) {
+		req := httptest.NewRequest("POST", path, body)
+		req.Header.Set("Content-Type", contentType)
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	send("/bind", MIME_JSON, strings.NewReader(`{"name":"jalu"}`))
+	send("/bind", MIME_JSON, strings.NewReader(`{"name":`))
+	send("/bind", MIME_JSON, strings.NewReader(`{}`))
+	send("/bind?page=x", MIME_POST_FORM, strings.NewReader(`name=ana`))
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, _ := mw.CreateFormFile("big", "big.bin")
+	fw.Write(bytes.Repeat([]byte("a"), 4<<10))
+	fw, _ = mw.CreateFormFile("small", "small.txt")
+	fw.Write([]byte("hi"))
+	mw.Close()
+	size := int64(body.Len())
+	send("/upload", mw.FormDataContentType(), &body)
+
+	kinds := make([]string, len(events))
+	for i, ev := range events {
+		kinds[i] = ev.Kind
+	}
+	assert.Equal(t, []string{"json", "json", "json", "validation", "form", "params", "multipart"}, kinds)
+	assert.Equal(t, int64(15), events[0].Size)
+	assert.NoError(t, events[0].Err)
+	assert.Error(t, events[1].Err)
+	assert.Equal(t, size, events[6].Size)
+	assert.Equal(t, 1, events[6].Spilled)
+	assert.Equal(t, int64(4<<10), events[6].SpilledBytes)
+
+	stats := r.BodyStats()
+	assert.Equal(t, uint64(5), stats.Parsed)
+	assert.Equal(t, uint64(2), stats.Failures, "malformed JSON and query")
+	assert.Equal(t, uint64(1), stats.ValidationFailures)
+	assert.Equal(t, uint64(4), stats.Sizes[0])
+	assert.Equal(t, uint64(1), stats.Sizes[1])
+	assert.Equal(t, uint64(1), stats.Spills)
+	assert.Equal(t, uint64(15+8+2+8)+uint64(size), stats.Bytes)
+}
+
+type slowReporter struct {
+	testReporter
+	slow []SlowReport
+}
+
+func (r *slowReporter) ReportSlow(c *Context, report SlowReport) { r.slow = append(r.slow, report) }
+
+func slowHandler(c *Context) {
+	time.Sleep(20 * time.Millisecond)
+	c.String(http.StatusAccepted, "done")
+}
+
+func TestSlowThreshold(t *testing.T) {
+	var out strings.Builder
+	r := New(WithSlowThreshold(10*time.Millisecond), WithOutput(&out))
+	r.Get("/slow/:id", slowHandler)
+	r.Get("/fast", pingHandler)
+
+	for _, path := range []string{"/slow/7", "/fast", "/missing"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+	assert.Contains(t, out.String(), "slow request: GET /slow/:id (/slow/7) took")
+	assert.Contains(t, out.String(), "handler github.com/nrhox/glaze.slowHandler, status 202")
+	assert.Equal(t, 1, strings.Count(out.String(), "slow request"))
+
+	rep := &slowReporter{}
+	r = New(WithSlowThreshold(10*time.Millisecond), WithReporter(rep), WithOutput(&out))
+	r.Get("/slow/:id", slowHandler)
+	out.Reset()
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/slow/7", nil))
+	assert.Empty(t, out.String())
+	if assert.Len(t, rep.slow, 1) {
+		assert.Equal(t, "/slow/:id", rep.slow[0].Route)
+		assert.GreaterOrEqual(t, rep.slow[0].Latency, 20*time.Millisecond)
+		assert.Equal(t, 10*time.Millisecond, rep.slow[0].Threshold)
+	}
+}
+
+func TestNamedRoutes(t *testing.T) {
+	r := New()
+	api := r.Group("/api")
+	api.Name("user").Get("/users/:id", pingHandler)
+	api.Name("user").Put("/users/:id", pingHandler)
+	r.Name("home").Get("/", pingHandler)
+	r.Post("/users", func(c *Context) {
+		c.RedirectToRoute("user", map[string]string{"id": "a b"}, url.Values{"tab": {"info"}})
+	})
+	r.Get("/old", func(c *Context) {
+		if err := c.RedirectToRoute("user", nil, nil); err != nil {
+			c.String(http.StatusInternalServerError, err.Error())
+		}
+	})
+
+	assert.Equal(t, "/api/users/7", r.MustURL("user", map[string]string{"id": "7"}, nil))
+	assert.Equal(t, "/", r.MustURL("home", nil, nil))
+	_, err := r.URL("user", map[string]string{"id": "7", "tab": "x"}, nil)
+	assert.EqualError(t, err, `glaze: route "user" has no parameter tab`)
+	_, err = r.URL("nope", nil, nil)
+	assert.EqualError(t, err, `glaze: unknown route name "nope"`)
+	assert.Panics(t, func() { r.MustURL("user", nil, nil) })
+	assert.Panics(t, func() { r.Name("home").Get("/home", pingHandler) })
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("POST", "/users", nil))
+	assert.Equal(t, http.StatusSeeOther, w.Code)
+	assert.Equal(t, "/api/users/a%20b?tab=info", w.Header().Get("Location"))
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/old", nil))
+	assert.Equal(t, `glaze: route "user" need the parameter "id"`, w.Body.String())
+}
+
+func TestHeaderHelpers(t *testing.T) {
+	assert.Equal(t, []string{"gzip", `x;a="1,2"`, "br"}, SplitHeader(` gzip,,x;a="1,2" , br `))
+
+	els := ParseHeader(`text/html;level=1;q=0.8, */*;q=bad, for="[2001:db8::1]:80";Proto=https`)
+	assert.Len(t, els, 3)
+	assert.Equal(t, "text/html", els[0].Value)
+	assert.Equal(t, map[string]string{"level": "1", "q": "0.8"}, els[0].Params)
+	assert.Equal(t, 0.8, els[0].Q())
+	assert.Equal(t, 1.0, els[1].Q(), "invalid q")
+	assert.Equal(t, "", els[2].Value)
+	assert.Equal(t, map[string]string{"for": "[2001:db8::1]:80", "proto": "https"}, els[2].Params)
+
+	r := New()
+	r.Get("/", func(c *Context) {
+		c.AddHeader("Vary", "Accept")
+		c.AddHeader("Vary", "Accept-Language")
+		c.Text(200, "%d %q", len(c.GetHeaders("X-Tag")), c.HeaderValues("X-Tag"))
+	})
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Add("X-Tag", "a, b")
+	req.Header.Add("X-Tag", "c")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, `2 ["a" "b" "c"]`, w.Body.String())
+	assert.Equal(t, []string{"Accept", "Accept-Language"}, w.Header().Values("Vary"))
+}
+
+func TestCharset(t *testing.T) {
+	r := New(WithCharset("ISO-8859-1", Latin1))
+	r.Get("/text", func(c *Context) { c.Text(200, "café %s", "€") })
+	r.Get("/html", func(c *Context) { c.HTMLString(200, "<p>déjà</p>") })
+	r.Get("/typed", func(c *Context) {
+		c.Writer.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		c.Text(200, "é")
+	})
+
+	send := func(path, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		if accept != "" {
+			req.Header.Set("Accept-Charset", accept)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send("/text", "iso-8859-1, utf-8;q=0.5")
+	assert.Equal(t, "text/plain; charset=iso-8859-1", w.Header().Get("Content-Type"))
+	assert.Equal(t, []byte("caf\xe9 ?"), w.Body.Bytes())
+	assert.Equal(t, "Accept-Charset", w.Header().Get("Vary"))
+
+	w = send("/html", "ISO-8859-1")
+	assert.Equal(t, "text/html; charset=iso-8859-1", w.Header().Get("Content-Type"))
+	assert.Equal(t, []byte("<p>d\xe9j\xe0</p>"), w.Body.Bytes())
+
+	for _, accept := range []string{"", "utf-8, iso-8859-1", "*", "windows-1252"} {
+		w = send("/text", accept)
+		assert.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"), accept)
+		assert.Equal(t, "café €", w.Body.String(), accept)
+	}
+
+	w = send("/typed", "iso-8859-1")
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "é", w.Body.String())
+}
+
+func TestCatchAllRoute(t *testing.T) {
+	r := New()
+	show := func(c *Context) {
+		c.Text(200, "%s %q %q", c.FullPath(), c.Param("id"), c.Param("filepath"))
+	}
+	r.Get("/static/*filepath", show)
+	r.Get("/static/index", show)
+	r.Get("/repos/:id/*filepath", show)
+	r.Get("/repos/:id/settings", show)
+	r.Name("file").Get("/files/*filepath", show)
+
+	for path, want := range map[string]string{
+		"/static/css/app.css":     `/static/*filepath "" "css/app.css"`,
+		"/static/index":           `/static/index "" ""`,
+		"/static/index/more":      `/static/*filepath "" "index/more"`,
+		"/static":                 `/static/*filepath "" ""`,
+		"/static/":                `/static/*filepath "" ""`,
+		"/repos/7/settings":       `/repos/:id/settings "7" ""`,
+		"/repos/7/settings/hooks": `/repos/:id/*filepath "7" "settings/hooks"`,
+		"/repos/7/blob/main/a.go": `/repos/:id/*filepath "7" "blob/main/a.go"`,
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		assert.Equal(t, 200, w.Code, path)
+		assert.Equal(t, want, w.Body.String(), path)
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("POST", "/static/a", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+
+	assert.Equal(t, "/files/a%20b/c.txt", r.MustURL("file", map[string]string{"filepath": "a b/c.txt"}, nil))
+	assert.Panics(t, func() { r.Get("/bad/*rest/more", pingHandler) })
+	assert.Panics(t, func() { r.Get("/static/*other", pingHandler) })
+	assert.Panics(t, func() { r.Get("/static/*filepath", pingHandler) })
+}
+
+func TestBoundValue(t *testing.T) {
+	type createOrder struct {
+		Item string `json:"item" validate:"required"`
+		Qty  int    `query:"qty"`
+	}
+	r := New()
+	r.Post("/orders", func(c *Context) {
+		assert.Nil(t, c.BoundValue())
+		var in createOrder
+		if err := c.Bind(&in); err != nil {
+			c.String(http.StatusBadRequest, err.Error())
+			c.Abort()
+			return
+		}
+		c.Next()
+	}, func(c *Context) {
+		in := c.BoundValue().(*createOrder)
+		var again createOrder
+		assert.NoError(t, c.Bind(&again), "body already read")
+		c.Text(200, "%s %d %s", in.Item, in.Qty, again.Item)
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/orders?qty=2", strings.NewReader(`{"item":"tea"}`))
+	req.Header.Set("Content-Type", MIME_JSON)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, "tea 2 tea", w.Body.String())
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest("POST", "/orders", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", MIME_JSON)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestNoRoute(t *testing.T) {
+	r := New()
+	r.Get("/ping", pingHandler)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/missing", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	r.Use(func(c *Context) {
+		c.Writer.Header().Set("X-Mw", "1")
+		c.Next()
+	})
+	r.NoRoute(func(c *Context) {
+		if c.Query("silent") != "" {
+			return
+		}
+		c.JSON(http.StatusNotFound, M{"error": "no such endpoint", "path": c.Request.URL.Path})
+	})
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/missing", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "1", w.Header().Get("X-Mw"))
+	assert.JSONEq(t, `{"error":"no such endpoint","path":"/missing"}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/missing?silent=1", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "404 Not Found", w.Body.String())
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("POST", "/ping", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code, "405 is not a NoRoute")
+}
+
+func TestStaticETagAndCacheRules(t *testing.T) {
+	mod := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	fsys := fstest.MapFS{
+		"index.html":           {Data: []byte("home"), ModTime: mod},
+		"app.3f9a2c1b7d.js":    {Data: []byte("js"), ModTime: mod},
+		"fonts/a.woff2":        {Data: []byte("font"), ModTime: mod},
+		"robots.txt":           {Data: []byte("txt"), ModTime: mod},
+		"notes.2025.01.txt":    {Data: []byte("n"), ModTime: mod},
+		"app.3f9a2c1b7d.js.gz": {Data: []byte("gz"), ModTime: mod},
+	}
+	r := New()
+	r.StaticFS("/", http.FS(fsys), StaticConfig{
+		Fallback:     "index.html",
+		CacheControl: "public, max-age=60",
+		CacheRules: []CacheRule{
+			{Fingerprinted: true, CacheControl: "public, max-age=31536000, immutable"},
+			{Match: "*.html", CacheControl: "no-cache"},
+			{Match: "/fonts/*", CacheControl: "public, max-age=86400"},
+		},
+	})
+
+	serve := func(path string, header ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		for i := 0; i+1 < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve("/app.3f9a2c1b7d.js")
+	etag := w.Header().Get("ETag")
+	assert.Regexp(t, `^"[0-9a-f]{32}"$`, etag)
+	assert.Equal(t, mod.Format(http.TimeFormat), w.Header().Get("Last-Modified"))
+	assert.Equal(t, "public, max-age=31536000, immutable", w.Header().Get("Cache-Control"))
+	assert.Equal(t, etag, serve("/app.3f9a2c1b7d.js").Header().Get("ETag"), "stable")
+
+	w = serve("/app.3f9a2c1b7d.js", "If-None-Match", etag)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+	w = serve("/app.3f9a2c1b7d.js", "If-Modified-Since", mod.Format(http.TimeFormat))
+	assert.Equal(t, http.StatusNotModified, w.Code)
+
+	gz := serve("/app.3f9a2c1b7d.js", "Accept-Encoding", "gzip")
+	assert.Equal(t, "gz", gz.Body.String())
+	assert.NotEqual(t, etag, gz.Header().Get("ETag"), "sidecar has its own ETag")
+
+	assert.Equal(t, "no-cache", serve("/").Header().Get("Cache-Control"))
+	assert.Equal(t, "no-cache", serve("/dashboard/settings").Header().Get("Cache-Control"), "fallback")
+	assert.Equal(t, "public, max-age=86400", serve("/fonts/a.woff2").Header().Get("Cache-Control"))
+	assert.Equal(t, "public, max-age=60", serve("/robots.txt").Header().Get("Cache-Control"))
+	assert.Equal(t, "public, max-age=60", serve("/notes.2025.01.txt").Header().Get("Cache-Control"))
+}
+
+func TestFaviconAndRobots(t *testing.T) {
+	icon := []byte("\x00\x00\x01\x00icon")
+	dir := t.TempDir()
+	svg := filepath.Join(dir, "icon.svg")
+	assert.NoError(t, os.WriteFile(svg, []byte("<svg/>"), 0o644))
+
+	r := New()
+	r.Favicon(icon)
+	r.RobotsTxt("User-agent: *\nDisallow: /admin/\n")
+	r.Group("/v2").Favicon(svg)
+	assert.Panics(t, func() { r.Group("/x").Favicon(42) })
+	assert.Panics(t, func() { r.Group("/y").Favicon(filepath.Join(dir, "missing.ico")) })
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/favicon.ico", nil))
+	assert.Equal(t, icon, w.Body.Bytes())
+	assert.Equal(t, "image/x-icon", w.Header().Get("Content-Type"))
+	assert.Equal(t, "public, max-age=86400", w.Header().Get("Cache-Control"))
+	etag := w.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+
+	req := httptest.NewRequest("GET", "/favicon.ico", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/v2/favicon.ico", nil))
+	assert.Equal(t, "<svg/>", w.Body.String())
+	assert.Equal(t, "image/svg+xml", w.Header().Get("Content-Type"))
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("HEAD", "/robots.txt", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "public, max-age=3600", w.Header().Get("Cache-Control"))
+}
+
+func TestNoCompression(t *testing.T) {
+	big := strings.Repeat("glaze ", 100)
+	send := func(c *Context) { c.String(http.StatusOK, big) }
+	do := func(r *Engine, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	r := New()
+	r.Use(Compress(CompressConfig{MinLength: 64}))
+	r.Get("/big", send)
+	r.NoCompression().Get("/download", send)
+	assert.Equal(t, "gzip", do(r, "/big").Header().Get("Content-Encoding"))
+	w := do(r, "/download")
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Equal(t, big, w.Body.String())
+
+	// pre-routing, the route is known once the body is written
+	r = New()
+	r.UsePhase(PhasePreRouting, 0, Compress(CompressConfig{MinLength: 64}))
+	r.Get("/big", send)
+	r.NoCompression().Get("/download", send)
+	assert.Equal(t, "gzip", do(r, "/big").Header().Get("Content-Encoding"))
+	w = do(r, "/download")
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Equal(t, big, w.Body.String())
+}
+
+func TestRequestIDInLogs(t *testing.T) {
+	var logs bytes.Buffer
+	r := New(WithOutput(io.Discard), WithErrorOutput(&logs))
+	r.Use(RequestID(), Recovery(), ErrorHandler())
+	r.Get("/users/:id", func(c *Context) {
+		assert.Equal(t, c.RequestID(), RequestIDFromContext(c.Request.Context()))
+		c.Text(200, "%s", c.RequestID())
+	})
+	r.Get("/panic/:id", func(c *Context) { panic("boom") })
+	r.Get("/fail", func(c *Context) { c.Error(errors.New("db down")) })
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/users/1", nil))
+	assert.Regexp(t, `^[0-9a-f]{32}$`, w.Body.String())
+	assert.Equal(t, w.Body.String(), w.Header().Get("X-Request-ID"))
+
+	req := httptest.NewRequest("GET", "/users/1", nil)
+	req.Header.Set("X-Request-ID", "trace-42")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, "trace-42", w.Body.String())
+
+	req = httptest.NewRequest("GET", "/users/1", nil)
+	req.Header.Set("X-Request-ID", "bad id\n")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.NotEqual(t, "bad id\n", w.Body.String())
+
+	req = httptest.NewRequest("GET", "/panic/7", nil)
+	req.Header.Set("X-Request-ID", "trace-panic")
+	req.RemoteAddr = "10.0.0.7:1234"
+	r.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Contains(t, logs.String(), "request_id=trace-panic route=/panic/:id client_ip=10.0.0.7\n")
+
+	logs.Reset()
+	req = httptest.NewRequest("GET", "/fail", nil)
+	req.Header.Set("X-Request-ID", "trace-err")
+	req.RemoteAddr = "10.0.0.8:1234"
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "[ERROR] GET /fail: db down (request_id=trace-err route=/fail client_ip=10.0.0.8)\n", logs.String())
+}
+
+func TestRadixTree(t *testing.T) {
+	r := New()
+	show := func(c *Context) {
+		c.Text(200, "%s %v", c.FullPath(), c.Params)
+	}
+	for _, p := range []string{
+		"/", "/use", "/user", "/users", "/users/me", "/users/:id", "/users/:id/posts/:post",
+		"/users/me/settings", "/ab", "/a/:x", "/search/", "/api/v1/organizations/members",
+		"/api/v1/organizations/projects", "/api/v2",
+	} {
+		r.Get(p, show)
+	}
+
+	for path, want := range map[string]string{
+		"/":                              "/ []",
+		"/use":                           "/use []",
+		"/user":                          "/user []",
+		"/users":                         "/users []",
+		"/users/":                        "/users []",
+		"//users//me/":                   "/users/me []",
+		"/users/me":                      "/users/me []",
+		"/users/mex":                     "/users/:id [{id mex}]",
+		"/users/m":                       "/users/:id [{id m}]",
+		"/users/7/posts/9":               "/users/:id/posts/:post [{id 7} {post 9}]",
+		"/users/me/settings":             "/users/me/settings []",
+		"/ab":                            "/ab []",
+		"/a/1":                           "/a/:x [{x 1}]",
+		"/search":                        "/search/ []",
+		"/api/v1/organizations/projects": "/api/v1/organizations/projects []",
+		"/api/v2":                        "/api/v2 []",
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		assert.Equal(t, want, w.Body.String(), path)
+	}
+
+	for _, path := range []string{"/us", "/userx", "/users/me/posts/1", "/api/v1/organizations", "/api/v3", "/a", "/abc"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		assert.Equal(t, http.StatusNotFound, w.Code, path)
+	}
+
+	assert.PanicsWithValue(t, "conflict: static 'all' collides with param in GET /users/all", func() {
+		r.Get("/users/all", show)
+	})
+	assert.PanicsWithValue(t, "duplicate route detected: GET /users/me/", func() {
+		r.Get("/users//me/", show)
+	})
+	r.Get("/users/me/avatar", show) // existing static segment
+	assert.Len(t, r.RoutesInfo(), 15)
+}
+
+func TestFeatureFlags(t *testing.T) {
+	r := New()
+	r.Get("/off", func(c *Context) { c.Text(200, "%v", c.FlagEnabled("beta")) })
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/off", nil))
+	assert.Equal(t, "false", w.Body.String(), "no provider")
+
+	calls := 0
+	r = New(WithFlags(FlagFunc(func(c *Context, name string) bool {
+		calls++
+		return name == "beta" && c.GetHeader("X-Beta") == "1"
+	})))
+	r.Use(func(c *Context) {
+		if c.FlagEnabled("beta") {
+			c.Writer.Header().Set("X-Variant", "beta")
+		}
+	})
+	r.Get("/", func(c *Context) { c.Text(200, "%v %v", c.FlagEnabled("beta"), c.FlagEnabled("other")) })
+	r.Group("/v2", RequireFlag("beta")).Get("/", pingHandler)
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("X-Beta", "1")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, "true false", w.Body.String())
+	assert.Equal(t, "beta", w.Header().Get("X-Variant"))
+	assert.Equal(t, 2, calls, "one call per flag and request")
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/v2/", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	req = httptest.NewRequest("GET", "/v2/", nil)
+	req.Header.Set("X-Beta", "1")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, "pong", w.Body.String())
+}
+
+func TestRedirectTrailingSlash(t *testing.T) {
+	r := New(WithRedirectTrailingSlash())
+	for _, p := range []string{"/", "/users", "/users/:id", "/search/", "/static/*filepath"} {
+		r.Get(p, pingHandler)
+	}
+	r.Post("/users", pingHandler)
+
+	for _, tt := range []struct {
+		method, path string
+		code         int
+		location     string
+	}{
+		{"GET", "/users", 200, ""},
+		{"GET", "/users/", 301, "/users"},
+		{"GET", "/users/?page=2", 301, "/users?page=2"},
+		{"GET", "/users/7/", 301, "/users/7"},
+		{"POST", "/users/", 308, "/users"},
+		{"GET", "/search/", 200, ""},
+		{"GET", "/search", 301, "/search/"},
+		{"GET", "//search", 301, "/search/"},
+		{"GET", "/", 200, ""},
+		{"GET", "/static", 200, ""},
+		{"GET", "/static/css/", 200, ""},
+		{"GET", "/nope/", 404, ""},
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+		assert.Equal(t, tt.code, w.Code, tt.method+" "+tt.path)
+		assert.Equal(t, tt.location, w.Header().Get("Location"), tt.method+" "+tt.path)
+	}
+
+	// default: both forms match
+	r = New()
+	r.Get("/users", pingHandler)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/users/", nil))
+	assert.Equal(t, "pong", w.Body.String())
+}
+
+func TestResponseCache(t *testing.T) {
+	r := New()
+	r.Use(ResponseCache())
+	calls := 0
+	count := func(c *Context) {
+		calls++
+		c.Text(200, "%d %s", calls, c.GetHeader("Accept-Language"))
+	}
+	r.Cache(time.Minute, "accept-language").Get("/products", count)
+	r.Get("/live", count)
+	r.Cache(time.Minute).Get("/me", func(c *Context) {
+		c.SetCookie("seen", "1", 0, "/", "", false, true, http.SameSiteLaxMode)
+		count(c)
+	})
+
+	get := func(path, lang string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		if lang != "" {
+			req.Header.Set("Accept-Language", lang)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("/products", "en")
+	assert.Equal(t, "1 en", w.Body.String())
+	assert.Equal(t, "MISS", w.Header().Get("X-Cache"))
+	assert.Equal(t, "Accept-Language", w.Header().Get("Vary"))
+	w = get("/products", "en")
+	assert.Equal(t, "1 en", w.Body.String())
+	assert.Equal(t, "HIT", w.Header().Get("X-Cache"))
+	assert.Equal(t, "2 fr", get("/products", "fr").Body.String(), "other vary value")
+	assert.Equal(t, "3 en", get("/products?page=2", "en").Body.String(), "other query")
+
+	assert.Equal(t, "4 ", get("/live", "").Body.String())
+	assert.Equal(t, "5 ", get("/live", "").Body.String(), "route without Cache")
+	assert.Empty(t, get("/live", "").Header().Get("X-Cache"))
+
+	get("/me", "")
+	assert.Equal(t, "8 ", get("/me", "").Body.String(), "Set-Cookie is not cached")
+
+	info := r.RoutesInfo()
+	for _, ri := range info {
+		if ri.Path == "/products" {
+			assert.Equal(t, routeCache{ttl: time.Minute, vary: []string{"Accept-Language"}}, ri.Meta[cacheMeta])
+		}
+	}
+}
+
+func TestResponseCacheHostAndHeaders(t *testing.T) {
+	r := New()
+	r.Use(RequestID(), ResponseCache())
+	r.Cache(time.Minute).Get("/p", func(c *Context) {
+		c.Writer.Header().Set("X-Tenant", c.Request.Host)
+		c.Text(200, "page of %s", c.Request.Host)
+	})
+
+	get := func(host, id string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "http://"+host+"/p", nil)
+		req.Header.Set("X-Request-ID", id)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, "page of acme.example.com", get("acme.example.com", "a1").Body.String())
+	w := get("globex.example.com", "g1")
+	assert.Equal(t, "page of globex.example.com", w.Body.String(), "other host")
+	assert.Equal(t, "MISS", w.Header().Get("X-Cache"))
+
+	w = get("acme.example.com", "a2")
+	assert.Equal(t, "HIT", w.Header().Get("X-Cache"))
+	assert.Equal(t, "page of acme.example.com", w.Body.String())
+	assert.Equal(t, "acme.example.com", w.Header().Get("X-Tenant"))
+	assert.Equal(t, "a2", w.Header().Get("X-Request-ID"), "request id of the current request")
+}
+
+func TestResponseCacheCompress(t *testing.T) {
+	page := strings.Repeat("glaze ", 100)
+	for _, tt := range []struct {
+		name  string
+		mw    []HandlerFunc
+		calls int // one cached body, or one per encoding
+	}{
+		{"compress first", []HandlerFunc{Compress(CompressConfig{MinLength: 10}), ResponseCache()}, 1},
+		{"cache first", []HandlerFunc{ResponseCache(), Compress(CompressConfig{MinLength: 10})}, 2},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New()
+			r.Use(tt.mw...)
+			calls := 0
+			r.Cache(time.Minute).Get("/page", func(c *Context) {
+				calls++
+				c.String(http.StatusOK, page)
+			})
+
+			for i, encoding := range []string{"gzip", "", "gzip", ""} {
+				req := httptest.NewRequest("GET", "/page", nil)
+				if encoding != "" {
+					req.Header.Set("Accept-Encoding", encoding)
+				}
+				w := httptest.NewRecorder()
+				r.ServeHTTP(w, req)
+
+				if i >= 2 {
+					assert.Equal(t, "HIT", w.Header().Get("X-Cache"))
+				}
+				assert.Equal(t, encoding, w.Header().Get("Content-Encoding"))
+				assert.Contains(t, w.Header().Values("Vary"), "Accept-Encoding")
+				body := w.Body.Bytes()
+				if encoding == "gzip" {
+					zr, err := gzip.NewReader(w.Body)
+					if !assert.NoError(t, err) {
+						return
+					}
+					body, _ = io.ReadAll(zr)
+				}
+				assert.Equal(t, page, string(body))
+			}
+			assert.Equal(t, tt.calls, calls)
+		})
+	}
+}
+
+func TestRedirectFixedPath(t *testing.T) {
+	r := New(WithRedirectFixedPath(true))
+	for _, p := range []string{"/", "/users", "/users/:id/Posts", "/docs/", "/static/*filepath"} {
+		r.Get(p, pingHandler)
+	}
+	r.Post("/users", pingHandler)
+
+	for _, tt := range []struct {
+		method, path string
+		code         int
+		location     string
+	}{
+		{"GET", "/users", 200, ""},
+		{"GET", "//users", 301, "/users"},
+		{"GET", "/a/../users?page=2", 301, "/users?page=2"},
+		{"POST", "/./users", 308, "/users"},
+		{"GET", "/USERS", 301, "/users"},
+		{"GET", "/Users/Bob/posts", 301, "/users/Bob/Posts"},
+		{"GET", "/users//7/./Posts", 301, "/users/7/Posts"},
+		{"GET", "/DOCS/", 301, "/docs/"},
+		{"GET", "/Static/CSS/App.css", 301, "/static/CSS/App.css"},
+		{"GET", "/static/a/../b", 301, "/static/b"},
+		{"GET", "//nope", 404, ""},
+		{"GET", "/a/../nope", 400, ""},
+		{"GET", "/a/../users%00", 400, ""},
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+		assert.Equal(t, tt.code, w.Code, tt.method+" "+tt.path)
+		assert.Equal(t, tt.location, w.Header().Get("Location"), tt.method+" "+tt.path)
+	}
+
+	// case sensitive
+	r = New(WithRedirectFixedPath(false))
+	r.Get("/users", pingHandler)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/USERS", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/users//", nil))
+	assert.Equal(t, "/users/", w.Header().Get("Location"))
+}
+
+func TestRegisterRouteSpecs(t *testing.T) {
+	r := New()
+	r.Get("/health", pingHandler)
+	r.RegisterMiddleware("tag", func(c *Context) { c.Writer.Header().Set("X-Tag", "1") })
+
+	show := func(c *Context) {
+		audit, _ := c.RouteMeta("audit")
+		c.Text(200, "%s %v", c.FullPath(), audit)
+	}
+	err := r.Register([]RouteSpec{
+		{Method: "GET", Path: "/users/:id", Handler: show},
+		{Method: "POST", Path: "/users", Handler: show, Middleware: []string{"tag"}, Meta: map[string]any{"audit": true}},
+	})
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/users/7", nil))
+	assert.Equal(t, "/users/:id <nil>", w.Body.String())
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("POST", "/users", nil))
+	assert.Equal(t, "/users true", w.Body.String())
+	assert.Equal(t, "1", w.Header().Get("X-Tag"))
+
+	err = r.Register([]RouteSpec{
+		{Method: "GET", Path: "/ok", Handler: show},
+		{Method: "get", Path: "nope", Handler: show},
+		{Method: "PUT", Path: "/users", Middleware: []string{"auth"}},
+		{Method: "GET", Path: "/users/:name/", Handler: show},
+		{Method: "GET", Path: "/health", Handler: show},
+		{Method: "GET", Path: "/ok/", Handler: show},
+	})
+	assert.EqualError(t, err, `glaze: route spec 1 (get nope): invalid method
+glaze: route spec 1 (get nope): path must start with /
+glaze: route spec 2 (PUT /users): no handler
+glaze: route spec 2 (PUT /users): unknown middleware "auth"
+glaze: route spec 3 (GET /users/:name/): route already registered
+glaze: route spec 4 (GET /health): route already registered
+glaze: route spec 5 (GET /ok/): route already registered`)
+	assert.Len(t, r.RoutesInfo(), 3, "nothing added from an invalid table")
+
+	err = r.Register([]RouteSpec{
+		{Method: "GET", Path: "/ok", Handler: show},
+		{Method: "GET", Path: "/items/:id", Handler: show},
+		{Method: "GET", Path: "/items/new", Handler: show},
+		{Method: "GET", Path: "/users/new", Handler: show},
+		{Method: "GET", Path: "/named", Handler: show, Meta: map[string]any{nameMeta: "home"}},
+		{Method: "GET", Path: "/other", Handler: show, Meta: map[string]any{nameMeta: "home"}},
+	})
+	assert.EqualError(t, err, `glaze: route spec 2 (GET /items/new): conflict: static 'new' collides with param in GET /items/new
+glaze: route spec 3 (GET /users/new): conflict: static 'new' collides with param in GET /users/new
+glaze: route spec 5 (GET /other): route name "home" used by /named and GET /other`)
+	assert.Len(t, r.RoutesInfo(), 3, "nothing added from a conflicting table")
+}
+
+func TestBindError(t *testing.T) {
+	type address struct {
+		City string `json:"city"`
+	}
+	type input struct {
+		ID      int           `path:"id"`
+		Pages   []uint        `query:"page"`
+		Timeout time.Duration `query:"timeout"`
+		Level   *int8         `header:"X-Level"`
+		Address address       `json:"address"`
+	}
+	bind := func(target, body string, header ...string) *BindError {
+		r := New()
+		var err error
+		r.Post("/users/:id", func(c *Context) { err = c.Bind(&input{}) })
+		req := httptest.NewRequest("POST", target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if len(header) == 2 {
+			req.Header.Set(header[0], header[1])
+		}
+		r.ServeHTTP(httptest.NewRecorder(), req)
+		var berr *BindError
+		if !assert.ErrorAs(t, err, &berr) {
+			t.FailNow()
+		}
+		return berr
+	}
+
+	berr := bind("/users/x", "")
+	assert.Equal(t, BindError{Field: "id", Location: "path", Reason: "must be an integer", Value: "x", Err: berr.Err}, *berr)
+	assert.Equal(t, `path parameter "id": must be an integer`, berr.Error())
+	var numErr *strconv.NumError
+	assert.ErrorAs(t, berr, &numErr)
+
+	berr = bind("/users/1?page=2&page=-3", "")
+	assert.Equal(t, []string{"page", "query", "must be a non-negative integer", "-3"}, []string{berr.Field, berr.Location, berr.Reason, berr.Value})
+	assert.Equal(t, "must be a duration", bind("/users/1?timeout=soon", "").Reason)
+	assert.Equal(t, "is out of range", bind("/users/1", "", "X-Level", "300").Reason)
+
+	berr = bind("/users/1", `{"address":{"city":7}}`)
+	assert.Equal(t, []string{"address.city", "body", "must be a string", "number"}, []string{berr.Field, berr.Location, berr.Reason, berr.Value})
+	assert.True(t, strings.HasPrefix(berr.Error(), "invalid JSON body: json: cannot unmarshal number"))
+	berr = bind("/users/1", `{"address":`)
+	assert.Equal(t, []string{"", "body", "is not valid JSON"}, []string{berr.Field, berr.Location, berr.Reason})
+	assert.Equal(t, "invalid JSON body: is not valid JSON", (&BindError{Location: "body", Reason: "is not valid JSON"}).Error())
+
+	// typed handlers answer with the field
+	r := New()
+	r.Get("/items/:id", Handler(func(c *Context, in struct {
+		ID int `path:"id"`
+	}) (M, error) {
+		return M{"id": in.ID}, nil
+	}))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/items/abc", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error":"path parameter \"id\": must be an integer","fields":{"id":"must be an integer"}}`, w.Body.String())
+}
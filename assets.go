@@ -0,0 +1,126 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// immutableCache is the Cache-Control of the fingerprinted files, their
+// name change with their content.
+const immutableCache = "public, max-age=31536000, immutable"
+
+// Assets is a set of static files served under fingerprinted names,
+// "app.3f2a1b9c0d.js" for app.js, see Route.Assets.
+type Assets struct {
+	prefix string
+	fsys   http.FileSystem
+	hashed map[string]string // file name → fingerprinted name
+	files  map[string]string // fingerprinted name → file name
+	etags  etagCache
+}
+
+// Assets serve the files of fsys under relativePath with a content hash
+// in their name and immutable cache headers, the plain names are still
+// served with "no-cache". The files are hashed once, at registration.
+// Templates get the hashed path with the asset function, so Assets must
+// be called before LoadHTMLGlob or LoadHTMLFS:
+//
+//	<script src="{{asset "app.js"}}"></script>
+//
+// Usage:
+//
+//	//go:embed public
+//	var public embed.FS
+//
+//	sub, _ := fs.Sub(public, "public")
+//	assets := r.Assets("/assets", sub)
+//	r.LoadHTMLGlob("views/*.html")
+func (r *Route) Assets(relativePath string, fsys fs.FS) *Assets {
+	if strings.Contains(relativePath, ":") {
+		panic("URL parameters can not be used when serving assets")
+	}
+	e := r.engine
+	if e.assets != nil {
+		panic("glaze: Assets already registered on " + e.assets.prefix)
+	}
+	a := &Assets{
+		prefix: strings.TrimSuffix(r.jointAbsolutePath(relativePath), "/"),
+		fsys:   http.FS(fsys),
+		hashed: map[string]string{},
+		files:  map[string]string{},
+	}
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(data)
+		ext := path.Ext(name)
+		fingerprinted := strings.TrimSuffix(name, ext) + "." + hex.EncodeToString(sum[:5]) + ext
+		a.hashed[name] = fingerprinted
+		a.files[fingerprinted] = name
+		return nil
+	})
+	if err != nil {
+		panic("glaze: assets: " + err.Error())
+	}
+
+	e.assets = a
+	e.addMount(a.prefix, readMethods, r.meta, r.joinHandler(HandlersChain{a.serve}))
+	return a
+}
+
+// Path return the fingerprinted URL path of name ("app.js", "css/site.css"),
+// for the links not rendered by templates. Unknown names are an error.
+func (a *Assets) Path(name string) (string, error) {
+	hashed, ok := a.hashed[strings.TrimPrefix(name, "/")]
+	if !ok {
+		return "", fmt.Errorf("glaze: unknown asset %q", name)
+	}
+	return a.prefix + "/" + hashed, nil
+}
+
+// serve the fingerprinted or plain file of the request path.
+func (a *Assets) serve(c *Context) {
+	if m := c.Request.Method; m != http.MethodGet && m != http.MethodHead {
+		c.Writer.Header().Set("Allow", "GET, HEAD")
+		c.defaultResponse(http.StatusMethodNotAllowed)
+		return
+	}
+	name := strings.TrimPrefix(c.Request.URL.Path, a.prefix+"/")
+	if file, ok := a.files[name]; ok {
+		c.Writer.Header().Set("Cache-Control", immutableCache)
+		serveFile(c, a.fsys, file, &a.etags)
+		return
+	}
+	if _, ok := a.hashed[name]; ok {
+		c.Writer.Header().Set("Cache-Control", "no-cache")
+		serveFile(c, a.fsys, name, &a.etags)
+		return
+	}
+	c.defaultResponse(http.StatusNotFound)
+}
+
+// funcMap return the template functions, SetFuncMap ones and asset.
+func (e *Engine) funcMap() template.FuncMap {
+	if e.assets == nil {
+		return e.templateFuncs
+	}
+	funcs := template.FuncMap{"asset": e.assets.Path}
+	for k, v := range e.templateFuncs {
+		funcs[k] = v
+	}
+	return funcs
+}
@@ -0,0 +1,260 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+// Package auth implements form based login on top of glaze sessions. The
+// logged in principal (a user id) is kept in the session, and restored
+// from a signed remember-me cookie once the session ended.
+//
+// Usage:
+//
+//	r.Use(glaze.Sessions(glaze.SessionConfig{Secret: sessionSecret}))
+//	r.Use(auth.Middleware(auth.Config{Secret: rememberSecret}))
+//
+//	r.Post("/login", func(c *glaze.Context) {
+//	    user, err := users.Check(c.Form("email"), c.Form("password"))
+//	    if err != nil {
+//	        c.Flash("error", "invalid email or password")
+//	        http.Redirect(c.Writer, c.Request, "/login", http.StatusSeeOther)
+//	        return
+//	    }
+//	    auth.Login(c, user.ID, c.Form("remember") == "on")
+//	    http.Redirect(c.Writer, c.Request, auth.ReturnTo(c, "/"), http.StatusSeeOther)
+//	})
+//	r.Post("/logout", func(c *glaze.Context) {
+//	    auth.Logout(c)
+//	    http.Redirect(c.Writer, c.Request, "/", http.StatusSeeOther)
+//	})
+//	r.Group("/account", auth.RequireLogin("/login")).Get("/", accountPage)
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/nrhox/glaze"
+)
+
+// session keys of the login
+const (
+	principalKey = "auth.principal"
+	loginAtKey   = "auth.login_at"
+)
+
+const defaultCookieName = "glaze_remember"
+
+// Config holds the configuration of the remember-me cookie.
+type Config struct {
+	// Secret sign the remember-me cookie, at least 32 bytes. Changing it
+	// log out every remembered browser.
+	Secret []byte
+
+	// CookieName of the remember-me cookie, default "glaze_remember".
+	CookieName string
+
+	// RememberFor is the lifetime of the remember-me cookie, default 30 days.
+	RememberFor time.Duration
+
+	// Validate is called before a principal is restored from the cookie,
+	// returning false reject it (deleted or disabled user, changed
+	// password). Default every valid cookie is accepted.
+	Validate func(c *glaze.Context, principal string) bool
+}
+
+// configKey is the context key of the middleware config.
+type configKey struct{}
+
+// remembered is the signed content of the remember-me cookie.
+type remembered struct {
+	Principal string `json:"p"`
+	ExpiresAt int64  `json:"e"`
+}
+
+// Middleware returns a middleware logging in again the browsers sending a
+// valid remember-me cookie without a logged in session. It must come after
+// glaze.Sessions, and is needed by Login with remember.
+func Middleware(cfg Config) glaze.HandlerFunc {
+	if len(cfg.Secret) < 32 {
+		panic("auth: Secret must be at least 32 bytes")
+	}
+	if cfg.CookieName == "" {
+		cfg.CookieName = defaultCookieName
+	}
+	if cfg.RememberFor <= 0 {
+		cfg.RememberFor = 30 * 24 * time.Hour
+	}
+
+	return func(c *glaze.Context) {
+		c.Set(configKey{}, &cfg)
+		if _, ok := Principal(c); ok {
+			return
+		}
+		raw, err := c.Request.Cookie(cfg.CookieName)
+		if err != nil {
+			return
+		}
+		var rem remembered
+		if readSigned(cfg.Secret, raw.Value, &rem) != nil || time.Now().Unix() > rem.ExpiresAt ||
+			cfg.Validate != nil && !cfg.Validate(c, rem.Principal) {
+			setCookie(c, cfg.CookieName, "", -1)
+			return
+		}
+		login(c, rem.Principal)
+	}
+}
+
+// Login store principal in the session, renewed. With remember a
+// remember-me cookie is set too, it need Middleware.
+func Login(c *glaze.Context, principal string, remember bool) {
+	login(c, principal)
+	if !remember {
+		return
+	}
+	cfg := configOf(c)
+	if cfg == nil {
+		panic("auth: remember-me need the auth Middleware")
+	}
+	value, err := sign(cfg.Secret, remembered{
+		Principal: principal,
+		ExpiresAt: time.Now().Add(cfg.RememberFor).Unix(),
+	})
+	if err != nil {
+		panic("auth: " + err.Error())
+	}
+	setCookie(c, cfg.CookieName, value, int(cfg.RememberFor.Seconds()))
+}
+
+func login(c *glaze.Context, principal string) {
+	s := c.Session()
+	s.Renew()
+	s.Set(principalKey, principal)
+	s.Set(loginAtKey, time.Now().Unix())
+}
+
+// Logout clear the session and delete the remember-me cookie.
+func Logout(c *glaze.Context) {
+	c.Session().Clear()
+	name := defaultCookieName
+	if cfg := configOf(c); cfg != nil {
+		name = cfg.CookieName
+	}
+	if _, err := c.Request.Cookie(name); err == nil {
+		setCookie(c, name, "", -1)
+	}
+}
+
+// Principal return the logged in principal and whether there is one.
+func Principal(c *glaze.Context) (string, bool) {
+	p, ok := c.Session().Get(principalKey).(string)
+	return p, ok && p != ""
+}
+
+// LoginTime return when the principal logged in, by form or remember-me
+// cookie, zero when nobody is logged in. Sensitive actions can ask the
+// password again when it is too old.
+func LoginTime(c *glaze.Context) time.Time {
+	switch at := c.Session().Get(loginAtKey).(type) {
+	case int64: // set by this request
+		return time.Unix(at, 0)
+	case float64: // JSON number of the cookie
+		return time.Unix(int64(at), 0)
+	}
+	return time.Time{}
+}
+
+// RequireLogin returns a middleware redirecting anonymous users to
+// loginPath with the "return_to" query parameter, see ReturnTo.
+func RequireLogin(loginPath string) glaze.HandlerFunc {
+	return func(c *glaze.Context) {
+		if _, ok := Principal(c); ok {
+			return
+		}
+		sep := "?"
+		if strings.Contains(loginPath, "?") {
+			sep = "&"
+		}
+		target := loginPath + sep + url.Values{"return_to": {c.Request.URL.RequestURI()}}.Encode()
+		http.Redirect(c.Writer, c.Request, target, http.StatusFound)
+		c.Abort()
+	}
+}
+
+// ReturnTo return the "return_to" query or form parameter set by
+// RequireLogin when it is a local path, fallback otherwise, so the
+// redirect after login can not lead to another site.
+func ReturnTo(c *glaze.Context, fallback string) string {
+	p := c.Query("return_to")
+	if p == "" {
+		p = c.Form("return_to")
+	}
+	if !strings.HasPrefix(p, "/") || strings.HasPrefix(p, "//") {
+		return fallback
+	}
+	// browsers drop tabs and newlines and read a backslash as "/", so
+	// "/<tab>/evil" would become "//evil"
+	for i := 0; i < len(p); i++ {
+		if p[i] < 0x20 || p[i] == 0x7f || p[i] == '\\' {
+			return fallback
+		}
+	}
+	if u, err := url.Parse(p); err != nil || u.Scheme != "" || u.Host != "" {
+		return fallback
+	}
+	return p
+}
+
+func configOf(c *glaze.Context) *Config {
+	v, _ := c.Get(configKey{})
+	cfg, _ := v.(*Config)
+	return cfg
+}
+
+// setCookie write the remember-me cookie, Secure for HTTPS requests.
+func setCookie(c *glaze.Context, name, value string, maxAge int) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		MaxAge:   maxAge,
+		Secure:   c.Scheme() == "https",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+var errInvalidCookie = errors.New("auth: invalid cookie signature")
+
+// sign encode v as base64(json) + "." + base64(hmac).
+func sign(secret []byte, v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	payload := base64.RawURLEncoding.EncodeToString(data)
+	return payload + "." + mac(secret, payload), nil
+}
+
+// readSigned verify and decode a value made by sign.
+func readSigned(secret []byte, value string, v any) error {
+	payload, sig, ok := strings.Cut(value, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(mac(secret, payload))) {
+		return errInvalidCookie
+	}
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func mac(secret []byte, payload string) string {
+	h := hmac.New(sha256.New, secret)
+	h.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,134 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nrhox/glaze"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	sessionSecret  = []byte(strings.Repeat("s", 32))
+	rememberSecret = []byte(strings.Repeat("r", 32))
+)
+
+func TestLoginFlow(t *testing.T) {
+	disabled := map[string]bool{}
+	r := glaze.New()
+	r.Use(glaze.Sessions(glaze.SessionConfig{Secret: sessionSecret}))
+	r.Use(Middleware(Config{Secret: rememberSecret, Validate: func(c *glaze.Context, p string) bool {
+		return !disabled[p]
+	}}))
+	r.Post("/login", func(c *glaze.Context) {
+		Login(c, c.Form("user"), c.Form("remember") == "on")
+		http.Redirect(c.Writer, c.Request, ReturnTo(c, "/"), http.StatusSeeOther)
+	})
+	r.Post("/logout", func(c *glaze.Context) {
+		Logout(c)
+		c.Writer.WriteHeader(http.StatusNoContent)
+	})
+	r.Group("/account", RequireLogin("/login")).Get("/", func(c *glaze.Context) {
+		p, _ := Principal(c)
+		c.String(http.StatusOK, p)
+	})
+
+	do := func(req *http.Request, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+		for _, ck := range cookies {
+			req.AddCookie(ck)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	login := func(form string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/login?return_to=%2Faccount%2F", strings.NewReader(form))
+		req.Header.Set("Content-Type", glaze.MIME_POST_FORM)
+		return do(req)
+	}
+	cookie := func(w *httptest.ResponseRecorder, name string) *http.Cookie {
+		for _, ck := range w.Result().Cookies() {
+			if ck.Name == name {
+				return ck
+			}
+		}
+		return nil
+	}
+
+	// anonymous
+	w := do(httptest.NewRequest("GET", "/account/?tab=1", nil))
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "/login?return_to="+url.QueryEscape("/account/?tab=1"), w.Header().Get("Location"))
+
+	// login without remember
+	w = login("user=alice")
+	assert.Equal(t, "/account/", w.Header().Get("Location"))
+	assert.Nil(t, cookie(w, "glaze_remember"))
+	session := cookie(w, "glaze_session")
+	assert.Equal(t, "alice", do(httptest.NewRequest("GET", "/account/", nil), session).Body.String())
+
+	// remember-me restore the login without session
+	w = login("user=bob&remember=on")
+	remember := cookie(w, "glaze_remember")
+	assert.NotNil(t, remember)
+	assert.Equal(t, 30*24*3600, remember.MaxAge)
+	w = do(httptest.NewRequest("GET", "/account/", nil), remember)
+	assert.Equal(t, "bob", w.Body.String())
+	assert.NotNil(t, cookie(w, "glaze_session"), "session restored")
+
+	// tampered and rejected cookies
+	bad := *remember
+	bad.Value = strings.Replace(bad.Value, ".", ".x", 1)
+	assert.Equal(t, http.StatusFound, do(httptest.NewRequest("GET", "/account/", nil), &bad).Code)
+	disabled["bob"] = true
+	w = do(httptest.NewRequest("GET", "/account/", nil), remember)
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, -1, cookie(w, "glaze_remember").MaxAge)
+	disabled["bob"] = false
+
+	// logout delete both cookies
+	w = do(httptest.NewRequest("POST", "/logout", nil), remember)
+	assert.Equal(t, -1, cookie(w, "glaze_session").MaxAge)
+	assert.Equal(t, -1, cookie(w, "glaze_remember").MaxAge)
+}
+
+func TestReturnTo(t *testing.T) {
+	for target, want := range map[string]string{
+		"/orders?id=1":     "/orders?id=1",
+		"//evil.example":   "/",
+		"/\\evil.example":  "/",
+		"https://evil":     "/",
+		"/\t/evil.example": "/",
+		"/\n/evil.example": "/",
+		"/\r/evil.example": "/",
+		"/\x7f/evil":       "/",
+		"/a\\b":            "/",
+		"/a:b/c":           "/a:b/c",
+		"":                 "/",
+	} {
+		r := glaze.New()
+		var got string
+		r.Get("/login", func(c *glaze.Context) { got = ReturnTo(c, "/") })
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/login?return_to="+url.QueryEscape(target), nil))
+		assert.Equal(t, want, got, target)
+	}
+}
+
+func TestLoginTime(t *testing.T) {
+	r := glaze.New()
+	r.Use(glaze.Sessions(glaze.SessionConfig{Secret: sessionSecret}))
+	r.Get("/", func(c *glaze.Context) {
+		assert.True(t, LoginTime(c).IsZero())
+		assert.Panics(t, func() { Login(c, "alice", true) }, "remember without Middleware")
+		assert.WithinDuration(t, time.Now(), LoginTime(c), time.Minute)
+	})
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	assert.Panics(t, func() { Middleware(Config{Secret: []byte("short")}) })
+}
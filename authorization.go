@@ -0,0 +1,136 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"net/http"
+)
+
+// Route metadata keys read by the Authorize middleware.
+const (
+	MetaRoles       = "glaze.roles"
+	MetaPermissions = "glaze.permissions"
+)
+
+// Principal is the authenticated identity of a request.
+// Authentication middleware put it in the context with SetPrincipal.
+type Principal interface {
+	HasRole(role string) bool
+	HasPermission(permission string) bool
+}
+
+// principalKey is the context key of the principal.
+type principalKey struct{}
+
+// SetPrincipal store the authenticated principal of the request.
+func (c *Context) SetPrincipal(p Principal) {
+	c.Set(principalKey{}, p)
+}
+
+// Principal return the principal set by authentication middleware, or nil.
+func (c *Context) Principal() Principal {
+	v, _ := c.Get(principalKey{})
+	p, _ := v.(Principal)
+	return p
+}
+
+// Roles returns a copy of the group where routes require one of the roles.
+//
+//	admin := r.Group("/admin").Roles("admin", "owner")
+func (r *Route) Roles(roles ...string) *Route {
+	return r.Meta(MetaRoles, roles)
+}
+
+// Permissions returns a copy of the group where routes require all the permissions.
+//
+//	r.Permissions("invoice:write").Post("/invoices", createInvoice)
+func (r *Route) Permissions(permissions ...string) *Route {
+	return r.Meta(MetaPermissions, permissions)
+}
+
+// AuthorizeConfig holds the configuration of the authorization middleware.
+type AuthorizeConfig struct {
+	// Unauthorized is called when the route require roles or permissions
+	// but no principal is set, default respond 401.
+	Unauthorized HandlerFunc
+
+	// Forbidden is called when the principal miss a role or permission,
+	// default respond 403.
+	Forbidden HandlerFunc
+}
+
+// Authorize returns a middleware that check the roles and permissions
+// declared on the matched route (see Route.Roles and Route.Permissions)
+// against the principal set by the authentication middleware.
+// Routes without requirements are not checked.
+//
+// Usage:
+//
+//	r.Use(authMiddleware(), glaze.Authorize())
+//	r.Roles("admin").Delete("/users/:id", deleteUser)
+func Authorize() HandlerFunc {
+	return AuthorizeWithConfig(AuthorizeConfig{})
+}
+
+// AuthorizeWithConfig returns an authorization middleware with custom config.
+func AuthorizeWithConfig(cfg AuthorizeConfig) HandlerFunc {
+	if cfg.Unauthorized == nil {
+		cfg.Unauthorized = func(c *Context) {
+			c.String(http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
+		}
+	}
+	if cfg.Forbidden == nil {
+		cfg.Forbidden = func(c *Context) {
+			c.String(http.StatusForbidden, http.StatusText(http.StatusForbidden))
+		}
+	}
+
+	return func(c *Context) {
+		roles := metaStrings(c, MetaRoles)
+		permissions := metaStrings(c, MetaPermissions)
+		if len(roles) == 0 && len(permissions) == 0 {
+			return // public route
+		}
+
+		p := c.Principal()
+		if p == nil {
+			cfg.Unauthorized(c)
+			c.Abort()
+			return
+		}
+		if !authorized(p, roles, permissions) {
+			cfg.Forbidden(c)
+			c.Abort()
+		}
+	}
+}
+
+// authorized report whether p has one of roles and all permissions.
+func authorized(p Principal, roles, permissions []string) bool {
+	if len(roles) > 0 {
+		ok := false
+		for _, role := range roles {
+			if p.HasRole(role) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	for _, perm := range permissions {
+		if !p.HasPermission(perm) {
+			return false
+		}
+	}
+	return true
+}
+
+// metaStrings read a []string route metadata.
+func metaStrings(c *Context, key string) []string {
+	v, _ := c.RouteMeta(key)
+	s, _ := v.([]string)
+	return s
+}
@@ -0,0 +1,99 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+// Package autotls serve a glaze engine over HTTPS with certificates
+// obtained automatically from Let's Encrypt (ACME).
+//
+// It is a module of its own, github.com/nrhox/glaze/autotls, so the glaze
+// core keep depending only on the standard library; for the same reason it
+// is a function taking the engine and not an Engine method.
+//
+// Usage:
+//
+//	r := glaze.New()
+//	log.Fatal(autotls.Run(r, "example.com", "www.example.com"))
+package autotls
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/nrhox/glaze"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// Manager provides the certificates and the HTTP-01 challenge handler,
+// *autocert.Manager implements it.
+type Manager interface {
+	HTTPHandler(fallback http.Handler) http.Handler
+	TLSConfig() *tls.Config
+}
+
+// addresses of the challenge and HTTPS servers, changed by the tests
+var (
+	httpAddr  = ":http"
+	httpsAddr = ":https"
+)
+
+// Run serve e on :443 for the domains, with certificates cached in
+// the user cache directory ("glaze-autocert"). The HTTP-01 challenge
+// handler listen on :80 and redirect other requests to HTTPS.
+func Run(e *glaze.Engine, domains ...string) error {
+	return RunWithManager(e, NewManager(DefaultCacheDir(), domains...))
+}
+
+// NewManager create an autocert manager accepting the terms of service
+// and limited to the domains.
+func NewManager(cacheDir string, domains ...string) *autocert.Manager {
+	return &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: autocert.HostWhitelist(domains...),
+		Cache:      autocert.DirCache(cacheDir),
+	}
+}
+
+// RunWithManager serve e on :443 with a custom manager (email, cache
+// backend, host policy) and the challenge handler on :80. When one of the
+// servers stops, the other is closed too.
+func RunWithManager(e *glaze.Engine, m Manager) error {
+	httpLn, err := net.Listen("tcp", httpAddr)
+	if err != nil {
+		return err
+	}
+	tlsLn, err := net.Listen("tcp", httpsAddr)
+	if err != nil {
+		httpLn.Close()
+		return err
+	}
+	cfg := m.TLSConfig()
+	cfg.MinVersion = tls.VersionTLS12
+
+	// challenge server, redirect everything else to https
+	challenge := &http.Server{Handler: m.HTTPHandler(nil)}
+	errc := make(chan error, 2)
+	go func() {
+		errc <- challenge.Serve(httpLn)
+	}()
+	go func() {
+		errc <- e.RunListener(tls.NewListener(tlsLn, cfg))
+	}()
+
+	err = <-errc
+	challenge.Close()
+	httpLn.Close() // Serve may not have started yet
+	tlsLn.Close()
+	return err
+}
+
+// DefaultCacheDir return the certificate cache directory,
+// "glaze-autocert" inside the user cache directory, or the temp directory as last resort.
+func DefaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "glaze-autocert")
+}
@@ -0,0 +1,88 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package autotls
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/nrhox/glaze"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubManager answer the challenges with a fixed body.
+type stubManager struct{}
+
+func (stubManager) HTTPHandler(http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "challenge "+r.URL.Path)
+	})
+}
+
+func (stubManager) TLSConfig() *tls.Config { return &tls.Config{} }
+
+// useFreeAddrs point the servers to free local ports.
+func useFreeAddrs(t *testing.T) {
+	free := func() string {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer l.Close()
+		return l.Addr().String()
+	}
+	oldHTTP, oldHTTPS := httpAddr, httpsAddr
+	httpAddr, httpsAddr = free(), free()
+	t.Cleanup(func() { httpAddr, httpsAddr = oldHTTP, oldHTTPS })
+}
+
+// assertFree fail when addr is still listened on.
+func assertFree(t *testing.T, addr string) {
+	l, err := net.Listen("tcp", addr)
+	if assert.NoError(t, err, "challenge server still listening") {
+		l.Close()
+	}
+}
+
+func TestRunWithManager(t *testing.T) {
+	useFreeAddrs(t)
+	e := glaze.New()
+	done := make(chan error, 1)
+	go func() { done <- RunWithManager(e, stubManager{}) }()
+	<-e.Ready()
+
+	resp, err := http.Get("http://" + httpAddr + "/.well-known/acme-challenge/x")
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, "challenge /.well-known/acme-challenge/x", string(body))
+
+	require.NoError(t, e.Shutdown(context.Background()))
+	assert.ErrorIs(t, <-done, http.ErrServerClosed)
+	assertFree(t, httpAddr)
+}
+
+func TestRunWithManagerListenError(t *testing.T) {
+	useFreeAddrs(t)
+	busy, err := net.Listen("tcp", httpsAddr)
+	require.NoError(t, err)
+	defer busy.Close()
+
+	assert.Error(t, RunWithManager(glaze.New(), stubManager{}))
+	assertFree(t, httpAddr)
+}
+
+func TestRunWithManagerServeError(t *testing.T) {
+	useFreeAddrs(t)
+	e := glaze.New()
+	e.OnStart(func(context.Context) error { return errors.New("boom") })
+
+	assert.ErrorContains(t, RunWithManager(e, stubManager{}), "boom")
+	assertFree(t, httpAddr)
+	assertFree(t, httpsAddr)
+}
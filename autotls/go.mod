@@ -0,0 +1,19 @@
+module github.com/nrhox/glaze/autotls
+
+go 1.24.0
+
+require (
+	github.com/nrhox/glaze v0.0.0
+	github.com/stretchr/testify v1.10.0
+	golang.org/x/crypto v0.40.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/net v0.41.0 // indirect
+	golang.org/x/text v0.27.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/nrhox/glaze => ../
@@ -0,0 +1,90 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"context"
+	"maps"
+	"runtime/debug"
+	"slices"
+)
+
+// Copy return a copy of the context safe to use after the handler returned,
+// in another goroutine. It has the request, params, keys and matched route
+// but no Writer: the response belong to the original context.
+func (c *Context) Copy() *Context {
+	cp := &Context{
+		Request:   c.Request,
+		Params:    slices.Clone(c.Params),
+		querys:    c.querys,
+		index:     len(c.handlers),
+		engine:    c.engine,
+		route:     c.route,
+		clientIP:  c.clientIP,
+		scheme:    c.scheme,
+		host:      c.host,
+		forwarded: c.forwarded,
+		stopped:   true,
+	}
+	c.mu.RLock()
+	cp.Keys = maps.Clone(c.Keys)
+	c.mu.RUnlock()
+	return cp
+}
+
+// Go run fn in a new goroutine after the response, for work like sending
+// emails or webhooks. fn get a context with the values of the request
+// context, not canceled when the request end, but canceled when
+// Engine.Shutdown give up waiting. Shutdown and ListenAndGraceful wait for
+// the running fn. Panics are recovered, logged and sent to the engine
+// Reporter with a Copy of c.
+//
+// Usage:
+//
+//	r.Post("/signup", func(c *glaze.Context) {
+//	    user := createUser(c)
+//	    c.Go(func(ctx context.Context) {
+//	        mailer.SendWelcome(ctx, user.Email)
+//	    })
+//	    c.JSON(http.StatusCreated, user)
+//	})
+func (c *Context) Go(fn func(ctx context.Context)) {
+	cp := c.Copy()
+	e := c.engine
+	ctx, cancel := context.WithCancel(context.WithoutCancel(c.Request.Context()))
+	stop := context.AfterFunc(e.background, cancel)
+
+	e.running.Add(1)
+	go func() {
+		defer e.running.Done()
+		defer cancel()
+		defer stop()
+		defer func() {
+			if r := recover(); r != nil {
+				report := newPanicReport(cp, r, debug.Stack(), DefaultRedactHeaders, nil)
+				e.logf(LogLevelError, "%s\n", report.String())
+				if e.reporter != nil {
+					e.reporter.ReportPanic(cp, report)
+				}
+			}
+		}()
+		fn(ctx)
+	}()
+}
+
+// waitBackground wait for the Go goroutines, or cancel them when ctx is done.
+func (e *Engine) waitBackground(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		e.running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		e.cancelBackground()
+		return ctx.Err()
+	}
+}
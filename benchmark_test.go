@@ -25,7 +25,15 @@ func Benchmark10Params(B *testing.B) {
 	router := New()
 	router.Use(func(c *Context) {})
 	router.Get("/param/:param1/:params2/:param3/:param4/:param5/:param6/:param7/:param8/:param9/:param10", func(c *Context) {})
-	runRequest(B, router, http.MethodGet, "/param/horeg/with/test/performance/and/wish/performance/lessthen/700ns")
+	runRequest(B, router, http.MethodGet, "/param/horeg/with/test/performance/and/wish/performance/less/then/700ns")
+}
+
+func BenchmarkDeepStatic(B *testing.B) {
+	router := New()
+	router.Get("/api/v1/organizations/members/settings/notifications", func(c *Context) {})
+	router.Get("/api/v1/organizations/members/settings/profile", func(c *Context) {})
+	router.Get("/api/v1/organizations/projects", func(c *Context) {})
+	runRequest(B, router, http.MethodGet, "/api/v1/organizations/members/settings/notifications")
 }
 
 type mockRequest struct {
@@ -64,3 +72,30 @@ func runRequest(B *testing.B, r *Engine, method, path string) {
 		r.ServeHTTP(w, req)
 	}
 }
+
+func BenchmarkQuery(B *testing.B) {
+	router := New()
+	router.Get("/search", func(c *Context) { c.Query("q") })
+	runRequest(B, router, http.MethodGet, "/search?q=glaze")
+}
+
+// TestZeroAllocs keep the hot path of static and param routes allocation free.
+func TestZeroAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("sync.Pool drop items at random under the race detector")
+	}
+	router := New()
+	router.Use(func(c *Context) {})
+	router.Get("/ping", func(c *Context) {})
+	router.Get("/users/:id/posts/:post", func(c *Context) { c.Param("post") })
+
+	for _, path := range []string{"/ping", "/users/42/posts/7"} {
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		w := newMockRequest()
+		router.ServeHTTP(w, req) // warm the pool
+		allocs := testing.AllocsPerRun(100, func() { router.ServeHTTP(w, req) })
+		if allocs != 0 {
+			t.Errorf("GET %s: %v allocs/op, want 0", path, allocs)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// Bind decode the request into dst, a pointer to struct, then Validate it:
+//   - the body, as JSON for JSON content types (application/json, */*+json),
+//     or form values into fields tagged `form` for form content types
+//   - path parameters into fields tagged `path`
+//   - query parameters into fields tagged `query`
+//   - request headers into fields tagged `header`
+//
+// Tagged fields can be strings, bools, numbers, time.Duration, types
+// implementing encoding.TextUnmarshaler, pointers and slices of them.
+//
+// The bound value is kept on the context (see BoundValue): when a
+// middleware already bound the request into the same type, dst is set
+// from it without reading the body again.
+//
+// Usage:
+//
+//	type UpdateUser struct {
+//	    ID    int    `path:"id"`
+//	    Force bool   `query:"force"`
+//	    Name  string `json:"name" validate:"required"`
+//	}
+//
+//	var in UpdateUser
+//	if err := c.Bind(&in); err != nil {
+//	    c.JSON(http.StatusBadRequest, glaze.M{"error": err.Error()})
+//	    return
+//	}
+func (c *Context) Bind(dst any) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("glaze: Bind need a non nil pointer to struct")
+	}
+	if c.bound != nil && reflect.TypeOf(c.bound) == rv.Type() {
+		rv.Elem().Set(reflect.ValueOf(c.bound).Elem())
+		return nil
+	}
+
+	if err := c.bindBody(dst); err != nil {
+		return err
+	}
+	if err := c.bindParams(rv.Elem()); err != nil {
+		c.observeBody(BodyEvent{Kind: "params", Err: err})
+		return err
+	}
+	if err := Validate(dst); err != nil {
+		c.observeBody(BodyEvent{Kind: "validation", Err: err})
+		return err
+	}
+	c.bound = dst
+	return nil
+}
+
+// BoundValue return the pointer given to the last successful Bind of the
+// request, nil when nothing was bound. It let a middleware validating the
+// request hand the decoded struct to the handler.
+//
+// Usage:
+//
+//	api.Use(func(c *glaze.Context) {
+//	    var in CreateOrder
+//	    if err := c.Bind(&in); err != nil {
+//	        c.JSON(http.StatusBadRequest, glaze.M{"error": err.Error()})
+//	        c.Abort()
+//	        return
+//	    }
+//	    c.Next()
+//	})
+//
+//	in := c.BoundValue().(*CreateOrder)
+func (c *Context) BoundValue() any {
+	return c.bound
+}
+
+// bindParams fill the path, query and header fields of v.
+func (c *Context) bindParams(v reflect.Value) error {
+	if err := bindValues(v, "path", func(name string) []string {
+		if v, ok := c.Params.Get(name); ok {
+			return []string{v}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if err := bindValues(v, "query", func(name string) []string {
+		return c.queryValues()[name]
+	}); err != nil {
+		return err
+	}
+	return bindValues(v, "header", func(name string) []string {
+		return c.Request.Header.Values(name)
+	})
+}
+
+// bindBody decode the JSON or form body, other content types are ignored.
+func (c *Context) bindBody(dst any) error {
+	if c.Request.Body == nil || c.Request.Body == http.NoBody {
+		return nil
+	}
+	mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	switch {
+	case isJSONMediaType(mediaType):
+		counter := c.countBody()
+		err := json.NewDecoder(c.Request.Body).Decode(dst)
+		if err == io.EOF {
+			err = nil
+		} else if err != nil {
+			err = jsonBindError(err)
+		}
+		c.observeBody(BodyEvent{Kind: "json", Size: c.restoreBody(counter), Err: err})
+		return err
+	case mediaType == MIME_MULTIPART_POST_FORM:
+		if err := c.parseMultipart(); err != nil {
+			return err
+		}
+		return bindValues(reflect.ValueOf(dst).Elem(), "form", func(name string) []string {
+			return c.Request.PostForm[name]
+		})
+	case mediaType == MIME_POST_FORM:
+		counter := c.countBody()
+		err := c.Request.ParseForm()
+		if err == nil {
+			err = bindValues(reflect.ValueOf(dst).Elem(), "form", func(name string) []string {
+				return c.Request.PostForm[name]
+			})
+		}
+		c.observeBody(BodyEvent{Kind: "form", Size: c.restoreBody(counter), Err: err})
+		return err
+	}
+	return nil
+}
+
+// isJSONMediaType report whether the media type is JSON, including "+json" suffixes.
+func isJSONMediaType(mediaType string) bool {
+	return mediaType == MIME_JSON || len(mediaType) > 5 && mediaType[len(mediaType)-5:] == "+json"
+}
+
+var (
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+	durationType        = reflect.TypeFor[time.Duration]()
+)
+
+// bindValues set the fields tagged tag from lookup, embedded structs are walked.
+func bindValues(v reflect.Value, tag string, lookup func(name string) []string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		fv := v.Field(i)
+		if sf.Anonymous && fv.Kind() == reflect.Struct {
+			if err := bindValues(fv, tag, lookup); err != nil {
+				return err
+			}
+			continue
+		}
+		name := sf.Tag.Get(tag)
+		if name == "" || name == "-" || !sf.IsExported() {
+			continue
+		}
+		values := lookup(name)
+		if len(values) == 0 {
+			continue
+		}
+		if raw, err := setField(fv, values); err != nil {
+			return paramBindError(tag, name, raw, fv.Type(), err)
+		}
+	}
+	return nil
+}
+
+// setField set v from the raw string values, it return the value failing.
+func setField(v reflect.Value, values []string) (string, error) {
+	if v.Kind() == reflect.Slice && !v.Type().Implements(textUnmarshalerType) &&
+		!reflect.PointerTo(v.Type()).Implements(textUnmarshalerType) {
+		slice := reflect.MakeSlice(v.Type(), len(values), len(values))
+		for i, s := range values {
+			if err := setValue(slice.Index(i), s); err != nil {
+				return s, err
+			}
+		}
+		v.Set(slice)
+		return "", nil
+	}
+	return values[0], setValue(v, values[0])
+}
+
+// setValue parse s into v.
+func setValue(v reflect.Value, s string) error {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return setValue(v.Elem(), s)
+	}
+	if v.CanAddr() && v.Addr().Type().Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return errors.New("unsupported type " + v.Type().String())
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strconv"
+)
+
+// BindError is a request value Bind could not decode, so API layers can
+// answer field by field without parsing the encoding/json messages. The
+// failed validation rules are ValidationErrors, not BindError.
+//
+// Usage:
+//
+//	var berr *glaze.BindError
+//	if err := c.Bind(&in); errors.As(err, &berr) {
+//	    c.JSON(http.StatusBadRequest, glaze.M{"field": berr.Field, "in": berr.Location, "error": berr.Reason})
+//	    return
+//	}
+type BindError struct {
+	Field    string // tag name of the parameter, JSON path for the body ("address.city"), "" for malformed bodies
+	Location string // where the value was: body, form, path, query or header
+	Reason   string // english message, "must be an integer"
+	Value    string // raw value sent; for JSON the kind of the value ("string", "number -1")
+	Err      error  // error of the parser
+}
+
+// Error return "query parameter "page": must be an integer", and
+// "invalid JSON body: ..." for JSON bodies (the Reason when Err is nil).
+func (e *BindError) Error() string {
+	if e.Location == "body" {
+		if e.Err == nil {
+			return "invalid JSON body: " + e.Reason
+		}
+		return "invalid JSON body: " + e.Err.Error()
+	}
+	return e.Location + " parameter " + strconv.Quote(e.Field) + ": " + e.Reason
+}
+
+func (e *BindError) Unwrap() error {
+	return e.Err
+}
+
+// jsonBindError describe a JSON decoding error of the body.
+func jsonBindError(err error) *BindError {
+	berr := &BindError{Location: "body", Reason: err.Error(), Err: err}
+	var (
+		typeErr   *json.UnmarshalTypeError
+		syntaxErr *json.SyntaxError
+	)
+	switch {
+	case errors.As(err, &typeErr):
+		berr.Field, berr.Value = typeErr.Field, typeErr.Value
+		berr.Reason = typeReason(typeErr.Type)
+	case errors.As(err, &syntaxErr), errors.Is(err, io.ErrUnexpectedEOF):
+		berr.Reason = "is not valid JSON"
+	}
+	return berr
+}
+
+// paramBindError describe a parameter value s not parsed into the field
+// of type t, a slice for the repeated parameters.
+func paramBindError(location, name, s string, t reflect.Type, err error) *BindError {
+	if t.Kind() == reflect.Slice && !reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		t = t.Elem()
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	reason := err.Error()
+	var numErr *strconv.NumError
+	switch {
+	case errors.As(err, &numErr) && numErr.Err == strconv.ErrRange:
+		reason = "is out of range"
+	case t == durationType:
+		reason = "must be a duration"
+	case reflect.PointerTo(t).Implements(textUnmarshalerType):
+		// message of UnmarshalText
+	case numErr != nil:
+		reason = typeReason(t)
+	}
+	return &BindError{Field: name, Location: location, Reason: reason, Value: s, Err: err}
+}
+
+// typeReason return the message of a value not of the kind of t.
+func typeReason(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "must be an integer"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "must be a non-negative integer"
+	case reflect.Float32, reflect.Float64:
+		return "must be a number"
+	case reflect.Bool:
+		return "must be a boolean"
+	case reflect.String:
+		return "must be a string"
+	case reflect.Slice, reflect.Array:
+		return "must be an array"
+	case reflect.Struct, reflect.Map:
+		return "must be an object"
+	}
+	return "has an invalid type"
+}
@@ -0,0 +1,81 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// BodyTransform convert a request body (decryption, envelope removal,
+// legacy format). It can change the Content-Type header of c.Request when
+// the format change, so Bind decode the new body.
+type BodyTransform func(c *Context, body []byte) ([]byte, error)
+
+// TransformBody returns a middleware replacing the request body with the
+// result of the transforms, applied in order, see Context.TransformBody.
+// Transform errors are rendered like bind errors (engine mappings,
+// HTTPError, else 400) and abort the request.
+//
+// Usage:
+//
+//	r.Post("/legacy/orders", glaze.TransformBody(xmlToJSON), createOrder)
+func TransformBody(transforms ...BodyTransform) HandlerFunc {
+	return func(c *Context) {
+		for _, t := range transforms {
+			if err := c.TransformBody(t); err != nil {
+				c.renderBindError(err)
+				c.Abort()
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// TransformBody read the request body, apply t and make the result the
+// new body, seen by Bind and every later reader. The body the client sent
+// is kept for auditing, see OriginalBody. The whole body is read in memory,
+// limit its size first with http.MaxBytesReader.
+func (c *Context) TransformBody(t BodyTransform) error {
+	var body []byte
+	if c.Request.Body != nil && c.Request.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(c.Request.Body)
+		c.Request.Body.Close()
+		if err != nil {
+			return err
+		}
+	}
+	if c.originalBody == nil {
+		c.originalBody = body
+		if c.originalBody == nil {
+			c.originalBody = []byte{}
+		}
+	}
+
+	body, err := t(c, body)
+	if err != nil {
+		return err
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	c.Request.ContentLength = int64(len(body))
+	c.Request.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	c.Request.GetBody = nil
+	return nil
+}
+
+// OriginalBody return the body sent by the client before the first
+// TransformBody, false when the body was not transformed.
+//
+// Usage:
+//
+//	if raw, ok := c.OriginalBody(); ok {
+//	    audit.Record(c.Request.URL.Path, raw)
+//	}
+func (c *Context) OriginalBody() ([]byte, bool) {
+	return c.originalBody, c.originalBody != nil
+}
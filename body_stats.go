@@ -0,0 +1,167 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"errors"
+	"io"
+	"os"
+	"sync/atomic"
+)
+
+// BodySizeBuckets are the upper bounds of the BodyStats.Sizes histogram.
+var BodySizeBuckets = [...]int64{1 << 10, 16 << 10, 256 << 10, 1 << 20, 16 << 20}
+
+// BodyEvent is a request body parsed by the binders, or a binding failure,
+// passed to the WithBodyObserver hook.
+type BodyEvent struct {
+	// Kind is the parser: json, form, multipart or ndjson; params for the
+	// path, query and header fields of Bind and validation for Validate.
+	Kind string
+
+	Size int64 // bytes read from the body, 0 for params and validation
+	Err  error // the parse or validation error, nil on success
+
+	// Spilled is the number of multipart files written to temporary files,
+	// over the memory limit (Engine.MultipartMemory, MultipartLimits).
+	Spilled      int
+	SpilledBytes int64
+}
+
+// BodyStats is a snapshot of the body parsing counters, to tune
+// MultipartMemory and the size limits from data.
+type BodyStats struct {
+	Parsed             uint64 // bodies parsed, failed ones included
+	Bytes              uint64 // total size of the parsed bodies
+	Failures           uint64 // malformed bodies and parameters, size limits
+	ValidationFailures uint64 // Validate errors, multipart files over MaxFileSize
+
+	// Sizes count the parsed bodies by size, Sizes[i] for sizes up to
+	// BodySizeBuckets[i] and the last one for the larger bodies.
+	Sizes [len(BodySizeBuckets) + 1]uint64
+
+	Spills       uint64 // multipart files written to temporary files
+	SpilledBytes uint64
+}
+
+// bodyStats hold the counters of BodyStats.
+type bodyStats struct {
+	parsed, bytes, failures, validation atomic.Uint64
+	sizes                               [len(BodySizeBuckets) + 1]atomic.Uint64
+	spills, spilledBytes                atomic.Uint64
+	observer                            func(*Context, BodyEvent)
+}
+
+// WithBodyObserver register fn called for every parsed body and binding
+// failure, after the engine counters are updated, to feed a metrics library.
+//
+// Usage:
+//
+//	e := glaze.New(glaze.WithBodyObserver(func(c *glaze.Context, ev glaze.BodyEvent) {
+//	    bodySize.WithLabelValues(ev.Kind).Observe(float64(ev.Size))
+//	    if ev.Err != nil {
+//	        bindFailures.WithLabelValues(ev.Kind).Inc()
+//	    }
+//	}))
+func WithBodyObserver(fn func(c *Context, ev BodyEvent)) ConfigsFunc {
+	return func(e *Engine) {
+		e.bodyStats.observer = fn
+	}
+}
+
+// BodyStats return the current body parsing counters.
+func (e *Engine) BodyStats() BodyStats {
+	s := &e.bodyStats
+	stats := BodyStats{
+		Parsed:             s.parsed.Load(),
+		Bytes:              s.bytes.Load(),
+		Failures:           s.failures.Load(),
+		ValidationFailures: s.validation.Load(),
+		Spills:             s.spills.Load(),
+		SpilledBytes:       s.spilledBytes.Load(),
+	}
+	for i := range s.sizes {
+		stats.Sizes[i] = s.sizes[i].Load()
+	}
+	return stats
+}
+
+// observeBody update the counters with ev and call the observer.
+func (c *Context) observeBody(ev BodyEvent) {
+	s := &c.engine.bodyStats
+	if ev.Kind != "params" && ev.Kind != "validation" {
+		s.parsed.Add(1)
+		s.bytes.Add(uint64(ev.Size))
+		i := 0
+		for i < len(BodySizeBuckets) && ev.Size > BodySizeBuckets[i] {
+			i++
+		}
+		s.sizes[i].Add(1)
+	}
+	if ev.Err != nil {
+		var verrs ValidationErrors
+		if errors.As(ev.Err, &verrs) {
+			s.validation.Add(1)
+		} else {
+			s.failures.Add(1)
+		}
+	}
+	if ev.Spilled > 0 {
+		s.spills.Add(uint64(ev.Spilled))
+		s.spilledBytes.Add(uint64(ev.SpilledBytes))
+	}
+	if s.observer != nil {
+		s.observer(c, ev)
+	}
+}
+
+// countingBody count the bytes read from a request body.
+type countingBody struct {
+	body io.ReadCloser
+	n    int64
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.body.Read(p)
+	b.n += int64(n)
+	return n, err
+}
+
+func (b *countingBody) Close() error { return b.body.Close() }
+
+// countBody replace the request body with a countingBody.
+func (c *Context) countBody() *countingBody {
+	counter := &countingBody{body: c.Request.Body}
+	c.Request.Body = counter
+	return counter
+}
+
+// restoreBody put the body replaced by countBody back and return the
+// bytes read.
+func (c *Context) restoreBody(counter *countingBody) int64 {
+	c.Request.Body = counter.body
+	return counter.n
+}
+
+// multipartSpills return the number and size of the files of the parsed
+// multipart form stored in temporary files.
+func (c *Context) multipartSpills() (n int, size int64) {
+	if c.Request.MultipartForm == nil {
+		return 0, 0
+	}
+	for _, files := range c.Request.MultipartForm.File {
+		for _, fh := range files {
+			f, err := fh.Open()
+			if err != nil {
+				continue
+			}
+			if _, onDisk := f.(*os.File); onDisk {
+				n++
+				size += fh.Size
+			}
+			f.Close()
+		}
+	}
+	return n, size
+}
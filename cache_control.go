@@ -0,0 +1,23 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+// CacheControl returns a middleware setting the Cache-Control response
+// header, attach it on a group or a route. Handlers can still override
+// the header for a single response.
+//
+// Usage:
+//
+//	assets := r.Group("/assets", glaze.CacheControl("public, max-age=3600"))
+func CacheControl(value string) HandlerFunc {
+	return func(c *Context) {
+		c.Writer.Header().Set("Cache-Control", value)
+	}
+}
+
+// NoStore returns a middleware forbidding any cache to store the
+// response, the usual policy of JSON APIs with private data.
+func NoStore() HandlerFunc {
+	return CacheControl("no-store")
+}
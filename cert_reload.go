@@ -0,0 +1,168 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// CertReloader serve a certificate/key pair that can be reloaded from
+// disk without restarting the server, for certificates rotated by
+// cert-manager or an ACME sidecar. Use GetCertificate in a tls.Config.
+type CertReloader struct {
+	certFile string
+	keyFile  string
+	cert     atomic.Pointer[tls.Certificate]
+
+	mu      sync.Mutex
+	modTime time.Time // newest modification time of the loaded files
+
+	// OnError is called when a reload fail, the previous certificate is kept.
+	OnError func(err error)
+}
+
+// NewCertReloader load the certificate and key files.
+//
+// Usage:
+//
+//	rl, err := glaze.NewCertReloader("tls.crt", "tls.key")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	go rl.Watch(ctx, time.Minute)
+//	e.RunTLSConfig(":443", &tls.Config{GetCertificate: rl.GetCertificate})
+func NewCertReloader(certFile, keyFile string) (*CertReloader, error) {
+	r := &CertReloader{certFile: certFile, keyFile: keyFile}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload read the files again, the current certificate is kept on error.
+func (r *CertReloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	modTime, err := r.filesModTime()
+	if err != nil {
+		return err
+	}
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return err
+	}
+	r.cert.Store(&cert)
+	r.modTime = modTime
+	return nil
+}
+
+// GetCertificate implement tls.Config.GetCertificate.
+func (r *CertReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return r.cert.Load(), nil
+}
+
+// Watch reload the certificate on SIGHUP and, when interval > 0, when
+// the files modification time change. It return when ctx is done.
+func (r *CertReloader) Watch(ctx context.Context, interval time.Duration) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			r.reload()
+		case <-tick:
+			if r.changed() {
+				r.reload()
+			}
+		}
+	}
+}
+
+func (r *CertReloader) reload() {
+	if err := r.Reload(); err != nil && r.OnError != nil {
+		r.OnError(err)
+	}
+}
+
+// changed report whether one of the files is newer than the loaded pair.
+func (r *CertReloader) changed() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	modTime, err := r.filesModTime()
+	return err == nil && modTime.After(r.modTime)
+}
+
+func (r *CertReloader) filesModTime() (time.Time, error) {
+	var newest time.Time
+	for _, name := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(name)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(newest) {
+			newest = info.ModTime()
+		}
+	}
+	return newest, nil
+}
+
+// WithCertReload make RunTLS and ListenAndGracefulTLS reload the
+// certificate files on SIGHUP, and every interval when they changed
+// (0 only reload on SIGHUP). Handshakes use the new certificate,
+// open connections are not closed.
+func WithCertReload(interval time.Duration) ConfigsFunc {
+	return func(e *Engine) {
+		e.server.certReload = true
+		e.server.certReloadInterval = interval
+	}
+}
+
+// prepareTLS apply the client certificate settings and the certificate
+// reloading to srv. It return the files to pass to ServeTLS and a
+// function stopping the watcher.
+func (e *Engine) prepareTLS(srv *http.Server, certFile, keyFile string) (string, string, func(), error) {
+	srv.TLSConfig = e.tlsConfig(srv.TLSConfig)
+	if !e.server.certReload || certFile == "" {
+		return certFile, keyFile, func() {}, nil
+	}
+
+	rl, err := NewCertReloader(certFile, keyFile)
+	if err != nil {
+		return "", "", nil, err
+	}
+	rl.OnError = func(err error) {
+		e.logf(LogLevelError, "tls: reload certificate: %s\n", err)
+	}
+
+	if srv.TLSConfig == nil {
+		srv.TLSConfig = &tls.Config{}
+	} else {
+		srv.TLSConfig = srv.TLSConfig.Clone()
+	}
+	srv.TLSConfig.GetCertificate = rl.GetCertificate
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go rl.Watch(ctx, e.server.certReloadInterval)
+	return "", "", cancel, nil
+}
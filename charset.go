@@ -0,0 +1,85 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Transcoder convert an UTF-8 body to another charset.
+type Transcoder func(utf8 []byte) ([]byte, error)
+
+// WithCharset offer charset, with its transcoder, to the clients
+// preferring it in their Accept-Charset header. It apply to the bodies of
+// c.Text, c.HTMLString and c.HTML when no Content-Type is set yet, the
+// other responses stay UTF-8. UTF-8 is always offered and win the ties.
+//
+// Usage:
+//
+//	e := glaze.New(glaze.WithCharset("iso-8859-1", glaze.Latin1))
+func WithCharset(charset string, t Transcoder) ConfigsFunc {
+	return func(e *Engine) {
+		if e.charsets == nil {
+			e.charsets = make(map[string]Transcoder)
+		}
+		e.charsets[strings.ToLower(charset)] = t
+	}
+}
+
+// Latin1 is the ISO-8859-1 Transcoder, runes over U+00FF become "?".
+func Latin1(s []byte) ([]byte, error) {
+	out := make([]byte, 0, len(s))
+	for len(s) > 0 {
+		r, size := utf8.DecodeRune(s)
+		s = s[size:]
+		if r > 0xFF {
+			r = '?'
+		}
+		out = append(out, byte(r))
+	}
+	return out, nil
+}
+
+// negotiateCharset return the registered charset preferred by the
+// Accept-Charset header, "" for UTF-8.
+func (c *Context) negotiateCharset() string {
+	header := c.Request.Header.Get("Accept-Charset")
+	if header == "" {
+		return ""
+	}
+	best, bestQ := "", 0.0
+	for _, el := range ParseHeader(header) {
+		name, q := strings.ToLower(el.Value), el.Q()
+		if name == "utf-8" || name == "*" {
+			if q > 0 && q >= bestQ {
+				best, bestQ = "", q
+			}
+			continue
+		}
+		if _, ok := c.engine.charsets[name]; ok && q > bestQ {
+			best, bestQ = name, q
+		}
+	}
+	return best
+}
+
+// writeText send a text body of mediaType in the negotiated charset.
+func (c *Context) writeText(code int, mediaType string, body []byte) {
+	h := c.Writer.Header()
+	h.Add("Vary", "Accept-Charset")
+	charset := "utf-8"
+	if len(h["Content-Type"]) == 0 {
+		if name := c.negotiateCharset(); name != "" {
+			if out, err := c.engine.charsets[name](body); err == nil {
+				body, charset = out, name
+			} else {
+				c.engine.logf(LogLevelError, "charset %s: %v\n", name, err)
+			}
+		}
+		h.Set("Content-Type", mediaType+"; charset="+charset)
+	}
+	c.Writer.WriteHeader(code)
+	c.Writer.Write(body)
+}
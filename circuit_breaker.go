@@ -0,0 +1,218 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of a circuit breaker.
+type CircuitState int
+
+const (
+	// CircuitClosed let every request pass, failures are counted.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen fail fast every request with 503.
+	CircuitOpen
+	// CircuitHalfOpen let a few probe requests pass to test recovery.
+	CircuitHalfOpen
+)
+
+// String return the state name.
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// CircuitBreakerConfig holds the configuration of the circuit breaker middleware.
+type CircuitBreakerConfig struct {
+	// FailureRatio open the circuit when failures / requests reach it, default 0.5.
+	FailureRatio float64
+
+	// MinRequests is the minimum requests in a window before the
+	// ratio is evaluated, default 20.
+	MinRequests int
+
+	// Window is the counting window, counters reset after it, default 10s.
+	Window time.Duration
+
+	// SlowThreshold mark requests slower than it as failure, 0 disable latency check.
+	SlowThreshold time.Duration
+
+	// OpenTimeout is how long the circuit stay open before half-open probing, default 30s.
+	OpenTimeout time.Duration
+
+	// HalfOpenRequests is the amount of successful probes needed to close
+	// the circuit again, default 1.
+	HalfOpenRequests int
+
+	// IsFailure decide if a finished request is a failure,
+	// default is status code >= 500.
+	IsFailure func(*Context) bool
+
+	// OnStateChange is called (with lock released) every time the state change.
+	OnStateChange func(from, to CircuitState)
+}
+
+// circuitBreaker keep the counters of one breaker instance.
+type circuitBreaker struct {
+	cfg CircuitBreakerConfig
+
+	mu          sync.Mutex
+	state       CircuitState
+	windowStart time.Time
+	openedAt    time.Time
+	requests    int
+	failures    int
+	probes      int // in-flight probes in half-open state
+	successes   int // successful probes in half-open state
+}
+
+// CircuitBreaker returns a middleware that stop calling the next handlers
+// when they become unhealthy (too many errors or slow responses),
+// and respond 503 directly until the OpenTimeout elapsed.
+//
+// Every call create an independent breaker, attach it on a group or
+// a route to protect only that part:
+//
+//	api := r.Group("/reports", glaze.CircuitBreaker(glaze.CircuitBreakerConfig{
+//	    SlowThreshold: 2 * time.Second,
+//	}))
+func CircuitBreaker(cfg CircuitBreakerConfig) HandlerFunc {
+	if cfg.FailureRatio <= 0 {
+		cfg.FailureRatio = 0.5
+	}
+	if cfg.MinRequests <= 0 {
+		cfg.MinRequests = 20
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = 10 * time.Second
+	}
+	if cfg.OpenTimeout <= 0 {
+		cfg.OpenTimeout = 30 * time.Second
+	}
+	if cfg.HalfOpenRequests <= 0 {
+		cfg.HalfOpenRequests = 1
+	}
+	if cfg.IsFailure == nil {
+		cfg.IsFailure = func(c *Context) bool {
+			return c.Writer.Status() >= http.StatusInternalServerError
+		}
+	}
+
+	cb := &circuitBreaker{cfg: cfg, windowStart: time.Now()}
+	return func(c *Context) {
+		probe, ok := cb.allow()
+		if !ok {
+			c.Writer.Header().Set("Content-Type", textPlainContentType)
+			c.Writer.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = c.Writer.Write([]byte(http.StatusText(http.StatusServiceUnavailable)))
+			c.Abort()
+			return
+		}
+
+		start := time.Now()
+		failed := true
+		defer func() {
+			// a panic is counted as failure, then propagated to Recovery
+			cb.done(probe, failed)
+		}()
+
+		c.Next()
+
+		failed = cfg.IsFailure(c) ||
+			(cfg.SlowThreshold > 0 && time.Since(start) > cfg.SlowThreshold)
+	}
+}
+
+// allow report whether the request can pass and if it is a half-open probe.
+func (cb *circuitBreaker) allow() (probe bool, ok bool) {
+	cb.mu.Lock()
+	from := cb.state
+	now := time.Now()
+
+	if cb.state == CircuitOpen && now.Sub(cb.openedAt) >= cb.cfg.OpenTimeout {
+		cb.setState(CircuitHalfOpen, now)
+	}
+
+	switch cb.state {
+	case CircuitOpen:
+		ok = false
+	case CircuitHalfOpen:
+		// only a limited amount of probes run at the same time
+		if cb.probes < cb.cfg.HalfOpenRequests {
+			cb.probes++
+			probe, ok = true, true
+		}
+	default:
+		if now.Sub(cb.windowStart) > cb.cfg.Window {
+			cb.windowStart = now
+			cb.requests, cb.failures = 0, 0
+		}
+		ok = true
+	}
+
+	to := cb.state
+	cb.mu.Unlock()
+	cb.notify(from, to)
+	return probe, ok
+}
+
+// done record the result of a request.
+func (cb *circuitBreaker) done(probe, failed bool) {
+	cb.mu.Lock()
+	from := cb.state
+	now := time.Now()
+
+	if probe {
+		cb.probes--
+		if cb.state == CircuitHalfOpen {
+			if failed {
+				cb.setState(CircuitOpen, now)
+			} else if cb.successes++; cb.successes >= cb.cfg.HalfOpenRequests {
+				cb.setState(CircuitClosed, now)
+			}
+		}
+	} else if cb.state == CircuitClosed {
+		cb.requests++
+		if failed {
+			cb.failures++
+		}
+		if cb.requests >= cb.cfg.MinRequests &&
+			float64(cb.failures)/float64(cb.requests) >= cb.cfg.FailureRatio {
+			cb.setState(CircuitOpen, now)
+		}
+	}
+
+	to := cb.state
+	cb.mu.Unlock()
+	cb.notify(from, to)
+}
+
+// setState change state and reset counters, lock must be held.
+func (cb *circuitBreaker) setState(state CircuitState, now time.Time) {
+	cb.state = state
+	cb.requests, cb.failures = 0, 0
+	cb.successes = 0
+	cb.windowStart = now
+	if state == CircuitOpen {
+		cb.openedAt = now
+	}
+}
+
+// notify call OnStateChange if the state changed.
+func (cb *circuitBreaker) notify(from, to CircuitState) {
+	if from != to && cb.cfg.OnStateChange != nil {
+		cb.cfg.OnStateChange(from, to)
+	}
+}
@@ -0,0 +1,316 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// CompressConfig holds the configuration of the Compress middleware.
+type CompressConfig struct {
+	// Level of gzip and deflate, default gzip.DefaultCompression.
+	Level int
+
+	// MinLength is the smallest body compressed, default 1024 bytes.
+	// Smaller bodies are sent as is, except when flushed.
+	MinLength int
+
+	// ExcludePaths are path prefixes never compressed.
+	ExcludePaths []string
+
+	// ExcludeTypes are media types (or "type/" prefixes) never compressed,
+	// added to the already compressed types (images, audio, video, archives,
+	// fonts). Event streams (text/event-stream) are never compressed.
+	ExcludeTypes []string
+}
+
+// noCompressionMeta is the route metadata key of NoCompression.
+const noCompressionMeta = "glaze.nocompression"
+
+// NoCompression returns a copy of the group whose routes are never
+// compressed by the Compress middleware, for already compressed downloads
+// or streams that must not be buffered.
+//
+// Usage:
+//
+//	r.NoCompression().Get("/backups/:name", downloadBackup)
+func (r *Route) NoCompression() *Route {
+	return r.Meta(noCompressionMeta, true)
+}
+
+var defaultExcludedTypes = []string{
+	"image/", "audio/", "video/",
+	"application/zip", "application/gzip", "application/x-gzip", "application/x-7z-compressed",
+	"application/x-rar-compressed", "application/zstd", "application/wasm",
+	"font/woff", "font/woff2",
+	MIME_EVENT_STREAM,
+}
+
+// Compress returns a middleware compressing the responses with gzip or
+// deflate, as accepted by the client. The body is buffered up to MinLength
+// to decide, a Flush (streaming, c.Stream) send the buffered data and flush
+// the compressor so streamed responses are not held back. Routes
+// registered with Route.NoCompression are sent as is.
+//
+// Usage:
+//
+//	r.Use(glaze.Compress())
+//	r.Use(glaze.Compress(glaze.CompressConfig{MinLength: 256, ExcludePaths: []string{"/downloads"}}))
+func Compress(cfg ...CompressConfig) HandlerFunc {
+	var conf CompressConfig
+	if len(cfg) > 0 {
+		conf = cfg[0]
+	}
+	if conf.Level == 0 {
+		conf.Level = gzip.DefaultCompression
+	}
+	if conf.MinLength <= 0 {
+		conf.MinLength = 1024
+	}
+	excluded := append(append([]string{}, defaultExcludedTypes...), conf.ExcludeTypes...)
+
+	if conf.Level < gzip.HuffmanOnly || conf.Level > gzip.BestCompression {
+		panic("glaze: invalid Compress level " + strconv.Itoa(conf.Level))
+	}
+
+	pools := map[string]*sync.Pool{
+		"gzip": {New: func() any {
+			w, _ := gzip.NewWriterLevel(io.Discard, conf.Level)
+			return w
+		}},
+		"deflate": {New: func() any {
+			w, _ := flate.NewWriter(io.Discard, conf.Level)
+			return w
+		}},
+	}
+
+	return func(c *Context) {
+		c.compressed = true
+		req := c.Request
+		if req.Method == http.MethodHead || req.Header.Get("Upgrade") != "" {
+			c.Next()
+			return
+		}
+		for _, prefix := range conf.ExcludePaths {
+			if strings.HasPrefix(req.URL.Path, prefix) {
+				c.Next()
+				return
+			}
+		}
+		if _, off := c.RouteMeta(noCompressionMeta); off {
+			c.Next()
+			return
+		}
+		encoding := acceptedEncoding(req.Header.Get("Accept-Encoding"))
+		if encoding == "" {
+			c.Writer.Header().Add("Vary", "Accept-Encoding")
+			c.Next()
+			return
+		}
+
+		orig := c.Writer
+		cw := &compressWriter{
+			ResponseWriter: orig,
+			ctx:            c,
+			encoding:       encoding,
+			pool:           pools[encoding],
+			minLength:      conf.MinLength,
+			excluded:       excluded,
+			status:         http.StatusOK,
+		}
+		c.Writer = cw
+		defer func() {
+			cw.close()
+			c.Writer = orig
+		}()
+		c.Next()
+	}
+}
+
+// acceptedEncoding return gzip or deflate, the preferred encoding of the
+// Accept-Encoding header, or "".
+func acceptedEncoding(header string) string {
+	best, bestQ := "", 0.0
+	for _, el := range ParseHeader(header) {
+		name, q := strings.ToLower(el.Value), el.Q()
+		if name == "*" {
+			name = "gzip"
+		}
+		if (name == "gzip" || name == "deflate") && (q > bestQ || q == bestQ && name == "gzip") {
+			best, bestQ = name, q
+		}
+	}
+	if bestQ <= 0 {
+		return ""
+	}
+	return best
+}
+
+// compressor is the common interface of gzip.Writer and flate.Writer.
+type compressor interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// compressWriter buffer the body until the compression is decided:
+// MinLength bytes written, a Flush, or the end of the handler.
+type compressWriter struct {
+	ResponseWriter
+	ctx       *Context // route of pre-routing use, known once the body is written
+	encoding  string
+	pool      *sync.Pool
+	minLength int
+	excluded  []string
+
+	status      int
+	wroteHeader bool // WriteHeader called by the handler
+	decided     bool
+	buf         []byte
+	size        int
+	zw          compressor // nil when the body is sent as is
+}
+
+func (w *compressWriter) WriteHeader(code int) {
+	if w.wroteHeader || code <= 0 {
+		return
+	}
+	if code < 200 && code != http.StatusSwitchingProtocols {
+		w.ResponseWriter.WriteHeader(code) // informational, 103 Early Hints
+		return
+	}
+	w.status, w.wroteHeader = code, true
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	w.wroteHeader = true
+	w.size += len(data)
+	if !w.decided {
+		w.buf = append(w.buf, data...)
+		if len(w.buf) < w.minLength {
+			return len(data), nil
+		}
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+		return len(data), nil
+	}
+	if w.zw != nil {
+		return w.zw.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// Flush send the buffered body, compressed when possible, through the
+// compressor to the client.
+func (w *compressWriter) Flush() {
+	w.wroteHeader = true
+	if !w.decided {
+		w.decide(true)
+	}
+	if w.zw != nil {
+		w.zw.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// decide send the header, compressing the body when allowed and compress
+// is true, then the buffered data.
+func (w *compressWriter) decide(compress bool) error {
+	w.decided = true
+	h := w.Header()
+	if w.compressible() {
+		h.Add("Vary", "Accept-Encoding")
+		if compress {
+			h.Set("Content-Encoding", w.encoding)
+			h.Del("Content-Length")
+			w.zw = w.pool.Get().(compressor)
+			w.zw.Reset(w.ResponseWriter)
+		}
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+
+	buf := w.buf
+	w.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	var err error
+	if w.zw != nil {
+		_, err = w.zw.Write(buf)
+	} else {
+		_, err = w.ResponseWriter.Write(buf)
+	}
+	return err
+}
+
+// compressible report whether the response can be compressed.
+func (w *compressWriter) compressible() bool {
+	if w.status < 200 || w.status == http.StatusNoContent || w.status == http.StatusNotModified ||
+		w.status == http.StatusPartialContent {
+		return false
+	}
+	if _, off := w.ctx.RouteMeta(noCompressionMeta); off {
+		return false
+	}
+	h := w.Header()
+	if h.Get("Content-Encoding") != "" {
+		return false
+	}
+	ctype := h.Get("Content-Type")
+	if ctype == "" && len(w.buf) > 0 {
+		ctype = http.DetectContentType(w.buf)
+		h.Set("Content-Type", ctype)
+	}
+	mediaType, _, _ := mime.ParseMediaType(ctype)
+	if mediaType == "image/svg+xml" {
+		return true
+	}
+	for _, t := range w.excluded {
+		if mediaType == t || strings.HasSuffix(t, "/") && strings.HasPrefix(mediaType, t) {
+			return false
+		}
+	}
+	return true
+}
+
+// close send what is left once the handler returned.
+func (w *compressWriter) close() {
+	if !w.decided && (w.wroteHeader || len(w.buf) > 0) {
+		w.decide(false)
+	}
+	if w.zw != nil {
+		w.zw.Close()
+		w.zw.Reset(io.Discard)
+		w.pool.Put(w.zw)
+		w.zw = nil
+	}
+}
+
+func (w *compressWriter) Status() int {
+	return w.status
+}
+
+// Size return the uncompressed body size.
+func (w *compressWriter) Size() int {
+	if !w.wroteHeader {
+		return noWritten
+	}
+	return w.size
+}
+
+func (w *compressWriter) Written() bool {
+	return w.wroteHeader
+}
+
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
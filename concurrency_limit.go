@@ -0,0 +1,100 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"net/http"
+	"time"
+)
+
+// ConcurrencyLimitConfig holds the configuration of the concurrency limit middleware.
+type ConcurrencyLimitConfig struct {
+	// MaxInFlight is the maximum number of requests handled at the same time.
+	MaxInFlight int
+
+	// QueueSize is the number of requests allowed to wait for a free slot,
+	// 0 means requests over MaxInFlight are rejected directly.
+	QueueSize int
+
+	// QueueTimeout is the maximum time a request wait in the queue,
+	// 0 means wait until a slot is free or the client go away.
+	QueueTimeout time.Duration
+
+	// RetryAfter is sent as Retry-After header (in seconds) when > 0.
+	RetryAfter time.Duration
+}
+
+// ConcurrencyLimit returns a middleware that bound the number of
+// requests in-flight, extra requests get 503 directly.
+//
+// Attach it on the engine for a global limit, or on a group or route
+// to protect only an expensive part:
+//
+//	r.Post("/export", glaze.ConcurrencyLimit(4), exportHandler)
+func ConcurrencyLimit(max int) HandlerFunc {
+	return ConcurrencyLimitWithConfig(ConcurrencyLimitConfig{MaxInFlight: max})
+}
+
+// ConcurrencyLimitWithConfig returns a concurrency limit middleware with custom config.
+func ConcurrencyLimitWithConfig(cfg ConcurrencyLimitConfig) HandlerFunc {
+	if cfg.MaxInFlight <= 0 {
+		panic("concurrency limit: MaxInFlight must be greater than 0")
+	}
+	retryAfter := ""
+	if cfg.RetryAfter > 0 {
+		retryAfter = retryAfterSeconds(cfg.RetryAfter)
+	}
+
+	slots := make(chan struct{}, cfg.MaxInFlight)
+	queue := make(chan struct{}, cfg.QueueSize)
+
+	reject := func(c *Context) {
+		h := c.Writer.Header()
+		if retryAfter != "" {
+			h.Set("Retry-After", retryAfter)
+		}
+		h.Set("Content-Type", textPlainContentType)
+		c.Writer.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = c.Writer.Write([]byte(http.StatusText(http.StatusServiceUnavailable)))
+		c.Abort()
+	}
+
+	return func(c *Context) {
+		select {
+		case slots <- struct{}{}:
+			// got a slot directly
+		default:
+			// no free slot, try to wait in the queue
+			select {
+			case queue <- struct{}{}:
+			default:
+				reject(c)
+				return
+			}
+
+			var timeout <-chan time.Time
+			if cfg.QueueTimeout > 0 {
+				timer := time.NewTimer(cfg.QueueTimeout)
+				defer timer.Stop()
+				timeout = timer.C
+			}
+
+			select {
+			case slots <- struct{}{}:
+				<-queue
+			case <-timeout:
+				<-queue
+				reject(c)
+				return
+			case <-c.Request.Context().Done():
+				<-queue
+				c.Abort()
+				return
+			}
+		}
+		defer func() { <-slots }()
+
+		c.Next()
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"net"
+	"net/http"
+	"sync"
+)
+
+// ConnStats is a snapshot of the connections of the servers started by the Run helpers.
+type ConnStats struct {
+	Open   int // accepted and not closed (new, active and idle)
+	Active int // reading or serving a request
+	Idle   int // keep-alive, waiting for the next request
+}
+
+// connTracker count the connections by state, from http.Server.ConnState.
+type connTracker struct {
+	mu     sync.Mutex
+	states map[net.Conn]http.ConnState
+	stats  ConnStats
+}
+
+// WithConnState register fn as http.Server.ConnState of the Run helpers,
+// it is called after the engine connection counters are updated.
+//
+// Usage:
+//
+//	e := glaze.New(glaze.WithConnState(func(conn net.Conn, state http.ConnState) {
+//	    connTransitions.WithLabelValues(state.String()).Inc()
+//	}))
+func WithConnState(fn func(net.Conn, http.ConnState)) ConfigsFunc {
+	return func(e *Engine) {
+		e.server.connState = fn
+	}
+}
+
+// ConnStats return the current connection counters, usable as gauges
+// by a metrics middleware or to monitor a graceful drain.
+func (e *Engine) ConnStats() ConnStats {
+	e.conns.mu.Lock()
+	defer e.conns.mu.Unlock()
+	return e.conns.stats
+}
+
+// connStateHook update the counters and call the user hook.
+func (e *Engine) connStateHook(conn net.Conn, state http.ConnState) {
+	e.conns.track(conn, state)
+	if e.server.connState != nil {
+		e.server.connState(conn, state)
+	}
+}
+
+func (t *connTracker) track(conn net.Conn, state http.ConnState) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.states == nil {
+		t.states = make(map[net.Conn]http.ConnState)
+	}
+
+	prev, known := t.states[conn]
+	if known {
+		t.add(prev, -1)
+	}
+	switch state {
+	case http.StateHijacked, http.StateClosed:
+		if known {
+			t.stats.Open--
+			delete(t.states, conn)
+		}
+	default:
+		if !known {
+			t.stats.Open++
+		}
+		t.states[conn] = state
+		t.add(state, 1)
+	}
+}
+
+func (t *connTracker) add(state http.ConnState, n int) {
+	switch state {
+	case http.StateActive:
+		t.stats.Active += n
+	case http.StateIdle:
+		t.stats.Idle += n
+	}
+}
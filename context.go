@@ -5,32 +5,106 @@ package glaze
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
 	"io/fs"
 	"mime/multipart"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 )
 
 // Context is like the request context in web framework.
 // It hold request, response, params, query, handlers, and custom values.
+//
+// Contexts are pooled and reused by the engine: a Context must not be kept
+// or used after the handler returned, copy the values needed instead.
 type Context struct {
-	Writer  http.ResponseWriter // write response back
-	Request *http.Request       // http request
-	Params  map[string]string   // path parameters like /user/:id
-	querys  url.Values          // query parameters
+	Writer    ResponseWriter // write response back
+	Request   *http.Request  // http request
+	Params    Params         // path parameters like /user/:id
+	querys    url.Values     // query parameters, parsed on first use
+	writermem responseWriter // Writer of the request, reused with the context
 
 	handlers []HandlerFunc // list of handler functions (middlewares)
 	index    int           // current handler index
 	engine   *Engine       // pointer to engine
+	route    *RouteInfo    // matched route
+
+	clientIP  string // client address resolved from trusted proxies
+	scheme    string // original scheme resolved from trusted proxies
+	host      string // original host resolved from trusted proxies
+	forwarded bool   // proxy headers already resolved
 
 	Keys map[any]any  // custom key-value storage
 	mu   sync.RWMutex // lock for safe access
 
-	stopped bool // stop flag to abort next handlers
+	stopped bool        // stop flag to abort next handlers
+	errs    []error     // errors attached with Error
+	ext     any         // application context, see ContextFactory
+	scoped  map[any]any // per request provider values, see ProvideScoped
+
+	viewData   M        // template data of the request, see SetViewData
+	apiVersion string   // version selected by Versioned
+	accepted   []string // override of the Accept header, see SetAccepted
+
+	originalBody []byte // body before TransformBody
+	bound        any    // last value decoded by Bind, see BoundValue
+	compressed   bool   // the response goes through Compress, see ResponseCache
+}
+
+// Param is a single path parameter.
+type Param struct {
+	Key   string
+	Value string
+}
+
+// Params is the list of path parameters, in path order.
+type Params []Param
+
+// Get return the value of the parameter name and whether it exists.
+func (ps Params) Get(name string) (string, bool) {
+	for _, p := range ps {
+		if p.Key == name {
+			return p.Value, true
+		}
+	}
+	return "", false
+}
+
+// ByName return the value of the parameter name, or empty string.
+func (ps Params) ByName(name string) string {
+	v, _ := ps.Get(name)
+	return v
+}
+
+// reset prepare a pooled context for a new request.
+func (c *Context) reset(w http.ResponseWriter, req *http.Request) {
+	c.writermem.reset(w)
+	c.Writer = &c.writermem
+	c.Request = req
+	c.Params = c.Params[:0]
+	c.querys = nil
+	c.handlers = nil
+	c.index = -1
+	c.route = nil
+	c.clientIP, c.scheme, c.host, c.forwarded = "", "", "", false
+	c.Keys = nil
+	c.stopped = false
+	clear(c.errs)
+	c.errs = c.errs[:0]
+	c.ext = nil
+	c.scoped = nil
+	c.viewData = nil
+	c.apiVersion = ""
+	c.accepted = c.accepted[:0]
+	c.originalBody = nil
+	c.bound = nil
+	c.compressed = false
 }
 
 // Next call the next handler in the list.
@@ -60,12 +134,38 @@ func (c *Context) Abort() {
 
 // Param return value from path parameter by key.
 func (c *Context) Param(key string) string {
-	return c.Params[key]
+	return c.Params.ByName(key)
+}
+
+// FullPath return the registered path of the matched route,
+// like "/user/:id", or empty string if no route matched.
+func (c *Context) FullPath() string {
+	if c.route == nil {
+		return ""
+	}
+	return c.route.Path
+}
+
+// RouteMeta return the metadata attached to the matched route with Route.Meta.
+func (c *Context) RouteMeta(key string) (value any, exists bool) {
+	if c.route == nil {
+		return nil, false
+	}
+	value, exists = c.route.Meta[key]
+	return
 }
 
 // Query return value from query parameter in URL.
 func (c *Context) Query(key string) string {
-	return c.querys.Get(key)
+	return c.queryValues().Get(key)
+}
+
+// queryValues parse the query of the request URL on first use.
+func (c *Context) queryValues() url.Values {
+	if c.querys == nil {
+		c.querys = c.Request.URL.Query()
+	}
+	return c.querys
 }
 
 // Set put a custom value inside context.
@@ -92,6 +192,65 @@ func (c *Context) String(code int, msg string) {
 	io.WriteString(c.Writer, msg)
 }
 
+// Text send formatted text with the text/plain content type.
+//
+//	c.Text(http.StatusOK, "hello %s", name)
+func (c *Context) Text(code int, format string, args ...any) {
+	if len(c.engine.charsets) > 0 {
+		c.writeText(code, MIME_PLAIN, fmt.Appendf(nil, format, args...))
+		return
+	}
+	writeContentType(c.Writer, []string{textPlainContentType})
+	c.Writer.WriteHeader(code)
+	fmt.Fprintf(c.Writer, format, args...)
+}
+
+// HTMLString send html as is with the text/html content type, without
+// template. The caller must escape the user input.
+func (c *Context) HTMLString(code int, html string) {
+	if len(c.engine.charsets) > 0 {
+		c.writeText(code, MIME_HTML, []byte(html))
+		return
+	}
+	writeContentType(c.Writer, htmlContentType)
+	c.Writer.WriteHeader(code)
+	io.WriteString(c.Writer, html)
+}
+
+// SetAccepted override the Accept header for NegotiateFormat and the
+// default responses (404, 405, 500...), in preference order. Middleware
+// use it for API groups always answering JSON.
+//
+//	c.SetAccepted(glaze.MIME_JSON)
+func (c *Context) SetAccepted(types ...string) {
+	c.accepted = append(c.accepted[:0], types...)
+}
+
+// NegotiateFormat return the offer preferred by the client, from the types
+// set with SetAccepted or the Accept header, or "" when none is acceptable.
+//
+//	switch c.NegotiateFormat(glaze.MIME_JSON, glaze.MIME_HTML) {
+//	case glaze.MIME_HTML:
+//	    c.HTML(http.StatusOK, "user.html", user)
+//	default:
+//	    c.JSON(http.StatusOK, user)
+//	}
+func (c *Context) NegotiateFormat(offers ...string) string {
+	if len(c.accepted) == 0 {
+		return negotiateFormat(c.GetHeader("Accept"), offers...)
+	}
+	for _, accepted := range c.accepted {
+		for _, el := range ParseHeader(accepted) {
+			for _, offer := range offers {
+				if matchMediaRange(mediaRange(el.Value), offer) >= 0 {
+					return offer
+				}
+			}
+		}
+	}
+	return ""
+}
+
 // writeContentType set Content-Type header if not exist.
 func writeContentType(w http.ResponseWriter, value []string) {
 	header := w.Header()
@@ -100,24 +259,15 @@ func writeContentType(w http.ResponseWriter, value []string) {
 	}
 }
 
-// JSON send JSON response with escape HTML off.
+// JSON send JSON response with escape HTML off,
+// unless enabled by the engine JSONConfig.
 func (c *Context) JSON(code int, data any) {
-	writeContentType(c.Writer, jsonContentType)
-	c.Writer.WriteHeader(code)
-
-	encoder := json.NewEncoder(c.Writer)
-	encoder.SetEscapeHTML(false) // do not escape HTML
-	encoder.Encode(data)
+	c.renderJSON(code, data, c.engine.json.EscapeHTML)
 }
 
 // PureJSON send JSON response with escape HTML on.
 func (c *Context) PureJSON(code int, data any) {
-	writeContentType(c.Writer, jsonContentType)
-	c.Writer.WriteHeader(code)
-
-	encoder := json.NewEncoder(c.Writer)
-	encoder.SetEscapeHTML(true) // escape HTML
-	encoder.Encode(data)
+	c.renderJSON(code, data, true)
 }
 
 // BindJSON read JSON request body and decode into struct.
@@ -127,18 +277,17 @@ func (c *Context) BindJSON(dst any) error {
 	}
 
 	defer c.Request.Body.Close()
-	if err := json.NewDecoder(c.Request.Body).Decode(dst); err != nil {
-		return err
-	}
-	return nil
+	counter := c.countBody()
+	err := json.NewDecoder(c.Request.Body).Decode(dst)
+	c.observeBody(BodyEvent{Kind: "json", Size: c.restoreBody(counter), Err: err})
+	return err
 }
 
 // FormFile return uploaded file header by field name.
+// The form is parsed with the route MultipartLimits.
 func (c *Context) FormFile(name string) (*multipart.FileHeader, error) {
-	if c.Request.MultipartForm == nil {
-		if err := c.Request.ParseMultipartForm(c.engine.MultipartMemory); err != nil {
-			return nil, err
-		}
+	if err := c.parseMultipart(); err != nil {
+		return nil, err
 	}
 	f, fh, err := c.Request.FormFile(name)
 	if err != nil {
@@ -155,7 +304,7 @@ func (c *Context) Form(name string) string {
 
 // MultipartForm return multipart form data from request.
 func (c *Context) MultipartForm() (*multipart.Form, error) {
-	err := c.Request.ParseMultipartForm(c.engine.MultipartMemory)
+	err := c.parseMultipart()
 	return c.Request.MultipartForm, err
 }
 
@@ -219,6 +368,55 @@ func (c *Context) GetCookie(key string) (string, error) {
 	return v, nil
 }
 
+// ClientIP return the client IP address. It is the direct peer address,
+// unless the request came through a trusted proxy (see Engine.SetTrustedProxies
+// and the Forwarded middleware).
+func (c *Context) ClientIP() string {
+	c.resolveForwarded()
+	if c.clientIP != "" {
+		return c.clientIP
+	}
+	return remoteHost(c.Request.RemoteAddr)
+}
+
+// RemoteIP return the IP of the direct peer, RemoteAddr without the port,
+// even when the request came through a proxy. Rate limiters and audit
+// logs keep it next to ClientIP.
+func (c *Context) RemoteIP() string {
+	return strings.Trim(remoteHost(c.Request.RemoteAddr), "[]")
+}
+
+// RemotePort return the port of the direct peer, or "" when RemoteAddr
+// has none.
+func (c *Context) RemotePort() string {
+	_, port, err := net.SplitHostPort(c.Request.RemoteAddr)
+	if err != nil {
+		return ""
+	}
+	return port
+}
+
+// Scheme return "https" or "http" as seen by the client.
+func (c *Context) Scheme() string {
+	c.resolveForwarded()
+	if c.scheme != "" {
+		return c.scheme
+	}
+	if c.Request.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
+// Host return the host requested by the client.
+func (c *Context) Host() string {
+	c.resolveForwarded()
+	if c.host != "" {
+		return c.host
+	}
+	return c.Request.Host
+}
+
 // GetHeader return header value by key from request
 func (c *Context) GetHeader(key string) string {
 	return c.Request.Header.Get(key)
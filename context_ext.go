@@ -0,0 +1,58 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"fmt"
+)
+
+// ContextFactory register fn building the application context of each
+// request, usually a struct embedding *glaze.Context with app helpers.
+// It is called lazily, once per request, by Ext and Extend.
+//
+// Usage:
+//
+//	type AppContext struct {
+//	    *glaze.Context
+//	    db *sql.DB
+//	}
+//
+//	func (c *AppContext) DB() *sql.DB { return c.db }
+//
+//	e.ContextFactory(func(c *glaze.Context) any {
+//	    return &AppContext{Context: c, db: db}
+//	})
+//	e.Get("/users", glaze.Extend(func(c *AppContext) {
+//	    rows, err := c.DB().QueryContext(c.Request.Context(), "...")
+//	    ...
+//	}))
+func (e *Engine) ContextFactory(fn func(*Context) any) *Engine {
+	e.contextFactory = fn
+	return e
+}
+
+// Ext return the application context of the request as T,
+// created by the engine ContextFactory. It panic when no factory is
+// registered or the factory does not return a T.
+func Ext[T any](c *Context) T {
+	if c.ext == nil {
+		if c.engine.contextFactory == nil {
+			panic("glaze: Ext called without Engine.ContextFactory")
+		}
+		c.ext = c.engine.contextFactory(c)
+	}
+	ext, ok := c.ext.(T)
+	if !ok {
+		var zero T
+		panic(fmt.Sprintf("glaze: context factory return %T, not %T", c.ext, zero))
+	}
+	return ext
+}
+
+// Extend adapt a handler taking the application context to a HandlerFunc.
+func Extend[T any](h func(T)) HandlerFunc {
+	return func(c *Context) {
+		h(Ext[T](c))
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+// DebugRoutesPath is the path of the endpoint registered by DebugRoutes.
+const DebugRoutesPath = "/_glaze/routes"
+
+// DebugRoute is a route of the DebugRoutes report.
+type DebugRoute struct {
+	Method   string         `json:"method"`
+	Path     string         `json:"path"`
+	Handlers []string       `json:"handlers"` // chain in order, middleware first
+	Meta     map[string]any `json:"meta,omitempty"`
+	Examples []Example      `json:"examples,omitempty"` // see Route.Example
+}
+
+// DebugReport is the JSON body of the DebugRoutes endpoint.
+type DebugReport struct {
+	Mode       string              `json:"mode"`
+	Routes     []DebugRoute        `json:"routes"`
+	Middleware map[string][]string `json:"middleware"` // phase middleware and hooks
+	Config     map[string]any      `json:"config"`
+}
+
+// DebugRoutes register GET /_glaze/routes returning the live route table,
+// the middleware chains and the engine configuration as JSON (DebugReport).
+// Without handlers the endpoint is only served in debug mode and answer
+// 404 otherwise; with handlers, usually an authorization middleware,
+// it is always served behind them.
+//
+// Usage:
+//
+//	e.DebugRoutes()             // debug mode only
+//	e.DebugRoutes(requireAdmin) // protected, also in release mode
+func (e *Engine) DebugRoutes(handlers ...HandlerFunc) *Engine {
+	protected := len(handlers) > 0
+	handlers = append(handlers, func(c *Context) {
+		if !protected && !IsDebugging() {
+			c.defaultResponse(http.StatusNotFound)
+			return
+		}
+		c.JSON(http.StatusOK, e.debugReport())
+	})
+	e.Get(DebugRoutesPath, handlers...)
+	return e
+}
+
+// debugReport collect the route table and the configuration.
+func (e *Engine) debugReport() DebugReport {
+	report := DebugReport{
+		Mode:   Mode(),
+		Routes: []DebugRoute{},
+		Middleware: map[string][]string{
+			"onRequest":    handlerNames(e.onRequest),
+			"preRouting":   phaseNames(e.phases[PhasePreRouting]),
+			"postRouting":  phaseNames(e.phases[PhasePostRouting]),
+			"postResponse": phaseNames(e.phases[PhasePostResponse]),
+			"onResponse":   handlerNames(e.onResponse),
+		},
+		Config: map[string]any{
+			"multipartMemory":   e.MultipartMemory,
+			"logLevel":          int(e.logLevel),
+			"readTimeout":       e.server.readTimeout.String(),
+			"readHeaderTimeout": e.server.readHeaderTimeout.String(),
+			"writeTimeout":      e.server.writeTimeout.String(),
+			"idleTimeout":       e.server.idleTimeout.String(),
+			"maxHeaderBytes":    e.server.maxHeaderBytes,
+			"trustedProxies":    fmt.Sprint(e.trustedProxies),
+			"errorMappings":     len(e.errorMappings),
+			"startHooks":        len(e.startHooks),
+		},
+	}
+
+	for method, root := range e.trees {
+		walkRoutes(root, func(n *node) {
+			report.Routes = append(report.Routes, debugRoute(method, n))
+		})
+	}
+	for _, m := range e.mounts {
+		report.Routes = append(report.Routes, debugRoute(m.node.route.Method, m.node))
+	}
+	sort.Slice(report.Routes, func(i, j int) bool {
+		a, b := report.Routes[i], report.Routes[j]
+		if a.Path == b.Path {
+			return a.Method < b.Method
+		}
+		return a.Path < b.Path
+	})
+	return report
+}
+
+func debugRoute(method string, n *node) DebugRoute {
+	r := DebugRoute{Method: method, Path: n.route.Path, Handlers: handlerNames(n.handlers),
+		Examples: n.route.Examples()}
+	if len(n.route.Meta) > 0 {
+		r.Meta = make(map[string]any, len(n.route.Meta))
+		for k, v := range n.route.Meta {
+			if k == exampleMeta {
+				continue
+			}
+			if _, err := json.Marshal(v); err != nil {
+				v = fmt.Sprintf("%T", v) // funcs, channels...
+			}
+			r.Meta[k] = v
+		}
+		if len(r.Meta) == 0 {
+			r.Meta = nil
+		}
+	}
+	return r
+}
+
+// Handlers return the function names of the middleware chain applied to
+// the routes registered from now through the group: the pre-routing and
+// post-routing phases, then the engine and group middleware, in order.
+// Tests use it to check that a protected group has its auth middleware.
+//
+// Usage:
+//
+//	assert.Contains(t, strings.Join(admin.Handlers(), " "), "auth.RequireAdmin")
+func (r *Route) Handlers() []string {
+	e := r.engine
+	names := phaseNames(e.phases[PhasePreRouting])
+	names = append(names, phaseNames(e.phases[PhasePostRouting])...)
+	return append(names, handlerNames(r.Handler)...)
+}
+
+// RouteHandlers return the function names of the full chain of a registered
+// route, phases included and the route handler last, or false when method
+// and path (as registered, "/users/:id") have no route. Mounts (Proxy,
+// Static) are found with their prefix and any method.
+func (e *Engine) RouteHandlers(method, path string) ([]string, bool) {
+	n := e.findRoute(method, path, nil)
+	if n == nil || n.handlers == nil || n.route.Path != path {
+		n = nil
+		for _, m := range e.mounts {
+			if m.prefix == path {
+				n = m.node
+			}
+		}
+	}
+	if n == nil {
+		return nil, false
+	}
+	names := phaseNames(e.phases[PhasePreRouting])
+	names = append(names, phaseNames(e.phases[PhasePostRouting])...)
+	return append(names, handlerNames(n.handlers)...), true
+}
+
+func handlerNames(chain HandlersChain) []string {
+	names := make([]string, len(chain))
+	for i, h := range chain {
+		names[i] = nameOfFunction(h)
+	}
+	return names
+}
+
+func phaseNames(list []phaseHandler) []string {
+	names := make([]string, len(list))
+	for i, p := range list {
+		names[i] = nameOfFunction(p.handler)
+	}
+	return names
+}
@@ -0,0 +1,256 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// envDevChild is set in the server process started by the RunDev supervisor.
+const envDevChild = "GLAZE_DEV_CHILD"
+
+const defaultDevInterval = 500 * time.Millisecond
+
+// DevWatch is a set of files reloaded by RunDev when they change.
+type DevWatch struct {
+	Name     string   // shown in the reload messages
+	FS       fs.FS    // files to watch, os.DirFS("locales")
+	Patterns []string // fs.Glob patterns of FS, default every file
+	Reload   func() error
+}
+
+// DevConfig holds the configuration of RunDev.
+type DevConfig struct {
+	// Watch are reloaded when their files change. Templates loaded with
+	// LoadHTMLGlob or LoadHTMLFS are always watched.
+	Watch []DevWatch
+
+	// Interval of the file polling, default 500ms.
+	Interval time.Duration
+
+	// Restart run RunDev as a supervisor: the program is built with
+	// Build and started as a child serving addr, it is rebuilt and
+	// restarted when a .go file (or go.mod) under Dir change.
+	Restart bool
+
+	// Build is the command building the program to Output, default
+	// "go build -o <Output> .".
+	Build []string
+
+	// Output is the path of the built program, default a file of the
+	// temporary directory.
+	Output string
+
+	// Dir is the source directory watched by Restart, default ".".
+	Dir string
+}
+
+// RunDev serve addr like ListenAndGraceful for development. In debug mode
+// it poll the watched files and reload them when they change: templates
+// loaded with LoadHTMLGlob or LoadHTMLFS, and cfg.Watch (i18n bundles...).
+// With cfg.Restart it also rebuild and restart the server on Go source
+// changes. Out of debug mode it is ListenAndGraceful.
+//
+// Usage:
+//
+//	e.LoadHTMLGlob("templates/*.html")
+//	e.RunDev(":8080", glaze.DevConfig{
+//	    Restart: true,
+//	    Watch: []glaze.DevWatch{{
+//	        Name:   "locales",
+//	        FS:     os.DirFS("locales"),
+//	        Reload: func() error { return bundle.LoadFS(os.DirFS("locales"), ".") },
+//	    }},
+//	})
+func (e *Engine) RunDev(addr string, cfg ...DevConfig) error {
+	var conf DevConfig
+	if len(cfg) > 0 {
+		conf = cfg[0]
+	}
+	if !IsDebugging() {
+		return e.ListenAndGraceful(addr)
+	}
+	if conf.Interval <= 0 {
+		conf.Interval = defaultDevInterval
+	}
+	if conf.Restart && os.Getenv(envDevChild) == "" {
+		return e.superviseDev(conf)
+	}
+
+	watches := conf.Watch
+	if e.htmlWatch != nil {
+		watches = append([]DevWatch{*e.htmlWatch}, watches...)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go e.watchDev(ctx, conf.Interval, watches)
+	return e.ListenAndGraceful(addr)
+}
+
+// watchDev reload the watches when their snapshot change.
+func (e *Engine) watchDev(ctx context.Context, interval time.Duration, watches []DevWatch) {
+	last := make([]string, len(watches))
+	for i, w := range watches {
+		last[i] = snapshotFS(w.FS, w.Patterns)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		for i, w := range watches {
+			snap := snapshotFS(w.FS, w.Patterns)
+			if snap == last[i] {
+				continue
+			}
+			last[i] = snap
+			if err := w.Reload(); err != nil {
+				e.logf(LogLevelError, "dev: reload %s: %s\n", w.Name, err)
+				continue
+			}
+			e.logf(LogLevelDebug, "dev: reloaded %s\n", w.Name)
+		}
+	}
+}
+
+// snapshotFS return a signature of the name, size and modification time of
+// the files matching patterns, or every file when there is no pattern.
+func snapshotFS(fsys fs.FS, patterns []string) string {
+	var lines []string
+	add := func(name string) {
+		if info, err := fs.Stat(fsys, name); err == nil && !info.IsDir() {
+			lines = append(lines, fmt.Sprintf("%s %d %d", name, info.Size(), info.ModTime().UnixNano()))
+		}
+	}
+	if len(patterns) == 0 {
+		fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+			if err == nil && !d.IsDir() {
+				add(name)
+			}
+			return nil
+		})
+	}
+	for _, p := range patterns {
+		matches, _ := fs.Glob(fsys, p)
+		for _, name := range matches {
+			add(name)
+		}
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
+// goSources snapshot the Go files and go.mod of dir, without vendor,
+// testdata and hidden directories.
+func goSources(dir string) string {
+	var lines []string
+	filepath.WalkDir(dir, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		base := d.Name()
+		if d.IsDir() {
+			if name != dir && (base == "vendor" || base == "testdata" || strings.HasPrefix(base, ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if (strings.HasSuffix(base, ".go") && !strings.HasSuffix(base, "_test.go")) || base == "go.mod" {
+			if info, err := d.Info(); err == nil {
+				lines = append(lines, fmt.Sprintf("%s %d %d", name, info.Size(), info.ModTime().UnixNano()))
+			}
+		}
+		return nil
+	})
+	return strings.Join(lines, "\n")
+}
+
+// superviseDev build and run the program, again on every source change.
+func (e *Engine) superviseDev(conf DevConfig) error {
+	if conf.Dir == "" {
+		conf.Dir = "."
+	}
+	if conf.Output == "" {
+		conf.Output = filepath.Join(os.TempDir(), fmt.Sprintf("glaze-dev-%d", os.Getpid()))
+		defer os.Remove(conf.Output)
+	}
+	if len(conf.Build) == 0 {
+		conf.Build = []string{"go", "build", "-o", conf.Output, "."}
+	}
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(quit)
+
+	var child *exec.Cmd
+	exited := make(chan struct{})
+	stopChild := func() {
+		if child == nil {
+			return
+		}
+		child.Process.Signal(syscall.SIGTERM)
+		select {
+		case <-exited:
+		case <-time.After(10 * time.Second):
+			child.Process.Kill()
+			<-exited
+		}
+		child = nil
+	}
+	startChild := func() {
+		build := exec.Command(conf.Build[0], conf.Build[1:]...)
+		build.Dir = conf.Dir
+		build.Stdout, build.Stderr = os.Stderr, os.Stderr
+		if err := build.Run(); err != nil {
+			e.logf(LogLevelError, "dev: build failed: %s, waiting for changes\n", err)
+			return
+		}
+		cmd := exec.Command(conf.Output, os.Args[1:]...)
+		cmd.Env = append(os.Environ(), envDevChild+"=1")
+		cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+		if err := cmd.Start(); err != nil {
+			e.logf(LogLevelError, "dev: %s\n", err)
+			return
+		}
+		child = cmd
+		exited = make(chan struct{})
+		go func(done chan struct{}) {
+			cmd.Wait()
+			close(done)
+		}(exited)
+	}
+
+	snap := goSources(conf.Dir)
+	startChild()
+	ticker := time.NewTicker(conf.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-quit:
+			stopChild()
+			return nil
+		case <-ticker.C:
+		}
+		if next := goSources(conf.Dir); next != snap {
+			snap = next
+			e.logf(LogLevelDebug, "dev: sources changed, restarting\n")
+			stopChild()
+			startChild()
+		}
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+// Package docs serve an interactive API documentation UI (Swagger UI or
+// Redoc) backed by an OpenAPI spec.
+//
+// Usage:
+//
+//	//go:embed openapi.yaml
+//	var spec []byte
+//
+//	admin := r.Group("/docs", basicAuth)
+//	docs.Mount(admin, "", docs.Config{Title: "Billing API", Spec: spec})
+//
+// The UI assets are loaded by the browser from AssetsURL, point it to
+// a self hosted copy of swagger-ui-dist or redoc for offline networks.
+package docs
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/nrhox/glaze"
+)
+
+// UI is the documentation renderer.
+type UI int
+
+const (
+	SwaggerUI UI = iota // interactive, with "try it out"
+	Redoc               // read-only three panels layout
+)
+
+const (
+	defaultSwaggerAssets = "https://cdn.jsdelivr.net/npm/swagger-ui-dist@5.17.14"
+	defaultRedocAssets   = "https://cdn.jsdelivr.net/npm/redoc@2.1.5/bundles"
+)
+
+// Config holds the configuration of the documentation endpoints.
+type Config struct {
+	// Title of the page, default "API documentation".
+	Title string
+
+	// UI is the renderer, default SwaggerUI.
+	UI UI
+
+	// Spec is the OpenAPI document (JSON or YAML), served at prefix/openapi.json
+	// or prefix/openapi.yaml. Ignored when SpecURL is set.
+	Spec []byte
+
+	// SpecURL is the URL of an existing spec endpoint.
+	SpecURL string
+
+	// AssetsURL is the base URL of the UI bundle, default a pinned CDN version.
+	AssetsURL string
+}
+
+// Mount register GET prefix/ (the UI) and the spec endpoint on r.
+// Mount it on a group with authentication middleware to protect the docs.
+func Mount(r glaze.Routes, prefix string, cfg Config) {
+	if cfg.Title == "" {
+		cfg.Title = "API documentation"
+	}
+	if cfg.AssetsURL == "" {
+		cfg.AssetsURL = defaultSwaggerAssets
+		if cfg.UI == Redoc {
+			cfg.AssetsURL = defaultRedocAssets
+		}
+	}
+	cfg.AssetsURL = strings.TrimSuffix(cfg.AssetsURL, "/")
+	prefix = strings.TrimSuffix(prefix, "/")
+
+	specURL := cfg.SpecURL
+	if specURL == "" {
+		if len(cfg.Spec) == 0 {
+			panic("docs: Spec or SpecURL is required")
+		}
+		name, contentType := "openapi.yaml", "application/yaml"
+		if isJSON(cfg.Spec) {
+			name, contentType = "openapi.json", "application/json"
+		}
+		spec := cfg.Spec
+		r.Get(prefix+"/"+name, func(c *glaze.Context) {
+			c.Writer.Header().Set("Content-Type", contentType)
+			c.Writer.WriteHeader(http.StatusOK)
+			c.Writer.Write(spec)
+		})
+		// relative to the page, work behind any group prefix
+		specURL = name
+	}
+
+	tmpl := swaggerTemplate
+	if cfg.UI == Redoc {
+		tmpl = redocTemplate
+	}
+	var page bytes.Buffer
+	if err := tmpl.Execute(&page, map[string]string{
+		"Title":   cfg.Title,
+		"Assets":  cfg.AssetsURL,
+		"SpecURL": specURL,
+	}); err != nil {
+		panic("docs: " + err.Error())
+	}
+	index := page.Bytes()
+
+	r.Get(prefix+"/", func(c *glaze.Context) {
+		// the page use relative URLs, "/docs" must become "/docs/"
+		if !strings.HasSuffix(c.Request.URL.Path, "/") {
+			http.Redirect(c.Writer, c.Request, c.Request.URL.Path+"/", http.StatusMovedPermanently)
+			return
+		}
+		c.Writer.Header().Set("Content-Type", "text/html; charset=utf-8")
+		c.Writer.WriteHeader(http.StatusOK)
+		c.Writer.Write(index)
+	})
+}
+
+// isJSON report whether the spec look like a JSON document.
+func isJSON(spec []byte) bool {
+	spec = bytes.TrimSpace(spec)
+	return len(spec) > 0 && spec[0] == '{'
+}
+
+var swaggerTemplate = template.Must(template.New("swagger").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<link rel="stylesheet" href="{{.Assets}}/swagger-ui.css">
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="{{.Assets}}/swagger-ui-bundle.js"></script>
+<script>
+window.ui = SwaggerUIBundle({url: {{.SpecURL}}, dom_id: "#swagger-ui", deepLinking: true});
+</script>
+</body>
+</html>
+`))
+
+var redocTemplate = template.Must(template.New("redoc").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+</head>
+<body>
+<redoc spec-url="{{.SpecURL}}"></redoc>
+<script src="{{.Assets}}/redoc.standalone.js"></script>
+</body>
+</html>
+`))
@@ -0,0 +1,62 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package docs
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nrhox/glaze"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMount(t *testing.T) {
+	r := glaze.New()
+	admin := r.Group("/docs", func(c *glaze.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.String(http.StatusUnauthorized, "unauthorized")
+			c.Abort()
+		}
+	})
+	Mount(admin, "", Config{Title: "Billing API", Spec: []byte(`{"openapi":"3.1.0"}`)})
+
+	do := func(path string, auth bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		if auth {
+			req.Header.Set("Authorization", "Basic x")
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, do("/docs/", false).Code)
+
+	w := do("/docs", true)
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "/docs/", w.Header().Get("Location"))
+
+	w = do("/docs/", true)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "<title>Billing API</title>")
+	assert.Contains(t, w.Body.String(), `url: "openapi.json"`)
+	assert.Contains(t, w.Body.String(), "swagger-ui-bundle.js")
+
+	w = do("/docs/openapi.json", true)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, `{"openapi":"3.1.0"}`, w.Body.String())
+}
+
+func TestMountRedoc(t *testing.T) {
+	r := glaze.New()
+	Mount(r, "/reference", Config{UI: Redoc, SpecURL: "/api/openapi.yaml", AssetsURL: "/assets/redoc/"})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/reference/", nil))
+	assert.Contains(t, w.Body.String(), `<redoc spec-url="/api/openapi.yaml">`)
+	assert.Contains(t, w.Body.String(), `src="/assets/redoc/redoc.standalone.js"`)
+
+	assert.Panics(t, func() { Mount(glaze.New(), "/docs", Config{}) })
+}
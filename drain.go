@@ -0,0 +1,52 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+// Done return a channel closed when the server start shutting down
+// (Engine.Shutdown, Engine.Close or a signal in ListenAndGraceful).
+// Long-lived handlers (SSE streams, websockets, long polls) select on it
+// to send a last message and return before the shutdown deadline.
+// Connection close by the client is still c.Request.Context().Done().
+//
+// Usage:
+//
+//	for {
+//	    select {
+//	    case ev := <-events:
+//	        send(c, ev)
+//	    case <-c.Done():
+//	        send(c, "reconnect")
+//	        return
+//	    case <-c.Request.Context().Done():
+//	        return
+//	    }
+//	}
+func (c *Context) Done() <-chan struct{} {
+	c.engine.drainMu.Lock()
+	defer c.engine.drainMu.Unlock()
+	return c.engine.drain
+}
+
+// beginDrain notify the long-lived handlers, it is safe to call many times.
+func (e *Engine) beginDrain() {
+	e.drainMu.Lock()
+	defer e.drainMu.Unlock()
+	select {
+	case <-e.drain:
+	default:
+		close(e.drain)
+	}
+}
+
+// resetDrain give a new Done channel to an engine started again after a
+// shutdown.
+func (e *Engine) resetDrain() {
+	e.drainMu.Lock()
+	defer e.drainMu.Unlock()
+	select {
+	case <-e.drain:
+		e.drain = make(chan struct{})
+	default:
+	}
+}
@@ -0,0 +1,236 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+	"strconv"
+)
+
+// errorMapping map matching errors to a response.
+type errorMapping struct {
+	match  func(error) bool
+	status int
+	body   any // JSON body, or func(error) any
+}
+
+// MapError register the response of errors matching target with errors.Is.
+// When body is omitted the response is {"error": err.Error()}, body can also
+// be a func(error) any building the JSON body from the error.
+//
+// Usage:
+//
+//	e.MapError(sql.ErrNoRows, http.StatusNotFound, glaze.M{"error": "not found"})
+//	e.MapError(ErrQuotaExceeded, http.StatusTooManyRequests)
+func (e *Engine) MapError(target error, status int, body ...any) *Engine {
+	e.addErrorMapping(func(err error) bool { return errors.Is(err, target) }, status, body)
+	return e
+}
+
+// MapErrorAs register the response of every error of type T, matched with errors.As.
+//
+//	glaze.MapErrorAs[*ValidationError](e, http.StatusUnprocessableEntity)
+func MapErrorAs[T error](e *Engine, status int, body ...any) {
+	e.addErrorMapping(func(err error) bool {
+		var target T
+		return errors.As(err, &target)
+	}, status, body)
+}
+
+func (e *Engine) addErrorMapping(match func(error) bool, status int, body []any) {
+	m := errorMapping{match: match, status: status}
+	if len(body) > 0 {
+		m.body = body[0]
+	}
+	e.errorMappings = append(e.errorMappings, m)
+}
+
+// resolveError return the status and JSON body of err,
+// unmapped errors become 500 without leaking the message.
+func (e *Engine) resolveError(err error) (int, any) {
+	if status, body, ok := e.lookupError(err); ok {
+		return status, body
+	}
+	return http.StatusInternalServerError, M{"error": http.StatusText(http.StatusInternalServerError)}
+}
+
+// lookupError return the response of the first mapping matching err.
+func (e *Engine) lookupError(err error) (int, any, bool) {
+	for _, m := range e.errorMappings {
+		if !m.match(err) {
+			continue
+		}
+		switch body := m.body.(type) {
+		case nil:
+			return m.status, M{"error": err.Error()}, true
+		case func(error) any:
+			return m.status, body(err), true
+		default:
+			return m.status, body, true
+		}
+	}
+	var herr *HTTPError
+	if errors.As(err, &herr) {
+		return herr.Status, herr, true
+	}
+	return 0, nil, false
+}
+
+// HTTPError is an error carrying its HTTP response, rendered by the
+// ErrorHandler middleware and typed handlers as
+// {"error": message, "code": code, "details": details}.
+// Engine mappings (MapError) still take precedence. With the I18n
+// middleware the message is translated with the "errors.<code>" key.
+type HTTPError struct {
+	Status  int    `json:"-"`
+	Code    string `json:"code,omitempty"` // machine readable, "user_not_found"
+	Message string `json:"error"`
+	Details any    `json:"details,omitempty"`
+	Err     error  `json:"-"` // cause, not sent to the client
+}
+
+// NewError return an HTTPError, message default to the status text.
+// Errors with the same status and code match with errors.Is, so they
+// can be declared once and enriched with WithDetails or Wrap.
+//
+// Usage:
+//
+//	var ErrUserNotFound = glaze.NewError(http.StatusNotFound, "user_not_found", "user not found")
+//
+//	return nil, ErrUserNotFound.WithDetails(glaze.M{"id": id})
+func NewError(status int, code, message string) *HTTPError {
+	if message == "" {
+		message = http.StatusText(status)
+	}
+	return &HTTPError{Status: status, Code: code, Message: message}
+}
+
+// WithDetails return a copy of e with the details sent to the client.
+func (e *HTTPError) WithDetails(details any) *HTTPError {
+	cp := *e
+	cp.Details = details
+	return &cp
+}
+
+// Wrap return a copy of e with err as cause, for logs and errors.Is.
+func (e *HTTPError) Wrap(err error) *HTTPError {
+	cp := *e
+	cp.Err = err
+	return &cp
+}
+
+// Error return "status code: message: cause".
+func (e *HTTPError) Error() string {
+	msg := strconv.Itoa(e.Status)
+	if e.Code != "" {
+		msg += " " + e.Code
+	}
+	msg += ": " + e.Message
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap return the cause.
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// Is match the HTTPError with the same status and code.
+func (e *HTTPError) Is(target error) bool {
+	t, ok := target.(*HTTPError)
+	return ok && t.Status == e.Status && t.Code == e.Code
+}
+
+// Error attach an error to the request, it is rendered by the
+// ErrorHandler middleware once the handler chain is done.
+// Nil errors are ignored.
+//
+// Usage:
+//
+//	if err := svc.Create(c.Request.Context(), in); err != nil {
+//	    c.Error(err)
+//	    return
+//	}
+func (c *Context) Error(err error) {
+	if err != nil {
+		c.errs = append(c.errs, err)
+	}
+}
+
+// Errors return the errors attached with Error, in order.
+func (c *Context) Errors() []error {
+	return c.errs
+}
+
+// ErrorHandler returns a middleware rendering the last error attached with
+// c.Error as JSON, using the engine mappings (see Engine.MapError) and
+// HTTPError, or as a problem document with WithProblemDetails.
+// Nothing is written if the handler already sent a response.
+// Server errors are logged with the request id (see RequestID), route and
+// client address, and sent to the engine Reporter.
+func ErrorHandler() HandlerFunc {
+	return func(c *Context) {
+		c.Next()
+
+		if len(c.errs) == 0 || c.Writer.Written() {
+			return
+		}
+		c.renderError(c.errs[len(c.errs)-1])
+	}
+}
+
+// ErrorPage is the data of the templates registered with ErrorTemplate.
+type ErrorPage struct {
+	Status  int
+	Message string // http.StatusText of Status
+	Path    string
+}
+
+// ErrorTemplate set the HTML template of the default status response
+// (404, 405, 500), rendered for clients accepting text/html.
+//
+// Usage:
+//
+//	e.ErrorTemplate(http.StatusNotFound, template.Must(template.ParseFiles("404.html")))
+func (e *Engine) ErrorTemplate(status int, tmpl *template.Template) *Engine {
+	if e.errorTemplates == nil {
+		e.errorTemplates = make(map[int]*template.Template)
+	}
+	e.errorTemplates[status] = tmpl
+	return e
+}
+
+// defaultResponse write the built-in response of status, negotiated with
+// the Accept header: JSON for API clients, HTML for browsers, text otherwise.
+func (c *Context) defaultResponse(status int) {
+	msg := http.StatusText(status)
+	h := c.Writer.Header()
+	h.Set("X-Content-Type-Options", "nosniff")
+
+	switch c.NegotiateFormat(MIME_PLAIN, MIME_JSON, MIME_HTML) {
+	case MIME_JSON:
+		h.Del("Content-Type")
+		c.JSON(status, M{"error": msg})
+	case MIME_HTML:
+		h.Set("Content-Type", "text/html; charset=utf-8")
+		c.Writer.WriteHeader(status)
+		page := ErrorPage{Status: status, Message: msg, Path: c.Request.URL.Path}
+		tmpl := c.engine.errorTemplates[status]
+		if tmpl == nil {
+			tmpl = defaultErrorTemplate
+		}
+		tmpl.Execute(c.Writer, page)
+	default:
+		h.Set("Content-Type", textPlainContentType)
+		c.String(status, strconv.Itoa(status)+" "+msg)
+	}
+}
+
+var defaultErrorTemplate = template.Must(template.New("error").Parse(
+	`<!DOCTYPE html><html><head><title>{{.Status}} {{.Message}}</title></head>` +
+		`<body><h1>{{.Status}} {{.Message}}</h1></body></html>` + "\n"))
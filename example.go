@@ -0,0 +1,53 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+// exampleMeta is the route metadata key of the examples.
+const exampleMeta = "glaze.examples"
+
+// Example is a documented request and response of a route, shown by the
+// DebugRoutes endpoint and added to an OpenAPI document by
+// openapi.AddExamples.
+type Example struct {
+	Name        string `json:"name"`                  // unique per route, "create-admin"
+	Summary     string `json:"summary,omitempty"`     // one line description
+	ContentType string `json:"contentType,omitempty"` // of both bodies, default application/json
+	Request     any    `json:"request,omitempty"`     // request body, nil for none
+	Status      int    `json:"status,omitempty"`      // response status, default 200
+	Response    any    `json:"response,omitempty"`    // response body, nil for none
+}
+
+// Example returns a copy of the group whose routes carry the examples,
+// like Meta. Examples of the parent group are kept, so chain it right
+// before registering the route.
+//
+// Usage:
+//
+//	r.Example(glaze.Example{
+//	    Name:     "create",
+//	    Request:  glaze.M{"name": "jalu"},
+//	    Status:   http.StatusCreated,
+//	    Response: glaze.M{"id": 7, "name": "jalu"},
+//	}).Post("/users", createUser)
+func (r *Route) Example(examples ...Example) *Route {
+	parent, _ := r.meta[exampleMeta].([]Example)
+	list := make([]Example, 0, len(parent)+len(examples))
+	list = append(list, parent...)
+	for _, ex := range examples {
+		if ex.ContentType == "" {
+			ex.ContentType = MIME_JSON
+		}
+		if ex.Status == 0 {
+			ex.Status = 200
+		}
+		list = append(list, ex)
+	}
+	return r.Meta(exampleMeta, list)
+}
+
+// Examples return the examples attached to the route with Route.Example.
+func (ri RouteInfo) Examples() []Example {
+	examples, _ := ri.Meta[exampleMeta].([]Example)
+	return examples
+}
@@ -0,0 +1,96 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Cache-Control of the files served by Favicon and RobotsTxt.
+const (
+	faviconCache = "public, max-age=86400"
+	robotsCache  = "public, max-age=3600"
+)
+
+// Favicon serve /favicon.ico from memory with a strong ETag and a one day
+// cache. icon is the image data ([]byte) or the path of a local file
+// (string), read once at registration.
+//
+// Usage:
+//
+//	//go:embed favicon.ico
+//	var favicon []byte
+//
+//	r.Favicon(favicon)
+//	r.Favicon("./public/favicon.ico")
+func (r *Route) Favicon(icon any) Routes {
+	var (
+		data  []byte
+		ctype string
+	)
+	switch v := icon.(type) {
+	case []byte:
+		data = v
+	case string:
+		b, err := os.ReadFile(v)
+		if err != nil {
+			panic("glaze: favicon: " + err.Error())
+		}
+		data, ctype = b, mime.TypeByExtension(filepath.Ext(v))
+	default:
+		panic(fmt.Sprintf("glaze: favicon need []byte data or a file path, got %T", icon))
+	}
+	if ctype == "" {
+		ctype = http.DetectContentType(data)
+	}
+	f := newMemoryFile(data, ctype, faviconCache)
+	return r.staticFileHandler("/favicon.ico", f.serve)
+}
+
+// RobotsTxt serve /robots.txt from memory with a strong ETag and a one hour cache.
+//
+// Usage:
+//
+//	r.RobotsTxt("User-agent: *\nDisallow: /admin/\n")
+func (r *Route) RobotsTxt(content string) Routes {
+	f := newMemoryFile([]byte(content), textPlainContentType, robotsCache)
+	return r.staticFileHandler("/robots.txt", f.serve)
+}
+
+// memoryFile is a file served from memory.
+type memoryFile struct {
+	data         []byte
+	contentType  string
+	cacheControl string
+	etag         string
+	modTime      time.Time // registration time, for If-Modified-Since
+}
+
+func newMemoryFile(data []byte, contentType, cacheControl string) *memoryFile {
+	sum := sha256.Sum256(data)
+	return &memoryFile{
+		data:         data,
+		contentType:  contentType,
+		cacheControl: cacheControl,
+		etag:         `"` + hex.EncodeToString(sum[:16]) + `"`,
+		modTime:      time.Now(),
+	}
+}
+
+// serve write the file, conditional and range requests are handled by http.ServeContent.
+func (f *memoryFile) serve(c *Context) {
+	h := c.Writer.Header()
+	h.Set("Content-Type", f.contentType)
+	h.Set("Cache-Control", f.cacheControl)
+	h.Set("ETag", f.etag)
+	http.ServeContent(c.Writer, c.Request, "", f.modTime, bytes.NewReader(f.data))
+}
@@ -0,0 +1,85 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import "net/http"
+
+// FlagProvider decide the feature flags of a request, it is the
+// integration point of flag services (LaunchDarkly, Unleash) or of a
+// homemade table. Enabled is called once per flag and request.
+type FlagProvider interface {
+	// Enabled report whether the flag name is on for the request of c,
+	// by user, tenant, header or percentage.
+	Enabled(c *Context, name string) bool
+}
+
+// FlagFunc adapt a function to FlagProvider.
+//
+// Usage:
+//
+//	e := glaze.New(glaze.WithFlags(glaze.FlagFunc(func(c *glaze.Context, name string) bool {
+//	    return name == "new-checkout" && c.GetHeader("X-Beta") == "1"
+//	})))
+type FlagFunc func(c *Context, name string) bool
+
+// Enabled call f.
+func (f FlagFunc) Enabled(c *Context, name string) bool {
+	return f(c, name)
+}
+
+// WithFlags set the engine flag provider, read with Context.FlagEnabled.
+//
+//	e := glaze.New(glaze.WithFlags(launchDarklyFlags{client}))
+func WithFlags(p FlagProvider) ConfigsFunc {
+	return func(e *Engine) {
+		e.flags = p
+	}
+}
+
+// flagsKey is the context key of the flags decided for the request.
+type flagsKey struct{}
+
+// FlagEnabled report whether the feature flag name is on for the request.
+// The answer of the provider is kept for the rest of the request, so the
+// middleware and the handler see the same value. Without WithFlags every
+// flag is off.
+//
+// Usage:
+//
+//	if c.FlagEnabled("new-checkout") {
+//	    newCheckout(c)
+//	    return
+//	}
+func (c *Context) FlagEnabled(name string) bool {
+	if c.engine.flags == nil {
+		return false
+	}
+	v, _ := c.Get(flagsKey{})
+	flags, _ := v.(map[string]bool)
+	if enabled, ok := flags[name]; ok {
+		return enabled
+	}
+	if flags == nil {
+		flags = make(map[string]bool)
+		c.Set(flagsKey{}, flags)
+	}
+	enabled := c.engine.flags.Enabled(c, name)
+	flags[name] = enabled
+	return enabled
+}
+
+// RequireFlag returns a middleware answering 404 while the flag name is
+// off, so unreleased routes are not visible.
+//
+// Usage:
+//
+//	r.Group("/v2/checkout", glaze.RequireFlag("new-checkout")).Post("/", newCheckout)
+func RequireFlag(name string) HandlerFunc {
+	return func(c *Context) {
+		if !c.FlagEnabled(name) {
+			c.defaultResponse(http.StatusNotFound)
+			c.Abort()
+		}
+	}
+}
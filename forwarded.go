@@ -0,0 +1,252 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// ForwardedConfig holds the configuration of the forwarded header middleware.
+type ForwardedConfig struct {
+	// TrustedProxies are the IPs or CIDRs of the proxies in front of the
+	// service. Headers are ignored when the direct peer is not one of them.
+	// Nil use the engine trusted proxies.
+	TrustedProxies []string
+
+	// IgnoreForwarded only read the legacy X-Forwarded-* headers,
+	// by default the RFC 7239 Forwarded header win when present.
+	IgnoreForwarded bool
+}
+
+// forwardedElement is one hop of a proxy chain.
+type forwardedElement struct {
+	ip    string
+	proto string
+	host  string
+}
+
+// Forwarded returns a middleware that read the Forwarded (RFC 7239) or
+// X-Forwarded-For/Proto/Host headers set by trusted proxies, and make
+// c.ClientIP, c.Scheme and c.Host report the original client values.
+// When cfg.TrustedProxies is nil the engine proxies are used,
+// see Engine.SetTrustedProxies.
+//
+// Usage:
+//
+//	r.Use(glaze.Forwarded(glaze.ForwardedConfig{
+//	    TrustedProxies: []string{"10.0.0.0/8"},
+//	}))
+func Forwarded(cfg ForwardedConfig) HandlerFunc {
+	trusted, err := parsePrefixes(cfg.TrustedProxies)
+	if err != nil {
+		panic("forwarded: " + err.Error())
+	}
+
+	return func(c *Context) {
+		proxies := trusted
+		if cfg.TrustedProxies == nil {
+			proxies = c.engine.trustedProxies
+		}
+		c.applyForwarded(proxies, cfg.IgnoreForwarded)
+	}
+}
+
+// SetTrustedProxies set the IPs or CIDRs of the proxies in front of the
+// service. Requests coming from them have c.ClientIP, c.Scheme and c.Host
+// resolved from the Forwarded or X-Forwarded-* headers, without the
+// Forwarded middleware. Nil (the default) trust nothing.
+//
+// Usage:
+//
+//	if err := e.SetTrustedProxies([]string{"10.0.0.0/8", "192.168.1.2"}); err != nil {
+//	    log.Fatal(err)
+//	}
+func (e *Engine) SetTrustedProxies(proxies []string) error {
+	trusted, err := parsePrefixes(proxies)
+	if err != nil {
+		return err
+	}
+	e.trustedProxies = trusted
+	return nil
+}
+
+// resolveForwarded apply the engine trusted proxies once per request.
+func (c *Context) resolveForwarded() {
+	if !c.forwarded {
+		c.applyForwarded(c.engine.trustedProxies, false)
+	}
+}
+
+// applyForwarded set the client values from the proxy headers when
+// the direct peer is one of the trusted proxies.
+func (c *Context) applyForwarded(trusted []netip.Prefix, ignoreForwarded bool) {
+	c.forwarded = true
+	if len(trusted) == 0 || !trustedIP(trusted, remoteHost(c.Request.RemoteAddr)) {
+		return
+	}
+
+	var chain []forwardedElement
+	if v := c.Request.Header.Values("Forwarded"); len(v) > 0 && !ignoreForwarded {
+		chain = parseForwarded(strings.Join(v, ","))
+	} else {
+		chain = parseXForwarded(c.Request)
+	}
+	if len(chain) == 0 {
+		return
+	}
+
+	// walk from the closest proxy, the first untrusted hop is the client
+	client := chain[0]
+	for i := len(chain) - 1; i >= 0; i-- {
+		if !trustedIP(trusted, chain[i].ip) {
+			client = chain[i]
+			break
+		}
+	}
+
+	if client.ip != "" {
+		c.clientIP = client.ip
+	}
+	if client.proto != "" {
+		c.scheme = strings.ToLower(client.proto)
+	}
+	if client.host != "" {
+		c.host = client.host
+	}
+}
+
+// parseForwarded parse a RFC 7239 header value into its elements.
+func parseForwarded(value string) []forwardedElement {
+	var out []forwardedElement
+	for _, element := range splitQuoted(value, ',') {
+		var e forwardedElement
+		for _, pair := range splitQuoted(element, ';') {
+			k, v, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if !ok {
+				continue
+			}
+			v = strings.Trim(strings.TrimSpace(v), `"`)
+			switch strings.ToLower(strings.TrimSpace(k)) {
+			case "for":
+				e.ip = forwardedNodeIP(v)
+			case "proto":
+				e.proto = v
+			case "host":
+				e.host = v
+			}
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+// parseXForwarded build the chain from X-Forwarded-For, the proto and
+// host headers only describe the client side so they go on the first hop.
+func parseXForwarded(req *http.Request) []forwardedElement {
+	var out []forwardedElement
+	for _, v := range req.Header.Values("X-Forwarded-For") {
+		for _, ip := range strings.Split(v, ",") {
+			out = append(out, forwardedElement{ip: forwardedNodeIP(strings.TrimSpace(ip))})
+		}
+	}
+	proto := firstHeaderValue(req.Header.Get("X-Forwarded-Proto"))
+	host := firstHeaderValue(req.Header.Get("X-Forwarded-Host"))
+	if len(out) == 0 {
+		if proto == "" && host == "" {
+			return nil
+		}
+		out = append(out, forwardedElement{})
+	}
+	for i := range out {
+		out[i].proto, out[i].host = proto, host
+	}
+	return out
+}
+
+// forwardedNodeIP extract the IP of a node: "192.0.2.1:80", "[2001:db8::1]:80".
+// Obfuscated identifiers and "unknown" return empty string.
+func forwardedNodeIP(node string) string {
+	if h, _, err := net.SplitHostPort(node); err == nil {
+		node = h
+	}
+	node = strings.Trim(node, "[]")
+	if addr, err := netip.ParseAddr(node); err == nil {
+		return addr.Unmap().String()
+	}
+	return ""
+}
+
+// splitQuoted split s on sep, ignoring separators inside double quotes.
+func splitQuoted(s string, sep byte) []string {
+	var out []string
+	quoted := false
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			quoted = !quoted
+		case sep:
+			if !quoted {
+				out = append(out, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(out, s[start:])
+}
+
+// firstHeaderValue return the first item of a comma separated header.
+func firstHeaderValue(v string) string {
+	first, _, _ := strings.Cut(v, ",")
+	return strings.TrimSpace(first)
+}
+
+// parsePrefixes parse IPs and CIDRs, a single IP become a /32 or /128 prefix.
+func parsePrefixes(list []string) ([]netip.Prefix, error) {
+	out := make([]netip.Prefix, 0, len(list))
+	for _, s := range list {
+		if strings.Contains(s, "/") {
+			p, err := netip.ParsePrefix(s)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, p.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(s)
+		if err != nil {
+			return nil, err
+		}
+		addr = addr.Unmap()
+		out = append(out, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return out, nil
+}
+
+// trustedIP report whether ip is inside one of the prefixes.
+func trustedIP(prefixes []netip.Prefix, ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, p := range prefixes {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// remoteHost strip the port from a "host:port" address.
+func remoteHost(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
@@ -5,13 +5,15 @@ package glaze
 
 import (
 	"context"
-	"fmt"
+	"html/template"
 	"io"
+	"net"
 	"net/http"
+	"net/netip"
 	"os"
-	"os/signal"
 	"sort"
-	"syscall"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -21,12 +23,67 @@ const defaultMultipartMemory = 40 << 20 // default size 40 MB
 // It holds routes, configs, trees, and HTTP server features.
 type Engine struct {
 	Route
-	routeList   []RouteInfo // all routes information
-	releaseMode bool        // flag for release mode
+	routeList  []RouteInfo // all routes information
+	groups     []*Route    // groups created, checked by Lint
+	lintIssues []LintIssue // issues found while registering routes
 
-	writer          io.Writer        // where log is written
+	writer          io.Writer        // where debug and info messages are written
+	errWriter       io.Writer        // where error messages are written
+	logLevel        LogLevel         // minimum level of engine messages
 	MultipartMemory int64            // memory limit for multipart form
 	trees           map[string]*node // route trees (per method)
+	mounts          []mount          // prefix handlers, see Route.Proxy
+
+	phases       [3][]phaseHandler // middleware registered with UsePhase
+	preRouting   HandlersChain     // pre-routing middleware + dispatch
+	postRouting  HandlersChain     // run before the matched route handlers
+	postResponse HandlersChain     // run after the handler chain
+	noRoute      HandlersChain     // handlers of unmatched requests, see NoRoute
+
+	json           JSONConfig                 // JSON rendering defaults, see WithJSON
+	html           HTMLRenderer               // renderer of Context.HTML, see WithHTMLRenderer
+	templateFuncs  template.FuncMap           // functions of the loaded templates, see SetFuncMap
+	charsets       map[string]Transcoder      // text charsets offered, see WithCharset
+	htmlWatch      *DevWatch                  // reload of the loaded templates, see RunDev
+	assets         *Assets                    // fingerprinted files of the asset function, see Route.Assets
+	errorMappings  []errorMapping             // error to response mapping, see MapError
+	errorTemplates map[int]*template.Template // HTML default responses, see ErrorTemplate
+	problems       bool                       // RFC 7807 error responses, see WithProblemDetails
+	validationMsgs map[string]string          // rule message templates, see WithValidationMessage
+	trustedProxies []netip.Prefix             // see SetTrustedProxies
+	reporter       Reporter                   // panic and error reporting hook
+	flags          FlagProvider               // feature flags, see WithFlags
+	slowThreshold  time.Duration              // slow request report, see WithSlowThreshold
+	server         serverConfig               // http.Server parameters of the Run helpers
+	startHooks     []StartHook                // run before serving, see OnStart
+	onRequest      HandlersChain              // run before every request, see OnRequest
+	onResponse     HandlersChain              // run after every request, see OnResponse
+	injectors      []ContextInjector          // request context values, see InjectContext
+	contextFactory func(*Context) any         // application context, see ContextFactory
+	providers      map[any]*provider          // see Provide and ProvideScoped
+	namedRoutes    map[string]string          // path by route name, see Route.Name
+	middleware     map[string]HandlerFunc     // middleware by name, see RegisterMiddleware
+	pathPolicy     PathPolicy                 // request path rules, see WithPathPolicy
+	trailingSlash  bool                       // redirect to the registered trailing slash, see WithRedirectTrailingSlash
+	fixedPath      bool                       // redirect to the clean path, see WithRedirectFixedPath
+	fixedPathCase  bool                       // fixed path ignore the case of static segments
+
+	serversMu sync.Mutex
+	srv       *http.Server              // shared server, see Server
+	servers   map[*http.Server]struct{} // servers started by the Run helpers
+	conns     connTracker               // connection counters, see ConnStats
+	bodyStats bodyStats                 // body parsing counters, see BodyStats
+	drain     chan struct{}             // closed on shutdown, see Context.Done
+	drainMu   sync.Mutex
+	ready     chan struct{} // closed once a Run helper serve, see Ready
+	readyOnce sync.Once
+	addr      net.Addr // address of the first listener, see Addr
+
+	background       context.Context    // canceled when shutdown give up, see Context.Go
+	cancelBackground context.CancelFunc // cancel background
+	running          sync.WaitGroup     // goroutines started with Context.Go
+
+	pool sync.Pool // reused *Context
 }
 
 // make sure Engine implement Router
@@ -42,10 +99,23 @@ func New(cfg ...ConfigsFunc) *Engine {
 		MultipartMemory: defaultMultipartMemory,
 		trees:           make(map[string]*node),
 		writer:          os.Stdout,
+		errWriter:       os.Stderr,
+		drain:           make(chan struct{}),
+		ready:           make(chan struct{}),
+		pathPolicy:      defaultPathPolicy,
+		server: serverConfig{
+			readHeaderTimeout: defaultReadHeaderTimeout,
+			idleTimeout:       defaultIdleTimeout,
+		},
 	}
 
+	engine.background, engine.cancelBackground = context.WithCancel(context.Background())
+
 	// self reference to engine
 	engine.engine = engine
+	engine.pool.New = func() any {
+		return &Context{engine: engine}
+	}
 	return engine.Config(cfg...)
 }
 
@@ -75,93 +145,121 @@ func (e *Engine) RoutesInfo() []RouteInfo {
 
 // ServeHTTP implement http.Handler.
 // It find route, create context, and run handlers.
+// Contexts are pooled, a static route is served without allocation.
 func (e *Engine) ServeHTTP(w http.ResponseWriter, req *http.Request) {
-	handlers, params := e.findRoute(req.Method, req.URL.Path)
-	if handlers == nil {
-		// if route not found, return 404
-		http.NotFound(w, req)
-		return
+	c := e.pool.Get().(*Context)
+	c.reset(w, req)
+	e.handleRequest(c)
+	e.pool.Put(c)
+}
+
+// handleRequest run the hooks, middleware and route handlers of c.
+func (e *Engine) handleRequest(c *Context) {
+	if len(e.injectors) > 0 {
+		e.injectContext(c)
+	}
+	for _, h := range e.onRequest {
+		h(c)
+	}
+	if len(e.onResponse) > 0 {
+		defer e.runResponseHooks(c)
+	}
+	if e.slowThreshold > 0 {
+		defer e.checkSlow(c, time.Now())
 	}
 
-	// create context for this request
-	c := &Context{
-		Writer:   w,
-		Request:  req,
-		Params:   params,
-		handlers: handlers,
-		index:    -1,
-		querys:   req.URL.Query(),
-		engine:   e.engine,
+	if e.preRouting != nil {
+		// pre-routing middleware, dispatch is the last handler
+		c.handlers = e.preRouting
+		c.Next()
+	} else {
+		e.dispatch(c)
+	}
+
+	for _, h := range e.postResponse {
+		h(c)
 	}
-	// start handler chain
-	c.Next()
 }
 
-// RunAndListen starts an HTTP server at the given address.
-// This function is simple: it does not support graceful shutdown.
-//
-// Example:
-//
-//	e := glaze.New()
-//	e.RunAndListen(":8080")
-func (e *Engine) RunAndListen(addr string) error {
-	if !e.releaseMode {
-		// show all routes in console
-		for _, r := range e.RoutesInfo() {
-			fmt.Fprintf(e.writer, "%-6s %s\n", r.Method, r.Path)
+// dispatch find the route of the request and run its handler chain.
+func (e *Engine) dispatch(c *Context) {
+	if e.fixedPath && e.redirectFixedPath(c) {
+		return
+	}
+	if status := e.pathPolicy.check(c.Request); status != 0 {
+		c.defaultResponse(status)
+		return
+	}
+
+	n := e.findRoute(c.Request.Method, c.Request.URL.Path, &c.Params)
+	if n == nil || n.handlers == nil {
+		// mounts serving the method, then 405 when the path is registered
+		// with other methods, then the other mounts, else 404
+		c.Params = c.Params[:0] // from a partial match
+		m := e.findMount(c.Request.URL.Path)
+		if m == nil || !m.serves(c.Request.Method) {
+			if allow := e.allowedMethods(c.Request.URL.Path); len(allow) > 0 {
+				c.Writer.Header().Set("Allow", strings.Join(allow, ", "))
+				c.defaultResponse(http.StatusMethodNotAllowed)
+				return
+			}
 		}
+		if m == nil {
+			e.notFound(c)
+			return
+		}
+		n = m.node
+	} else if e.trailingSlash && redirectTrailingSlash(c, n.route.Path) {
+		return
 	}
-	if !e.releaseMode {
-		fmt.Fprintf(e.writer, "listen on %s\n", addr)
+
+	c.route = n.route
+	c.handlers = n.handlers
+	if len(e.postRouting) > 0 {
+		c.handlers = make(HandlersChain, 0, len(e.postRouting)+len(n.handlers))
+		c.handlers = append(append(c.handlers, e.postRouting...), n.handlers...)
 	}
-	return http.ListenAndServe(addr, e)
+	c.index = -1
+
+	// start handler chain
+	c.Next()
 }
 
-// ListenAndGraceful starts an HTTP server at the given address,
-// but it also listen for system signals (SIGINT, SIGTERM).
-// When signal received, it shutdown the server gracefully with timeout.
+// NoRoute set the handlers of the requests matching no route and no mount,
+// run after the engine middleware like a route handler. Nothing written
+// by them send the default 404 response.
 //
-// Example:
+// Usage:
 //
-//	e := glaze.New()
-//	e.ListenAndGraceful(":8080")
-func (e *Engine) ListenAndGraceful(addr string) error {
-	if !e.releaseMode {
-		// show all routes in console
-		for _, r := range e.RoutesInfo() {
-			fmt.Fprintf(e.writer, "%-6s %s\n", r.Method, r.Path)
-		}
-	}
+//	e.NoRoute(func(c *glaze.Context) {
+//	    c.JSON(http.StatusNotFound, glaze.M{"error": "no such endpoint", "path": c.Request.URL.Path})
+//	})
+func (e *Engine) NoRoute(handlers ...HandlerFunc) *Engine {
+	e.noRoute = handlers
+	return e
+}
 
-	// create http server
-	srv := &http.Server{
-		Addr:    addr,
-		Handler: e.engine,
+// notFound run the NoRoute handlers, then the default 404 response.
+func (e *Engine) notFound(c *Context) {
+	if len(e.noRoute) > 0 {
+		c.handlers = make(HandlersChain, 0, len(e.postRouting)+len(e.Handler)+len(e.noRoute))
+		c.handlers = append(append(append(c.handlers, e.postRouting...), e.Handler...), e.noRoute...)
+		c.index = -1
+		c.Next()
 	}
-
-	if !e.releaseMode {
-		fmt.Fprintf(e.writer, "listen on %s\n", addr)
+	if !c.Writer.Written() {
+		c.defaultResponse(http.StatusNotFound)
 	}
+}
 
-	// run server in goroutine
-	go func() {
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			fmt.Fprintf(e.writer, "listen: %s\n", err)
+// allowedMethods return the sorted methods having a route for path.
+func (e *Engine) allowedMethods(path string) []string {
+	var allow []string
+	for method := range e.trees {
+		if n := e.findRoute(method, path, nil); n != nil && n.handlers != nil {
+			allow = append(allow, method)
 		}
-	}()
-
-	// wait for signal
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-	fmt.Fprint(e.writer, "Shutdown Server")
-
-	// graceful shutdown with context timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	if err := srv.Shutdown(ctx); err != nil {
-		return err
-	}
-	fmt.Fprint(e.writer, "Server exiting")
-	return nil
+	}
+	sort.Strings(allow)
+	return allow
 }
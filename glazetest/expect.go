@@ -0,0 +1,223 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glazetest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Response hold a recorded response and assert on it, each assertion
+// return the response so they can be chained.
+type Response struct {
+	t        testing.TB
+	name     string
+	Recorder *httptest.ResponseRecorder
+}
+
+// Status assert the status code.
+func (r *Response) Status(code int) *Response {
+	r.t.Helper()
+	if r.Recorder.Code != code {
+		r.errorf("status = %d, want %d, body: %s", r.Recorder.Code, code, truncate(r.Recorder.Body.String()))
+	}
+	return r
+}
+
+// Header assert the value of a response header.
+func (r *Response) Header(key, value string) *Response {
+	r.t.Helper()
+	if got := r.Recorder.Header().Get(key); got != value {
+		r.errorf("header %s = %q, want %q", key, got, value)
+	}
+	return r
+}
+
+// Body assert the whole body.
+func (r *Response) Body(body string) *Response {
+	r.t.Helper()
+	if got := r.Recorder.Body.String(); got != body {
+		r.errorf("body = %q, want %q", truncate(got), body)
+	}
+	return r
+}
+
+// BodyContains assert the body contains s.
+func (r *Response) BodyContains(s string) *Response {
+	r.t.Helper()
+	if !strings.Contains(r.Recorder.Body.String(), s) {
+		r.errorf("body %q does not contain %q", truncate(r.Recorder.Body.String()), s)
+	}
+	return r
+}
+
+// JSON assert the body is JSON equal to expected, a JSON string or a
+// value encoded with encoding/json. Key order and spacing are ignored.
+func (r *Response) JSON(expected any) *Response {
+	r.t.Helper()
+	got, ok := r.decode()
+	if !ok {
+		return r
+	}
+	want, err := normalize(expected)
+	if err != nil {
+		r.errorf("invalid expected JSON: %v", err)
+		return r
+	}
+	if !reflect.DeepEqual(got, want) {
+		r.errorf("JSON = %s, want %s", encode(got), encode(want))
+	}
+	return r
+}
+
+// JSONPath assert the JSON value at path, "$.user.name" or "$.items[0].id".
+// The expected value is compared after a JSON round trip, so 1 match 1.0.
+func (r *Response) JSONPath(path string, expected any) *Response {
+	r.t.Helper()
+	doc, ok := r.decode()
+	if !ok {
+		return r
+	}
+	got, err := lookup(doc, path)
+	if err != nil {
+		r.errorf("JSON path %s: %v", path, err)
+		return r
+	}
+	want, err := roundTrip(expected)
+	if err != nil {
+		r.errorf("invalid expected value: %v", err)
+		return r
+	}
+	if !reflect.DeepEqual(got, want) {
+		r.errorf("JSON path %s = %s, want %s", path, encode(got), encode(want))
+	}
+	return r
+}
+
+// Cookie assert a cookie is set by the response and return it, nil when missing.
+func (r *Response) Cookie(name string) *http.Cookie {
+	r.t.Helper()
+	for _, c := range r.Recorder.Result().Cookies() {
+		if c.Name == name {
+			return c
+		}
+	}
+	r.errorf("cookie %s is not set", name)
+	return nil
+}
+
+// DecodeJSON decode the body into v.
+func (r *Response) DecodeJSON(v any) *Response {
+	r.t.Helper()
+	if err := json.Unmarshal(r.Recorder.Body.Bytes(), v); err != nil {
+		r.errorf("decode JSON: %v, body: %s", err, truncate(r.Recorder.Body.String()))
+	}
+	return r
+}
+
+func (r *Response) decode() (any, bool) {
+	r.t.Helper()
+	var doc any
+	if err := json.Unmarshal(r.Recorder.Body.Bytes(), &doc); err != nil {
+		r.errorf("body is not JSON: %v, body: %s", err, truncate(r.Recorder.Body.String()))
+		return nil, false
+	}
+	return doc, true
+}
+
+// normalize decode a JSON string, or round trip a value, to compare it.
+func normalize(v any) (any, error) {
+	data, ok := v.(string)
+	if !ok {
+		return roundTrip(v)
+	}
+	var out any
+	err := json.Unmarshal([]byte(data), &out)
+	return out, err
+}
+
+// roundTrip encode and decode v, to compare it with decoded JSON.
+func roundTrip(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out any
+	err = json.Unmarshal(data, &out)
+	return out, err
+}
+
+// lookup walk a decoded JSON document with a "$.a.b[0]" path.
+func lookup(doc any, path string) (any, error) {
+	rest, ok := strings.CutPrefix(path, "$")
+	if !ok {
+		return nil, errors.New("path must start with $")
+	}
+	cur := doc
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			key := rest[:end]
+			rest = rest[end:]
+			obj, ok := cur.(map[string]any)
+			if !ok {
+				return nil, errors.New("not an object before ." + key)
+			}
+			if cur, ok = obj[key]; !ok {
+				return nil, errors.New("no key " + strconv.Quote(key))
+			}
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, errors.New("missing ]")
+			}
+			i, err := strconv.Atoi(rest[1:end])
+			if err != nil {
+				return nil, errors.New("invalid index " + rest[1:end])
+			}
+			rest = rest[end+1:]
+			arr, ok := cur.([]any)
+			if !ok {
+				return nil, errors.New("not an array before [" + strconv.Itoa(i) + "]")
+			}
+			if i < 0 || i >= len(arr) {
+				return nil, errors.New("index " + strconv.Itoa(i) + " out of range")
+			}
+			cur = arr[i]
+		default:
+			return nil, errors.New("unexpected " + strconv.Quote(rest[:1]))
+		}
+	}
+	return cur, nil
+}
+
+func encode(v any) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+func truncate(s string) string {
+	if len(s) > 200 {
+		return s[:200] + "..."
+	}
+	return s
+}
+
+// errorf report a failure with the request name.
+func (r *Response) errorf(format string, args ...any) {
+	r.t.Helper()
+	r.t.Errorf("%s: %s", r.name, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,233 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+// Package glazetest is a test client for glaze engines, or any http.Handler,
+// with fluent request building and response assertions.
+//
+// Usage:
+//
+//	func TestGetUser(t *testing.T) {
+//	    tc := glazetest.New(newEngine())
+//	    tc.GET("/users/1").WithHeader("Authorization", "Bearer token").
+//	        Expect(t).
+//	        Status(http.StatusOK).
+//	        JSONPath("$.name", "bob")
+//	}
+//
+// Cookies set by responses are kept by the client and sent with the next
+// requests, like a browser session.
+package glazetest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// baseURL is the URL of the requests, cookies are stored for its host.
+var baseURL = &url.URL{Scheme: "http", Host: "example.com"}
+
+// Client send requests to a handler without network.
+type Client struct {
+	handler http.Handler
+	header  http.Header
+	jar     http.CookieJar
+}
+
+// New create a client of h, usually a *glaze.Engine.
+func New(h http.Handler) *Client {
+	jar, _ := cookiejar.New(nil) // error is always nil
+	return &Client{handler: h, header: make(http.Header), jar: jar}
+}
+
+// WithHeader set a header sent with every request of the client.
+func (c *Client) WithHeader(key, value string) *Client {
+	c.header.Set(key, value)
+	return c
+}
+
+// Cookies return the cookies stored by the client.
+func (c *Client) Cookies() []*http.Cookie {
+	return c.jar.Cookies(baseURL)
+}
+
+// SetCookie store a cookie sent with the next requests.
+func (c *Client) SetCookie(cookie *http.Cookie) *Client {
+	c.jar.SetCookies(baseURL, []*http.Cookie{cookie})
+	return c
+}
+
+// ClearCookies remove the stored cookies, to start a new session.
+func (c *Client) ClearCookies() *Client {
+	c.jar, _ = cookiejar.New(nil)
+	return c
+}
+
+func (c *Client) GET(path string) *Request     { return c.Request(http.MethodGet, path) }
+func (c *Client) POST(path string) *Request    { return c.Request(http.MethodPost, path) }
+func (c *Client) PUT(path string) *Request     { return c.Request(http.MethodPut, path) }
+func (c *Client) PATCH(path string) *Request   { return c.Request(http.MethodPatch, path) }
+func (c *Client) DELETE(path string) *Request  { return c.Request(http.MethodDelete, path) }
+func (c *Client) HEAD(path string) *Request    { return c.Request(http.MethodHead, path) }
+func (c *Client) OPTIONS(path string) *Request { return c.Request(http.MethodOptions, path) }
+
+// Request start a request of any method.
+func (c *Client) Request(method, path string) *Request {
+	return &Request{client: c, method: method, path: path, header: c.header.Clone(), query: url.Values{}}
+}
+
+// Request is a request being built, send it with Expect or Do.
+type Request struct {
+	client  *Client
+	method  string
+	path    string
+	header  http.Header
+	query   url.Values
+	cookies []*http.Cookie
+	body    io.Reader
+	err     error
+
+	multipart *multipart.Writer
+	parts     *bytes.Buffer
+}
+
+// WithHeader set a request header.
+func (r *Request) WithHeader(key, value string) *Request {
+	r.header.Set(key, value)
+	return r
+}
+
+// WithQuery add a query parameter.
+func (r *Request) WithQuery(key, value string) *Request {
+	r.query.Add(key, value)
+	return r
+}
+
+// WithCookie send a cookie with this request only.
+func (r *Request) WithCookie(name, value string) *Request {
+	r.cookies = append(r.cookies, &http.Cookie{Name: name, Value: value})
+	return r
+}
+
+// WithBody set the raw body and its content type.
+func (r *Request) WithBody(contentType string, body io.Reader) *Request {
+	r.header.Set("Content-Type", contentType)
+	r.body = body
+	return r
+}
+
+// WithJSON send v encoded as JSON, a string or []byte is sent as is.
+func (r *Request) WithJSON(v any) *Request {
+	var data []byte
+	switch v := v.(type) {
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		data, r.err = json.Marshal(v)
+	}
+	return r.WithBody("application/json", bytes.NewReader(data))
+}
+
+// WithForm send url encoded form values.
+func (r *Request) WithForm(values url.Values) *Request {
+	return r.WithBody("application/x-www-form-urlencoded", strings.NewReader(values.Encode()))
+}
+
+// WithMultipartField add a field to a multipart form body.
+func (r *Request) WithMultipartField(name, value string) *Request {
+	w := r.multipartWriter()
+	if r.err == nil {
+		r.err = w.WriteField(name, value)
+	}
+	return r
+}
+
+// WithMultipartFile add a file to a multipart form body.
+func (r *Request) WithMultipartFile(field, filename string, content []byte) *Request {
+	w := r.multipartWriter()
+	if r.err != nil {
+		return r
+	}
+	part, err := w.CreateFormFile(field, filename)
+	if err == nil {
+		_, err = part.Write(content)
+	}
+	r.err = err
+	return r
+}
+
+func (r *Request) multipartWriter() *multipart.Writer {
+	if r.multipart == nil {
+		r.parts = &bytes.Buffer{}
+		r.multipart = multipart.NewWriter(r.parts)
+		r.header.Set("Content-Type", r.multipart.FormDataContentType())
+		r.body = r.parts
+	}
+	return r.multipart
+}
+
+// Build return the *http.Request.
+func (r *Request) Build() (*http.Request, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	if r.multipart != nil {
+		if err := r.multipart.Close(); err != nil {
+			return nil, err
+		}
+		r.multipart = nil
+	}
+
+	target := r.path
+	if len(r.query) > 0 {
+		sep := "?"
+		if strings.Contains(target, "?") {
+			sep = "&"
+		}
+		target += sep + r.query.Encode()
+	}
+	req := httptest.NewRequest(r.method, baseURL.String()+target, r.body)
+	req.Header = r.header
+	for _, cookie := range r.client.jar.Cookies(req.URL) {
+		req.AddCookie(cookie)
+	}
+	for _, cookie := range r.cookies {
+		req.AddCookie(cookie)
+	}
+	return req, nil
+}
+
+// Do send the request and return the recorded response.
+// Cookies of the response are stored by the client.
+func (r *Request) Do() (*httptest.ResponseRecorder, error) {
+	req, err := r.Build()
+	if err != nil {
+		return nil, err
+	}
+	w := httptest.NewRecorder()
+	r.client.handler.ServeHTTP(w, req)
+	if cookies := w.Result().Cookies(); len(cookies) > 0 {
+		r.client.jar.SetCookies(req.URL, cookies)
+	}
+	return w, nil
+}
+
+// Expect send the request and return the response assertions,
+// failures are reported to t.
+func (r *Request) Expect(t testing.TB) *Response {
+	t.Helper()
+	w, err := r.Do()
+	if err != nil {
+		t.Fatalf("glazetest: %s %s: %v", r.method, r.path, err)
+	}
+	return &Response{t: t, Recorder: w, name: r.method + " " + r.path}
+}
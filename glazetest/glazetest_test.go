@@ -0,0 +1,101 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glazetest
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/nrhox/glaze"
+	"github.com/stretchr/testify/assert"
+)
+
+// recorder capture the failures of assertions expected to fail.
+type recorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+func (r *recorder) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func newEngine() *glaze.Engine {
+	r := glaze.New()
+	r.Get("/users/:id", func(c *glaze.Context) {
+		c.JSON(http.StatusOK, glaze.M{"id": c.Param("id"), "name": "bob", "roles": []string{"admin"},
+			"auth": c.GetHeader("Authorization"), "q": c.Query("v")})
+	})
+	r.Post("/login", func(c *glaze.Context) {
+		http.SetCookie(c.Writer, &http.Cookie{Name: "session", Value: "s1", Path: "/"})
+		c.String(http.StatusOK, c.Request.PostFormValue("user"))
+	})
+	r.Get("/me", func(c *glaze.Context) {
+		cookie, err := c.Request.Cookie("session")
+		if err != nil {
+			c.String(http.StatusUnauthorized, "anonymous")
+			return
+		}
+		c.String(http.StatusOK, cookie.Value)
+	})
+	r.Post("/upload", func(c *glaze.Context) {
+		file, header, err := c.Request.FormFile("doc")
+		if err != nil {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		defer file.Close()
+		c.String(http.StatusOK, c.Request.FormValue("title")+" "+header.Filename)
+	})
+	r.Post("/echo", func(c *glaze.Context) {
+		c.JSON(http.StatusOK, glaze.M{"type": c.GetHeader("Content-Type")})
+	})
+	return r
+}
+
+func TestClient(t *testing.T) {
+	tc := New(newEngine()).WithHeader("Authorization", "Bearer t")
+
+	tc.GET("/users/1").WithQuery("v", "2").Expect(t).
+		Status(http.StatusOK).
+		Header("Content-Type", "application/json; charset=utf-8").
+		JSONPath("$.name", "bob").
+		JSONPath("$.roles[0]", "admin").
+		JSONPath("$.auth", "Bearer t").
+		JSONPath("$.q", "2").
+		JSON(`{"id":"1","name":"bob","roles":["admin"],"auth":"Bearer t","q":"2"}`)
+
+	// cookies of the session are kept
+	tc.GET("/me").Expect(t).Status(http.StatusUnauthorized)
+	resp := tc.POST("/login").WithForm(url.Values{"user": {"jalu"}}).Expect(t).Status(http.StatusOK).Body("jalu")
+	assert.Equal(t, "s1", resp.Cookie("session").Value)
+	tc.GET("/me").Expect(t).Body("s1")
+	tc.ClearCookies().GET("/me").WithCookie("session", "other").Expect(t).Body("other")
+
+	tc.POST("/upload").
+		WithMultipartField("title", "report").
+		WithMultipartFile("doc", "q1.pdf", []byte("%PDF")).
+		Expect(t).Status(http.StatusOK).Body("report q1.pdf")
+
+	tc.POST("/echo").WithJSON(map[string]int{"a": 1}).Expect(t).JSONPath("$.type", "application/json")
+}
+
+func TestAssertionFailures(t *testing.T) {
+	rec := &recorder{TB: t}
+	New(newEngine()).GET("/users/1").Expect(rec).
+		Status(http.StatusCreated).
+		JSONPath("$.name", "alice").
+		JSONPath("$.roles[3]", "x").
+		JSONPath("$.missing", 1).
+		BodyContains("nope")
+
+	assert.Len(t, rec.failures, 5)
+	assert.Contains(t, rec.failures[0], "GET /users/1: status = 200, want 201")
+	assert.Contains(t, rec.failures[1], `JSON path $.name = "bob", want "alice"`)
+	assert.Contains(t, rec.failures[2], "index 3 out of range")
+	assert.Contains(t, rec.failures[3], `no key "missing"`)
+}
@@ -0,0 +1,486 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package grpcjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/nrhox/glaze"
+)
+
+var marshalerType = reflect.TypeFor[json.Marshaler]()
+
+// Marshal encode a message to proto3 JSON.
+func Marshal(v any) ([]byte, error) {
+	return json.Marshal(encode(reflect.ValueOf(v), false))
+}
+
+// Render write the message as proto3 JSON. The "fields" query parameter
+// is a field mask selecting the fields of the response, "id,profile.name".
+func Render(c *glaze.Context, status int, v any) {
+	out := encode(reflect.ValueOf(v), false)
+	if mask := ParseFieldMask(c.Query("fields")); len(mask) > 0 {
+		out = ApplyFieldMask(out, mask)
+	}
+	c.JSON(status, out)
+}
+
+// Bind decode a proto3 JSON body into the message pointed by v. Fields
+// are matched by their JSON (lowerCamelCase) and original names, 64 bit
+// integers can be strings. A decode error is a *Status InvalidArgument.
+func Bind(c *glaze.Context, v any) error {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return Errorf(InvalidArgument, "read body: %v", err)
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	if err := Unmarshal(body, v); err != nil {
+		return Errorf(InvalidArgument, "%v", err)
+	}
+	return nil
+}
+
+// Unmarshal decode proto3 JSON into the message pointed by v.
+func Unmarshal(data []byte, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return errors.New("grpcjson: Unmarshal need a non nil pointer")
+	}
+	return decode(data, rv.Elem(), "", false)
+}
+
+// UpdateMask return the field mask of an update request, from the
+// "update_mask" or "updateMask" query parameter.
+func UpdateMask(c *glaze.Context) []string {
+	if m := c.Query("update_mask"); m != "" {
+		return ParseFieldMask(m)
+	}
+	return ParseFieldMask(c.Query("updateMask"))
+}
+
+// ParseFieldMask split a comma separated field mask, paths are converted
+// to lowerCamelCase: "display_name,profile.avatar_url" give
+// ["displayName", "profile.avatarUrl"].
+func ParseFieldMask(mask string) []string {
+	var paths []string
+	for _, p := range strings.Split(mask, ",") {
+		if p = strings.TrimSpace(p); p == "" {
+			continue
+		}
+		parts := strings.Split(p, ".")
+		for i, part := range parts {
+			parts[i] = lowerCamel(part)
+		}
+		paths = append(paths, strings.Join(parts, "."))
+	}
+	return paths
+}
+
+// ApplyFieldMask keep only the paths of an encoded message, a
+// map[string]any as returned by json.Unmarshal or the Render encoder.
+// Lists of messages are masked element by element.
+func ApplyFieldMask(v any, paths []string) any {
+	tree := map[string]any{}
+	for _, p := range paths {
+		node := tree
+		for _, part := range strings.Split(p, ".") {
+			next, ok := node[part].(map[string]any)
+			if !ok {
+				next = map[string]any{}
+				node[part] = next
+			}
+			node = next
+		}
+		clear(node)
+		node[""] = nil // whole field
+	}
+	return mask(v, tree)
+}
+
+func mask(v any, tree map[string]any) any {
+	if _, all := tree[""]; all {
+		return v
+	}
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(tree))
+		for name, sub := range tree {
+			if fv, ok := v[name]; ok {
+				out[name] = mask(fv, sub.(map[string]any))
+			}
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, e := range v {
+			out[i] = mask(e, tree)
+		}
+		return out
+	}
+	return v
+}
+
+// encode convert v to JSON values following the proto3 mapping, enum
+// values (and the elements of enum lists and maps) by their name.
+func encode(v reflect.Value, enum bool) any {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		if v.Type().Implements(marshalerType) {
+			return v.Interface()
+		}
+		v = v.Elem()
+	}
+	if v.Type().Implements(marshalerType) {
+		return v.Interface()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		out := map[string]any{}
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			if sf.Tag.Get("protobuf_oneof") != "" {
+				// the set member is inlined, even with its zero value
+				if member, ok := oneofMember(v.Field(i)); ok {
+					out[member.name] = encode(member.value, member.enum)
+				}
+				continue
+			}
+			name, ok := jsonName(sf)
+			if !ok || v.Field(i).IsZero() {
+				continue
+			}
+			out[name] = encode(v.Field(i), isEnum(sf))
+		}
+		return out
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Bytes() // base64 by encoding/json
+		}
+		fallthrough
+	case reflect.Array:
+		out := make([]any, v.Len())
+		for i := range out {
+			out[i] = encode(v.Index(i), enum)
+		}
+		return out
+	case reflect.Map:
+		out := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out[fmt.Sprint(iter.Key().Interface())] = encode(iter.Value(), enum)
+		}
+		return out
+	case reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Int32:
+		if s, ok := v.Interface().(fmt.Stringer); ok && enum {
+			// generated String return the number of unknown values
+			if name := s.String(); !isNumber(name) {
+				return name
+			}
+		}
+	}
+	return v.Interface()
+}
+
+// decode set v from data, the inverse of encode.
+func decode(data []byte, v reflect.Value, path string, enum bool) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return nil
+	}
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		if v.Type().Implements(reflect.TypeFor[json.Unmarshaler]()) {
+			return json.Unmarshal(data, v.Interface())
+		}
+		return decode(data, v.Elem(), path, enum)
+	}
+	if v.CanAddr() && v.Addr().Type().Implements(reflect.TypeFor[json.Unmarshaler]()) {
+		return json.Unmarshal(data, v.Addr().Interface())
+	}
+
+	var err error
+	switch v.Kind() {
+	case reflect.Struct:
+		var fields map[string]json.RawMessage
+		if err = json.Unmarshal(data, &fields); err != nil {
+			break
+		}
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			if sf.Tag.Get("protobuf_oneof") != "" {
+				if err := decodeOneof(fields, v, v.Field(i), path); err != nil {
+					return err
+				}
+				continue
+			}
+			name, ok := jsonName(sf)
+			if !ok {
+				continue
+			}
+			raw, found := fields[name]
+			if !found {
+				raw, found = fields[protoName(sf)]
+			}
+			if found {
+				if err := decode(raw, v.Field(i), path+name+".", isEnum(sf)); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			err = json.Unmarshal(data, v.Addr().Interface())
+			break
+		}
+		var items []json.RawMessage
+		if err = json.Unmarshal(data, &items); err != nil {
+			break
+		}
+		slice := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, raw := range items {
+			if err := decode(raw, slice.Index(i), path, enum); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+		return nil
+	case reflect.Int32:
+		var name string
+		if enum && json.Unmarshal(data, &name) == nil {
+			n, ok := enumNumber(v.Type(), name)
+			if !ok {
+				err = errors.New("unknown enum value " + strconv.Quote(name))
+				break
+			}
+			v.SetInt(int64(n))
+			return nil
+		}
+		err = json.Unmarshal(data, v.Addr().Interface())
+	case reflect.Int64, reflect.Uint64:
+		data = bytes.Trim(bytes.TrimSpace(data), `"`)
+		if v.Kind() == reflect.Int64 {
+			var n int64
+			n, err = strconv.ParseInt(string(data), 10, 64)
+			v.SetInt(n)
+		} else {
+			var n uint64
+			n, err = strconv.ParseUint(string(data), 10, 64)
+			v.SetUint(n)
+		}
+	default:
+		err = json.Unmarshal(data, v.Addr().Interface())
+	}
+	if err != nil {
+		return fmt.Errorf("invalid value for field %s: %w", strings.TrimSuffix(path, "."), unwrapSyntax(err))
+	}
+	return nil
+}
+
+// unwrapSyntax drop the Go type details of JSON errors.
+func unwrapSyntax(err error) error {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return errors.New("unexpected " + typeErr.Value)
+	}
+	return err
+}
+
+// isEnum report whether the protobuf tag of sf declare an enum field, or
+// a map of enum values.
+func isEnum(sf reflect.StructField) bool {
+	for _, key := range []string{"protobuf", "protobuf_val"} {
+		for _, opt := range strings.Split(sf.Tag.Get(key), ",") {
+			if strings.HasPrefix(opt, "enum=") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isNumber(s string) bool {
+	_, err := strconv.ParseInt(s, 10, 32)
+	return err == nil
+}
+
+// enumNames cache the numbers of the enum types by name.
+var enumNames sync.Map // reflect.Type → map[string]int32
+
+// enumNumber return the number of the enum value name of type t. The
+// names are found with the String method of the generated code, from 0
+// until 256 numbers in a row have no name.
+func enumNumber(t reflect.Type, name string) (int32, bool) {
+	names, ok := enumNames.Load(t)
+	if !ok {
+		m := map[string]int32{}
+		if _, stringer := reflect.Zero(t).Interface().(fmt.Stringer); stringer {
+			for n, misses := int32(0), 0; misses < 256; n++ {
+				s := reflect.ValueOf(n).Convert(t).Interface().(fmt.Stringer).String()
+				if isNumber(s) {
+					misses++
+					continue
+				}
+				m[s], misses = n, 0
+			}
+		}
+		names, _ = enumNames.LoadOrStore(t, m)
+	}
+	n, ok := names.(map[string]int32)[name]
+	return n, ok
+}
+
+// oneofField is the set member of a oneof.
+type oneofField struct {
+	name  string
+	value reflect.Value
+	enum  bool
+}
+
+// oneofMember return the member set in the oneof field f, an interface
+// holding a pointer to a wrapper struct with the member as only field.
+func oneofMember(f reflect.Value) (oneofField, bool) {
+	if f.IsNil() {
+		return oneofField{}, false
+	}
+	w := f.Elem()
+	if w.Kind() == reflect.Pointer {
+		if w.IsNil() {
+			return oneofField{}, false
+		}
+		w = w.Elem()
+	}
+	if w.Kind() != reflect.Struct || w.NumField() != 1 {
+		return oneofField{}, false
+	}
+	sf := w.Type().Field(0)
+	name, ok := jsonName(sf)
+	return oneofField{name, w.Field(0), isEnum(sf)}, ok
+}
+
+// decodeOneof set the oneof field f of msg from the member found in
+// fields. The wrapper types are listed by the XXX_OneofWrappers method of
+// the generated code; without it the oneof is not decoded.
+func decodeOneof(fields map[string]json.RawMessage, msg, f reflect.Value, path string) error {
+	if !msg.CanAddr() {
+		return nil
+	}
+	lister, ok := msg.Addr().Interface().(interface{ XXX_OneofWrappers() []any })
+	if !ok {
+		return nil
+	}
+	for _, wrapper := range lister.XXX_OneofWrappers() {
+		wt := reflect.TypeOf(wrapper)
+		if !wt.AssignableTo(f.Type()) || wt.Kind() != reflect.Pointer || wt.Elem().NumField() != 1 {
+			continue
+		}
+		sf := wt.Elem().Field(0)
+		name, ok := jsonName(sf)
+		if !ok {
+			continue
+		}
+		raw, found := fields[name]
+		if !found {
+			raw, found = fields[protoName(sf)]
+		}
+		if !found {
+			continue
+		}
+		w := reflect.New(wt.Elem())
+		if err := decode(raw, w.Elem().Field(0), path+name+".", isEnum(sf)); err != nil {
+			return err
+		}
+		f.Set(w)
+		return nil
+	}
+	return nil
+}
+
+// jsonName return the proto3 JSON name of a message field, false for
+// fields not part of the message.
+func jsonName(sf reflect.StructField) (string, bool) {
+	if !sf.IsExported() || strings.HasPrefix(sf.Name, "XXX_") {
+		return "", false
+	}
+	for _, opt := range strings.Split(sf.Tag.Get("protobuf"), ",") {
+		if name, ok := strings.CutPrefix(opt, "json="); ok {
+			return name, true
+		}
+	}
+	tag := sf.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return lowerCamel(name), true
+	}
+	return lowerInitial(sf.Name), true
+}
+
+// protoName return the original field name, the name= of the protobuf tag.
+func protoName(sf reflect.StructField) string {
+	for _, opt := range strings.Split(sf.Tag.Get("protobuf"), ",") {
+		if name, ok := strings.CutPrefix(opt, "name="); ok {
+			return name
+		}
+	}
+	if name, _, _ := strings.Cut(sf.Tag.Get("json"), ","); name != "" {
+		return name
+	}
+	return sf.Name
+}
+
+// lowerInitial lower the leading capitals of a Go name: ID give id,
+// UserID give userID and HTTPServer give httpServer.
+func lowerInitial(s string) string {
+	runes := []rune(s)
+	for i := range runes {
+		if !unicode.IsUpper(runes[i]) || i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
+
+// lowerCamel convert user_id and UserId to userId.
+func lowerCamel(s string) string {
+	var b strings.Builder
+	upper := false
+	for i, r := range s {
+		switch {
+		case r == '_':
+			upper = i > 0
+			continue
+		case upper:
+			r = unicode.ToUpper(r)
+		case b.Len() == 0:
+			r = unicode.ToLower(r)
+		}
+		upper = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
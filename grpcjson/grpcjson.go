@@ -0,0 +1,163 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+// Package grpcjson serve APIs defined with protobuf over glaze, with the
+// JSON conventions of gRPC transcoding: lowerCamelCase field names, 64 bit
+// integers as strings, zero values omitted, field masks and google.rpc.Status
+// errors with the gRPC code to HTTP status mapping.
+//
+// It has no protobuf dependency: messages are Go structs, field names are
+// read from the `protobuf:"...,json=name"` tag of generated code, then from
+// the `json` tag. Enum fields (tagged "enum=") are written by name with
+// their String method, and the set member of a oneof is inlined in the
+// message. Well known types (Timestamp, Any, wrappers...) are encoded as
+// plain messages; use protojson for full conformance.
+//
+// Usage:
+//
+//	grpcjson.Register(r) // map *grpcjson.Status errors
+//	r.Use(glaze.ErrorHandler())
+//
+//	r.Get("/v1/users/:id", func(c *glaze.Context) {
+//	    user, err := svc.GetUser(c.Request.Context(), &pb.GetUserRequest{Id: c.Param("id")})
+//	    if err != nil {
+//	        c.Error(err)
+//	        return
+//	    }
+//	    grpcjson.Render(c, http.StatusOK, user)
+//	})
+package grpcjson
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/nrhox/glaze"
+)
+
+// Code is a gRPC status code.
+type Code int
+
+const (
+	OK Code = iota
+	Canceled
+	Unknown
+	InvalidArgument
+	DeadlineExceeded
+	NotFound
+	AlreadyExists
+	PermissionDenied
+	ResourceExhausted
+	FailedPrecondition
+	Aborted
+	OutOfRange
+	Unimplemented
+	Internal
+	Unavailable
+	DataLoss
+	Unauthenticated
+)
+
+var codeNames = [...]string{
+	"OK", "CANCELLED", "UNKNOWN", "INVALID_ARGUMENT", "DEADLINE_EXCEEDED",
+	"NOT_FOUND", "ALREADY_EXISTS", "PERMISSION_DENIED", "RESOURCE_EXHAUSTED",
+	"FAILED_PRECONDITION", "ABORTED", "OUT_OF_RANGE", "UNIMPLEMENTED",
+	"INTERNAL", "UNAVAILABLE", "DATA_LOSS", "UNAUTHENTICATED",
+}
+
+// String return the canonical name of the code, "NOT_FOUND".
+func (c Code) String() string {
+	if c >= 0 && int(c) < len(codeNames) {
+		return codeNames[c]
+	}
+	return fmt.Sprintf("CODE(%d)", int(c))
+}
+
+// HTTPStatus return the HTTP status of a gRPC code, as google.rpc.Code
+// document it. Unknown codes are 500.
+func HTTPStatus(c Code) int {
+	switch c {
+	case OK:
+		return http.StatusOK
+	case Canceled:
+		return 499 // client closed request
+	case InvalidArgument, FailedPrecondition, OutOfRange:
+		return http.StatusBadRequest
+	case DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case NotFound:
+		return http.StatusNotFound
+	case AlreadyExists, Aborted:
+		return http.StatusConflict
+	case PermissionDenied:
+		return http.StatusForbidden
+	case ResourceExhausted:
+		return http.StatusTooManyRequests
+	case Unimplemented:
+		return http.StatusNotImplemented
+	case Unavailable:
+		return http.StatusServiceUnavailable
+	case Unauthenticated:
+		return http.StatusUnauthorized
+	}
+	return http.StatusInternalServerError
+}
+
+// Status is an error with a gRPC code, rendered as google.rpc.Status.
+type Status struct {
+	Code    Code
+	Message string
+	Details []any
+}
+
+// Errorf create a Status error with a formatted message.
+func Errorf(code Code, format string, args ...any) *Status {
+	return &Status{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// Error return "NOT_FOUND: user 42 not found".
+func (s *Status) Error() string {
+	return s.Code.String() + ": " + s.Message
+}
+
+// Is match a Status target of the same code and an empty message,
+// so errors.Is(err, &Status{Code: NotFound}) test the code only.
+func (s *Status) Is(target error) bool {
+	t, ok := target.(*Status)
+	return ok && t.Code == s.Code && t.Message == ""
+}
+
+// body return the google.rpc.Status JSON body.
+func (s *Status) body() any {
+	details := s.Details
+	if details == nil {
+		details = []any{}
+	}
+	return glaze.M{"code": int(s.Code), "message": s.Message, "details": details}
+}
+
+// Register map the *Status errors of every code to their HTTP status
+// and google.rpc.Status body, see glaze.Engine.MapError.
+func Register(e *glaze.Engine) {
+	for code := Canceled; code <= Unauthenticated; code++ {
+		e.MapError(&Status{Code: code}, HTTPStatus(code), func(err error) any {
+			var s *Status
+			if !errors.As(err, &s) {
+				return glaze.M{"code": int(code), "message": code.String(), "details": []any{}}
+			}
+			return s.body()
+		})
+	}
+}
+
+// Error write the google.rpc.Status response of err and abort the chain.
+// Errors other than *Status are UNKNOWN, without leaking their message.
+func Error(c *glaze.Context, err error) {
+	var s *Status
+	if !errors.As(err, &s) {
+		s = &Status{Code: Unknown, Message: http.StatusText(http.StatusInternalServerError)}
+	}
+	c.Abort()
+	c.JSON(HTTPStatus(s.Code), s.body())
+}
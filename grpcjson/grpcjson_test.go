@@ -0,0 +1,164 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package grpcjson
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/nrhox/glaze"
+	"github.com/stretchr/testify/assert"
+)
+
+// shaped like protoc-gen-go output
+type profile struct {
+	state     int
+	AvatarUrl string `protobuf:"bytes,1,opt,name=avatar_url,json=avatarUrl,proto3" json:"avatar_url,omitempty"`
+	Bio       string `protobuf:"bytes,2,opt,name=bio,proto3" json:"bio,omitempty"`
+}
+
+type user struct {
+	Id          int64    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	DisplayName string   `protobuf:"bytes,2,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	Age         int32    `protobuf:"varint,3,opt,name=age,proto3" json:"age,omitempty"`
+	Tags        []string `protobuf:"bytes,4,rep,name=tags,proto3" json:"tags,omitempty"`
+	Profile     *profile `protobuf:"bytes,5,opt,name=profile,proto3" json:"profile,omitempty"`
+}
+
+func TestMarshalUnmarshal(t *testing.T) {
+	u := &user{Id: 9007199254740993, DisplayName: "Jalu", Profile: &profile{AvatarUrl: "a.png"}}
+	data, err := Marshal(u)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"id":"9007199254740993","displayName":"Jalu","profile":{"avatarUrl":"a.png"}}`, string(data))
+
+	var got user
+	assert.NoError(t, Unmarshal([]byte(`{"id":"12","display_name":"J","age":3,"tags":["x"],"profile":{"bio":"hi"}}`), &got))
+	assert.Equal(t, user{Id: 12, DisplayName: "J", Age: 3, Tags: []string{"x"}, Profile: &profile{Bio: "hi"}}, got)
+
+	err = Unmarshal([]byte(`{"age":"old"}`), &got)
+	assert.EqualError(t, err, "invalid value for field age: unexpected string")
+
+	assert.Equal(t, "id", lowerInitial("ID"))
+	assert.Equal(t, "httpServer", lowerInitial("HTTPServer"))
+	assert.Equal(t, "avatarUrl", lowerCamel("avatar_url"))
+}
+
+type status int32
+
+func (x status) String() string {
+	switch x {
+	case 0:
+		return "STATUS_UNSPECIFIED"
+	case 1:
+		return "ACTIVE"
+	}
+	return strconv.Itoa(int(x))
+}
+
+type account struct {
+	Status status   `protobuf:"varint,1,opt,name=status,proto3,enum=test.Status" json:"status,omitempty"`
+	Roles  []status `protobuf:"varint,2,rep,packed,name=roles,proto3,enum=test.Status" json:"roles,omitempty"`
+	Level  int32    `protobuf:"varint,3,opt,name=level,proto3" json:"level,omitempty"`
+	// Types that are assignable to Contact:
+	//
+	//	*account_Email
+	//	*account_PhoneNumber
+	Contact isAccount_Contact `protobuf_oneof:"contact"`
+}
+
+type isAccount_Contact interface{ isAccount_Contact() }
+
+type account_Email struct {
+	Email string `protobuf:"bytes,4,opt,name=email,proto3,oneof"`
+}
+
+type account_PhoneNumber struct {
+	PhoneNumber string `protobuf:"bytes,5,opt,name=phone_number,json=phoneNumber,proto3,oneof"`
+}
+
+func (*account_Email) isAccount_Contact()       {}
+func (*account_PhoneNumber) isAccount_Contact() {}
+
+func (*account) XXX_OneofWrappers() []any {
+	return []any{(*account_Email)(nil), (*account_PhoneNumber)(nil)}
+}
+
+func TestEnumsAndOneofs(t *testing.T) {
+	data, err := Marshal(&account{Status: 1, Roles: []status{0, 1, 7}, Level: 2, Contact: &account_Email{}})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"status":"ACTIVE","roles":["STATUS_UNSPECIFIED","ACTIVE",7],"level":2,"email":""}`, string(data))
+
+	data, err = Marshal(&account{Contact: &account_PhoneNumber{PhoneNumber: "555"}})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"phoneNumber":"555"}`, string(data))
+
+	var got account
+	assert.NoError(t, Unmarshal([]byte(`{"status":"ACTIVE","roles":["ACTIVE",0],"phone_number":"555"}`), &got))
+	assert.Equal(t, account{Status: 1, Roles: []status{1, 0}, Contact: &account_PhoneNumber{PhoneNumber: "555"}}, got)
+
+	got = account{}
+	assert.NoError(t, Unmarshal([]byte(`{"status":1,"email":"a@example.com"}`), &got))
+	assert.Equal(t, account{Status: 1, Contact: &account_Email{Email: "a@example.com"}}, got)
+
+	err = Unmarshal([]byte(`{"status":"NOPE"}`), &got)
+	assert.EqualError(t, err, `invalid value for field status: unknown enum value "NOPE"`)
+}
+
+func TestRenderFieldMask(t *testing.T) {
+	r := glaze.New()
+	r.Get("/user", func(c *glaze.Context) {
+		Render(c, http.StatusOK, &user{Id: 1, DisplayName: "Jalu", Tags: []string{"a"},
+			Profile: &profile{AvatarUrl: "a.png", Bio: "hi"}})
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/user?fields=display_name,profile.bio", nil))
+	assert.JSONEq(t, `{"displayName":"Jalu","profile":{"bio":"hi"}}`, w.Body.String())
+
+	masked := ApplyFieldMask([]any{map[string]any{"a": 1, "b": 2}}, []string{"b"})
+	assert.Equal(t, []any{map[string]any{"b": 2}}, masked)
+}
+
+func TestStatusErrors(t *testing.T) {
+	r := glaze.New()
+	Register(r)
+	r.Use(glaze.ErrorHandler())
+	r.Get("/missing", func(c *glaze.Context) { c.Error(Errorf(NotFound, "user %d not found", 4)) })
+	r.Get("/wrapped", func(c *glaze.Context) {
+		c.Error(errors.Join(errors.New("ctx"), Errorf(ResourceExhausted, "quota")))
+	})
+	r.Get("/direct", func(c *glaze.Context) { Error(c, errors.New("secret")) })
+	r.Post("/bind", func(c *glaze.Context) {
+		var u user
+		if err := Bind(c, &u); err != nil {
+			Error(c, err)
+		}
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/missing", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.JSONEq(t, `{"code":5,"message":"user 4 not found","details":[]}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/wrapped", nil))
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/direct", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "secret")
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("POST", "/bind", strings.NewReader(`{"id":true}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":3`)
+
+	assert.Equal(t, "NOT_FOUND", NotFound.String())
+	assert.Equal(t, 499, HTTPStatus(Canceled))
+}
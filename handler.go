@@ -0,0 +1,135 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+)
+
+// Handler adapt a typed function to a HandlerFunc. The request is bound
+// and validated into Req with Context.Bind, fn is called and its Resp is
+// rendered as JSON with status 200, or the value of a StatusCode() int
+// method of Resp. Nothing is rendered if fn already wrote the response.
+//
+// Errors are rendered with the engine mappings (see MapError) or as
+// HTTPError, otherwise validation errors are 422 with the field messages,
+// other bind errors 400 (with the field of a BindError) and fn errors 500.
+// See WithProblemDetails for RFC 7807 responses.
+//
+// Usage:
+//
+//	type CreateUser struct {
+//	    Name string `json:"name" validate:"required"`
+//	}
+//
+//	r.Post("/users", glaze.Handler(func(c *glaze.Context, in CreateUser) (User, error) {
+//	    return users.Create(c.Request.Context(), in.Name)
+//	}))
+func Handler[Req, Resp any](fn func(c *Context, req Req) (Resp, error)) HandlerFunc {
+	return func(c *Context) {
+		req, err := bindRequest[Req](c)
+		if err != nil {
+			c.renderBindError(err)
+			return
+		}
+
+		resp, err := fn(c, req)
+		if err != nil {
+			c.renderError(err)
+			return
+		}
+		c.renderResult(resp)
+	}
+}
+
+// bindRequest allocate Req (or the struct Req point to) and bind it,
+// non struct types are left to their zero value.
+func bindRequest[Req any](c *Context) (Req, error) {
+	var req Req
+	t := reflect.TypeFor[Req]()
+	switch {
+	case t.Kind() == reflect.Struct:
+		return req, c.Bind(&req)
+	case t.Kind() == reflect.Pointer && t.Elem().Kind() == reflect.Struct:
+		ptr := reflect.New(t.Elem())
+		req = ptr.Interface().(Req)
+		return req, c.Bind(req)
+	}
+	return req, nil
+}
+
+// renderResult write resp as JSON, unless the response is already written.
+func (c *Context) renderResult(resp any) {
+	if c.Writer.Written() {
+		return
+	}
+	status := http.StatusOK
+	if s, ok := resp.(interface{ StatusCode() int }); ok {
+		status = s.StatusCode()
+	}
+	c.JSON(status, resp)
+}
+
+// renderBindError write the response of a Bind error.
+func (c *Context) renderBindError(err error) {
+	if status, body, ok := c.engine.lookupError(err); ok {
+		c.renderErrorBody(status, body)
+		return
+	}
+	var verrs ValidationErrors
+	if errors.As(err, &verrs) {
+		msg := c.translateError("validation.failed", "validation failed")
+		if c.engine.problems {
+			c.Problem(http.StatusUnprocessableEntity, "", "", msg, M{"fields": c.FieldMessages(verrs)})
+			return
+		}
+		c.JSON(http.StatusUnprocessableEntity, M{"error": msg, "fields": c.FieldMessages(verrs)})
+		return
+	}
+	var berr *BindError
+	if errors.As(err, &berr) && berr.Field != "" {
+		fields := map[string]string{berr.Field: berr.Reason}
+		if c.engine.problems {
+			c.Problem(http.StatusBadRequest, "", "", err.Error(), M{"fields": fields})
+			return
+		}
+		c.JSON(http.StatusBadRequest, M{"error": err.Error(), "fields": fields})
+		return
+	}
+	c.renderErrorBody(http.StatusBadRequest, M{"error": err.Error()})
+}
+
+// renderError write the response of a handler error, server errors are reported.
+func (c *Context) renderError(err error) {
+	status, body := c.engine.resolveError(err)
+	if herr, ok := body.(*HTTPError); ok && herr.Code != "" {
+		if msg := c.translateError("errors."+herr.Code, herr.Message); msg != herr.Message {
+			cp := *herr
+			cp.Message = msg
+			body = &cp
+		}
+	}
+	if status >= http.StatusInternalServerError {
+		c.engine.logf(LogLevelError, "[ERROR] %s %s: %v (%s)\n", c.Request.Method, c.Request.URL.Path, err, requestFields(c))
+		if c.engine.reporter != nil {
+			c.engine.reporter.ReportError(c, err)
+		}
+	}
+	c.renderErrorBody(status, body)
+}
+
+// renderErrorBody write an error body as JSON, or as a problem document
+// with WithProblemDetails.
+func (c *Context) renderErrorBody(status int, body any) {
+	if c.engine.problems {
+		if p := problemOf(status, body); p != nil {
+			p.Instance = c.Request.URL.Path
+			c.renderProblem(p)
+			return
+		}
+	}
+	c.JSON(status, body)
+}
@@ -0,0 +1,101 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"strconv"
+	"strings"
+)
+
+// GetHeaders return every value of the request header key, nil when missing.
+func (c *Context) GetHeaders(key string) []string {
+	return c.Request.Header.Values(key)
+}
+
+// AddHeader add a value to the response header key, keeping the existing
+// ones (Vary, Link, Set-Cookie).
+func (c *Context) AddHeader(key, value string) {
+	c.Writer.Header().Add(key, value)
+}
+
+// HeaderValues return the comma separated items of every value of the
+// request header key, see SplitHeader.
+//
+// Usage:
+//
+//	for _, el := range c.HeaderValues("Cache-Control") {
+//	    if el == "no-transform" { ... }
+//	}
+func (c *Context) HeaderValues(key string) []string {
+	var out []string
+	for _, v := range c.Request.Header.Values(key) {
+		out = append(out, SplitHeader(v)...)
+	}
+	return out
+}
+
+// SplitHeader split a comma separated header value into its trimmed, non
+// empty items. Commas inside double quotes do not split.
+func SplitHeader(value string) []string {
+	var out []string
+	for _, item := range splitQuoted(value, ',') {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// HeaderElement is an item of a header value with its parameters,
+// "text/html;level=1;q=0.8" or "for=192.0.2.60;proto=https".
+type HeaderElement struct {
+	Value  string            // "text/html", empty when the item is only parameters
+	Params map[string]string // lower case names, unquoted values
+}
+
+// Q return the "q" parameter, 1 when missing or invalid.
+func (el HeaderElement) Q() float64 {
+	if v, ok := el.Params["q"]; ok {
+		if q, err := strconv.ParseFloat(v, 64); err == nil {
+			return q
+		}
+	}
+	return 1
+}
+
+// ParseHeader parse a comma separated header value into its elements, for
+// Accept-Encoding, Accept-Language, Forwarded and the like. The elements
+// keep the header order.
+//
+// Usage:
+//
+//	for _, el := range glaze.ParseHeader(c.GetHeader("Accept-Encoding")) {
+//	    if el.Value == "br" && el.Q() > 0 { ... }
+//	}
+func ParseHeader(value string) []HeaderElement {
+	var out []HeaderElement
+	for _, item := range SplitHeader(value) {
+		var el HeaderElement
+		for i, part := range splitQuoted(item, ';') {
+			part = strings.TrimSpace(part)
+			k, v, ok := strings.Cut(part, "=")
+			if !ok {
+				if i == 0 {
+					el.Value = part
+				}
+				continue
+			}
+			if el.Params == nil {
+				el.Params = make(map[string]string)
+			}
+			v = strings.TrimSpace(v)
+			if unquoted, err := strconv.Unquote(v); err == nil && strings.HasPrefix(v, `"`) {
+				v = unquoted
+			}
+			el.Params[strings.ToLower(strings.TrimSpace(k))] = v
+		}
+		out = append(out, el)
+	}
+	return out
+}
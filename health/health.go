@@ -0,0 +1,199 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+// Package health provides liveness and readiness endpoints for glaze.
+//
+// Usage:
+//
+//	h := health.New()
+//	h.AddCheck("db", db.PingContext, health.CheckConfig{Timeout: time.Second})
+//	h.Mount(r, "/health") // GET /health/live and GET /health/ready
+package health
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/nrhox/glaze"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+
+	defaultTimeout = 5 * time.Second
+)
+
+// CheckFunc test a dependency, returning nil when it is healthy.
+// It must respect ctx cancellation.
+type CheckFunc func(ctx context.Context) error
+
+// CheckConfig holds the configuration of a single check.
+type CheckConfig struct {
+	// Timeout of one check run, default 5s.
+	Timeout time.Duration
+
+	// CacheTTL reuse the last result during this duration,
+	// 0 means the check run on every request.
+	CacheTTL time.Duration
+
+	// Liveness include the check in the liveness endpoint too.
+	// Keep this for checks that only fail when the process must restart.
+	Liveness bool
+}
+
+// CheckResult is the result of a single check.
+type CheckResult struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Duration string `json:"duration"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Report is the JSON body of the health endpoints.
+type Report struct {
+	Status string        `json:"status"`
+	Checks []CheckResult `json:"checks"`
+}
+
+// check is a registered named checker with its cached result.
+type check struct {
+	name string
+	fn   CheckFunc
+	cfg  CheckConfig
+
+	mu       sync.Mutex
+	last     CheckResult
+	lastTime time.Time
+}
+
+// Health hold the registered checks.
+type Health struct {
+	mu     sync.RWMutex
+	checks []*check
+}
+
+// New create an empty Health.
+func New() *Health {
+	return &Health{}
+}
+
+// AddCheck register a named check. Names must be unique.
+func (h *Health) AddCheck(name string, fn CheckFunc, cfg ...CheckConfig) {
+	c := &check{name: name, fn: fn}
+	if len(cfg) > 0 {
+		c.cfg = cfg[0]
+	}
+	if c.cfg.Timeout <= 0 {
+		c.cfg.Timeout = defaultTimeout
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, existing := range h.checks {
+		if existing.name == name {
+			panic("health: duplicate check '" + name + "'")
+		}
+	}
+	h.checks = append(h.checks, c)
+}
+
+// Mount registers GET prefix/live and GET prefix/ready.
+func (h *Health) Mount(r glaze.Routes, prefix string) {
+	r.Get(prefix+"/live", h.Liveness())
+	r.Get(prefix+"/ready", h.Readiness())
+}
+
+// Liveness returns a handler reporting whether the process is alive.
+// Only checks registered with CheckConfig.Liveness are run.
+func (h *Health) Liveness() glaze.HandlerFunc {
+	return func(c *glaze.Context) {
+		h.respond(c, h.Run(c.Request.Context(), true))
+	}
+}
+
+// Readiness returns a handler reporting whether the service can accept traffic.
+// Every registered check is run.
+func (h *Health) Readiness() glaze.HandlerFunc {
+	return func(c *glaze.Context) {
+		h.respond(c, h.Run(c.Request.Context(), false))
+	}
+}
+
+// Run execute the checks concurrently and return the report.
+// When liveness is true only liveness checks are run.
+func (h *Health) Run(ctx context.Context, liveness bool) Report {
+	h.mu.RLock()
+	checks := make([]*check, 0, len(h.checks))
+	for _, c := range h.checks {
+		if !liveness || c.cfg.Liveness {
+			checks = append(checks, c)
+		}
+	}
+	h.mu.RUnlock()
+
+	report := Report{Status: StatusUp, Checks: make([]CheckResult, len(checks))}
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report.Checks[i] = c.run(ctx)
+		}()
+	}
+	wg.Wait()
+
+	for _, res := range report.Checks {
+		if res.Status != StatusUp {
+			report.Status = StatusDown
+			break
+		}
+	}
+	return report
+}
+
+// respond write the report, 503 if one check is down.
+func (h *Health) respond(c *glaze.Context, report Report) {
+	code := http.StatusOK
+	if report.Status != StatusUp {
+		code = http.StatusServiceUnavailable
+	}
+	c.Writer.Header().Set("Cache-Control", "no-store")
+	c.JSON(code, report)
+}
+
+// run execute the check, or return the cached result if still fresh.
+func (c *check) run(ctx context.Context) CheckResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cfg.CacheTTL > 0 && !c.lastTime.IsZero() && time.Since(c.lastTime) < c.cfg.CacheTTL {
+		return c.last
+	}
+
+	// the result is shared, a probe going away must not cache its cancel
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.cfg.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- c.fn(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	res := CheckResult{Name: c.name, Status: StatusUp, Duration: time.Since(start).String()}
+	if err != nil {
+		res.Status = StatusDown
+		res.Error = err.Error()
+	}
+	c.last, c.lastTime = res, time.Now()
+	return res
+}
@@ -0,0 +1,68 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nrhox/glaze"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadinessAndLiveness(t *testing.T) {
+	calls := 0
+	h := New()
+	h.AddCheck("db", func(ctx context.Context) error {
+		calls++
+		return errors.New("connection refused")
+	}, CheckConfig{CacheTTL: time.Minute})
+	h.AddCheck("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, CheckConfig{Timeout: 10 * time.Millisecond})
+
+	r := glaze.New()
+	h.Mount(r, "/health")
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/health/ready", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	var report Report
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, StatusDown, report.Status)
+	assert.Equal(t, "connection refused", report.Checks[0].Error)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks[1].Error)
+
+	// cached result, check not called again
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/health/ready", nil))
+	assert.Equal(t, 1, calls)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/health/live", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestCanceledProbeNotCached(t *testing.T) {
+	h := New()
+	h.AddCheck("db", func(ctx context.Context) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(10 * time.Millisecond):
+			return nil
+		}
+	}, CheckConfig{CacheTTL: time.Minute})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, StatusUp, h.Run(ctx, false).Status, "probe gone away")
+	assert.Equal(t, StatusUp, h.Run(context.Background(), false).Status)
+}
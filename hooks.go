@@ -0,0 +1,157 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// StartHook is run by the Run helpers once the listener is bound,
+// before the first request is served.
+type StartHook func(ctx context.Context) error
+
+// OnStart register hooks run after the listener bind and before serving
+// (warm caches, announce readiness). Hooks run in registration order,
+// the first error close the listener and is returned by the Run helper.
+//
+// Usage:
+//
+//	e.OnStart(func(ctx context.Context) error {
+//	    return cache.Warm(ctx)
+//	})
+func (e *Engine) OnStart(hooks ...StartHook) *Engine {
+	e.startHooks = append(e.startHooks, hooks...)
+	return e
+}
+
+// start run the start hooks then serve srv on listener.
+// The server is tracked while serving, see Engine.Shutdown.
+func (e *Engine) start(srv *http.Server, listener net.Listener, serve func(net.Listener) error) error {
+	ctx := context.Background()
+	for _, hook := range e.startHooks {
+		if err := hook(ctx); err != nil {
+			listener.Close()
+			return fmt.Errorf("glaze: start hook: %w", err)
+		}
+	}
+
+	e.serversMu.Lock()
+	if e.servers == nil {
+		e.servers = make(map[*http.Server]struct{})
+	}
+	if len(e.servers) == 0 {
+		e.resetDrain() // started again after a shutdown
+	}
+	e.servers[srv] = struct{}{}
+	if e.addr == nil {
+		e.addr = listener.Addr()
+	}
+	e.serversMu.Unlock()
+	e.readyOnce.Do(func() { close(e.ready) })
+
+	defer func() {
+		e.serversMu.Lock()
+		delete(e.servers, srv)
+		e.serversMu.Unlock()
+	}()
+	return serve(listener)
+}
+
+// Ready return a channel closed once a Run helper has bound its listener
+// and run the start hooks, connections are accepted from then on. Tests
+// and orchestration wait on it instead of sleeping.
+//
+// Usage:
+//
+//	go e.ListenAndGraceful("127.0.0.1:0")
+//	<-e.Ready()
+//	resp, err := http.Get("http://" + e.Addr().String() + "/health")
+func (e *Engine) Ready() <-chan struct{} {
+	return e.ready
+}
+
+// Addr return the address of the first listener of the Run helpers, with
+// the real port when listening on ":0". It is nil until Ready is closed.
+func (e *Engine) Addr() net.Addr {
+	e.serversMu.Lock()
+	defer e.serversMu.Unlock()
+	return e.addr
+}
+
+// OnRequest register hooks run for every request before any middleware,
+// including the PhasePreRouting ones and requests without route.
+// It is the integration point of APM agents and request tracers.
+//
+// Usage:
+//
+//	e.OnRequest(func(c *glaze.Context) {
+//	    c.Set("apm.start", time.Now())
+//	})
+func (e *Engine) OnRequest(hooks ...HandlerFunc) *Engine {
+	e.onRequest = append(e.onRequest, hooks...)
+	return e
+}
+
+// ContextInjector derive the context of a request from ctx, it must
+// return ctx or a child of it.
+type ContextInjector func(ctx context.Context, c *Context) context.Context
+
+// InjectContext register injectors run for every request before the
+// OnRequest hooks, their context replace the request context so the values
+// are seen by every middleware, handler and library using
+// c.Request.Context(), including the 404 and mounted handlers.
+//
+// Usage:
+//
+//	e.InjectContext(func(ctx context.Context, c *glaze.Context) context.Context {
+//	    return flags.WithClient(ctx, flagClient.ForUser(c.GetHeader("X-User")))
+//	})
+func (e *Engine) InjectContext(injectors ...ContextInjector) *Engine {
+	e.injectors = append(e.injectors, injectors...)
+	return e
+}
+
+// ContextValue add the same value to the context of every request,
+// like the build version. It is a shortcut of InjectContext.
+//
+// Usage:
+//
+//	e.ContextValue(versionKey{}, buildVersion)
+//
+//	version := c.Request.Context().Value(versionKey{}).(string)
+func (e *Engine) ContextValue(key, value any) *Engine {
+	return e.InjectContext(func(ctx context.Context, _ *Context) context.Context {
+		return context.WithValue(ctx, key, value)
+	})
+}
+
+// injectContext replace the request context with the injectors one.
+func (e *Engine) injectContext(c *Context) {
+	parent := c.Request.Context()
+	ctx := parent
+	for _, inject := range e.injectors {
+		ctx = inject(ctx, c)
+	}
+	if ctx != parent {
+		c.Request = c.Request.WithContext(ctx)
+	}
+}
+
+// OnResponse register hooks run for every request once the handlers are
+// done, after the PhasePostResponse middleware. They also run for 404 and
+// while a panic not handled by Recovery is unwinding.
+func (e *Engine) OnResponse(hooks ...HandlerFunc) *Engine {
+	e.onResponse = append(e.onResponse, hooks...)
+	return e
+}
+
+// runResponseHooks is deferred by ServeHTTP so hooks run even on panic.
+func (e *Engine) runResponseHooks(c *Context) {
+	for _, h := range e.onResponse {
+		h(c)
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"bytes"
+	"context"
+	"html/template"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+)
+
+var htmlContentType = []string{"text/html; charset=utf-8"}
+
+// HTMLRenderer render the page name with data for Context.HTML. It is the
+// extension point of template engines (templ, quicktemplate, pongo2, jet),
+// handlers calling c.HTML do not change when the engine change.
+//
+// Usage, a templ adapter where data is the component:
+//
+//	type templRenderer struct{}
+//
+//	func (templRenderer) Render(ctx context.Context, w io.Writer, _ string, data any) error {
+//	    return data.(templ.Component).Render(ctx, w)
+//	}
+//
+//	e := glaze.New(glaze.WithHTMLRenderer(templRenderer{}))
+//	c.HTML(http.StatusOK, "", views.Home(user))
+type HTMLRenderer interface {
+	Render(ctx context.Context, w io.Writer, name string, data any) error
+}
+
+// WithHTMLRenderer set the renderer of Context.HTML.
+func WithHTMLRenderer(r HTMLRenderer) ConfigsFunc {
+	return func(e *Engine) {
+		e.html = r
+	}
+}
+
+// TemplateRenderer is the HTMLRenderer of html/template, name is the name
+// of the template to execute.
+type TemplateRenderer struct {
+	Template *template.Template
+}
+
+// Render execute the template name.
+func (r TemplateRenderer) Render(_ context.Context, w io.Writer, name string, data any) error {
+	return r.Template.ExecuteTemplate(w, name, data)
+}
+
+// LoadHTMLGlob parse the templates matching pattern with html/template
+// and use them for Context.HTML. It panic if a template is invalid.
+//
+// Usage:
+//
+//	e.LoadHTMLGlob("templates/*.html")
+//	c.HTML(http.StatusOK, "index.html", glaze.M{"title": "Home"})
+func (e *Engine) LoadHTMLGlob(pattern string) *Engine {
+	dir, rest := splitGlob(pattern)
+	return e.loadTemplates("templates", os.DirFS(dir), rest)
+}
+
+// splitGlob split pattern after its longest directory prefix without glob
+// characters, "views/*/*.html" is "views" and "*/*.html". The rest is
+// slash separated, for fs.Glob.
+func splitGlob(pattern string) (dir, rest string) {
+	elems := strings.Split(filepath.ToSlash(pattern), "/")
+	i := 0
+	for i < len(elems)-1 && !strings.ContainsAny(elems[i], "*?[\\") {
+		i++
+	}
+	dir = strings.Join(elems[:i], "/")
+	switch {
+	case dir == "" && i > 0: // absolute pattern
+		dir = "/"
+	case dir == "":
+		dir = "."
+	}
+	return filepath.FromSlash(dir), strings.Join(elems[i:], "/")
+}
+
+// LoadHTMLFS is LoadHTMLGlob for templates of fsys, like an embed.FS.
+func (e *Engine) LoadHTMLFS(fsys fs.FS, patterns ...string) *Engine {
+	return e.loadTemplates("templates", fsys, patterns...)
+}
+
+// loadTemplates parse the templates and register their reload for RunDev.
+func (e *Engine) loadTemplates(name string, fsys fs.FS, patterns ...string) *Engine {
+	set := &templateSet{}
+	parse := func() error {
+		tmpl, err := template.New("").Funcs(e.funcMap()).ParseFS(fsys, patterns...)
+		if err == nil {
+			set.tmpl.Store(tmpl)
+		}
+		return err
+	}
+	if err := parse(); err != nil {
+		panic(err)
+	}
+	e.html = set
+	e.htmlWatch = &DevWatch{Name: name, FS: fsys, Patterns: patterns, Reload: parse}
+	return e
+}
+
+// templateSet is the renderer of the loaded templates, they are swapped
+// atomically when RunDev reload them.
+type templateSet struct {
+	tmpl atomic.Pointer[template.Template]
+}
+
+func (s *templateSet) Render(_ context.Context, w io.Writer, name string, data any) error {
+	return s.tmpl.Load().ExecuteTemplate(w, name, data)
+}
+
+// SetFuncMap set the functions of the templates loaded after it with
+// LoadHTMLGlob and LoadHTMLFS.
+func (e *Engine) SetFuncMap(funcs template.FuncMap) *Engine {
+	e.templateFuncs = funcs
+	return e
+}
+
+// SetViewData add a value to the data of every Context.HTML call of the
+// request, for middleware providing the current user, the CSRF token or the
+// flash messages to all pages.
+//
+// Usage:
+//
+//	r.Use(func(c *glaze.Context) {
+//	    c.SetViewData("user", currentUser(c))
+//	    c.Next()
+//	})
+func (c *Context) SetViewData(key string, value any) {
+	if c.viewData == nil {
+		c.viewData = make(M)
+	}
+	c.viewData[key] = value
+}
+
+// ViewData return the values added with SetViewData, for renderers
+// receiving other data than a map.
+func (c *Context) ViewData() M {
+	return c.viewData
+}
+
+// mergeViewData return data with the view data of the request added, when
+// data is nil or a map. Keys of data win over the view data.
+func (c *Context) mergeViewData(data any) any {
+	if len(c.viewData) == 0 {
+		return data
+	}
+	var own map[string]any
+	switch d := data.(type) {
+	case nil:
+	case M:
+		own = d
+	case map[string]any:
+		own = d
+	default:
+		return data
+	}
+	merged := make(M, len(c.viewData)+len(own))
+	for k, v := range c.viewData {
+		merged[k] = v
+	}
+	for k, v := range own {
+		merged[k] = v
+	}
+	return merged
+}
+
+// HTML render the page name with the engine HTMLRenderer and send it with
+// the status code. When data is nil or a map, the values of SetViewData
+// and the pending flash messages ("flashes") are added to it. The page is
+// rendered in a buffer first, so a render error send the default 500
+// response instead of a partial page; the error is attached with Error. It
+// panic when no renderer is set.
+func (c *Context) HTML(code int, name string, data any) {
+	r := c.engine.html
+	if r == nil {
+		panic("glaze: no HTMLRenderer, see WithHTMLRenderer or LoadHTMLGlob")
+	}
+
+	c.flashViewData(data)
+	var buf bytes.Buffer
+	if err := r.Render(c.Request.Context(), &buf, name, c.mergeViewData(data)); err != nil {
+		c.Error(err)
+		c.engine.logf(LogLevelError, "html: render %s: %v\n", name, err)
+		c.defaultResponse(http.StatusInternalServerError)
+		return
+	}
+	if len(c.engine.charsets) > 0 {
+		c.writeText(code, MIME_HTML, buf.Bytes())
+		return
+	}
+	writeContentType(c.Writer, htmlContentType)
+	c.Writer.WriteHeader(code)
+	c.Writer.Write(buf.Bytes())
+}
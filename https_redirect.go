@@ -0,0 +1,103 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// HTTPSRedirectConfig holds the configuration of the HTTPS redirect middleware.
+type HTTPSRedirectConfig struct {
+	// TrustForwardedProto honor X-Forwarded-Proto to detect HTTPS.
+	// Only enable it behind a proxy that always set (or strip) the header.
+	TrustForwardedProto bool
+
+	// AllowedHosts are the hosts a redirect can target. When set, requests
+	// for other hosts get 400 instead of a redirect built from the Host header.
+	AllowedHosts []string
+
+	// HTTPSPort replace the port of the host, empty means the default port 443.
+	HTTPSPort string
+
+	// SkipPaths are request paths never redirected (health checks).
+	SkipPaths []string
+
+	// Skip is a custom predicate, request is not redirected when it return true.
+	Skip func(*Context) bool
+
+	// Status of the redirect, default 301. Non GET/HEAD requests always use 308
+	// so the method and body are kept.
+	Status int
+}
+
+// HTTPSRedirect returns a middleware that redirect plain HTTP requests to HTTPS.
+func HTTPSRedirect(cfg ...HTTPSRedirectConfig) HandlerFunc {
+	var conf HTTPSRedirectConfig
+	if len(cfg) > 0 {
+		conf = cfg[0]
+	}
+	if conf.Status == 0 {
+		conf.Status = http.StatusMovedPermanently
+	}
+
+	skip := make(map[string]struct{}, len(conf.SkipPaths))
+	for _, p := range conf.SkipPaths {
+		skip[p] = struct{}{}
+	}
+	allowed := make(map[string]struct{}, len(conf.AllowedHosts))
+	for _, h := range conf.AllowedHosts {
+		allowed[strings.ToLower(strings.TrimSuffix(strings.TrimPrefix(h, "["), "]"))] = struct{}{}
+	}
+
+	return func(c *Context) {
+		if c.Scheme() == "https" || isHTTPS(c.Request, conf.TrustForwardedProto) {
+			return
+		}
+		if _, ok := skip[c.Request.URL.Path]; ok {
+			return
+		}
+		if conf.Skip != nil && conf.Skip(c) {
+			return
+		}
+
+		host := c.Host()
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		host = strings.ToLower(strings.TrimSuffix(strings.TrimPrefix(host, "["), "]"))
+		if len(allowed) > 0 {
+			if _, ok := allowed[host]; !ok {
+				c.String(http.StatusBadRequest, http.StatusText(http.StatusBadRequest))
+				c.Abort()
+				return
+			}
+		}
+		if conf.HTTPSPort != "" && conf.HTTPSPort != "443" {
+			host = net.JoinHostPort(host, conf.HTTPSPort)
+		} else if strings.Contains(host, ":") {
+			host = "[" + host + "]" // IPv6 literal
+		}
+
+		status := conf.Status
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			status = http.StatusPermanentRedirect
+		}
+		http.Redirect(c.Writer, c.Request, "https://"+host+c.Request.URL.RequestURI(), status)
+		c.Abort()
+	}
+}
+
+// isHTTPS report whether the request came over TLS, directly or via a proxy.
+func isHTTPS(req *http.Request, trustForwarded bool) bool {
+	if req.TLS != nil {
+		return true
+	}
+	if trustForwarded {
+		proto, _, _ := strings.Cut(req.Header.Get("X-Forwarded-Proto"), ",")
+		return strings.EqualFold(strings.TrimSpace(proto), "https")
+	}
+	return false
+}
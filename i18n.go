@@ -0,0 +1,368 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// UnmarshalFunc decode a translation file into v, like json.Unmarshal.
+type UnmarshalFunc func(data []byte, v any) error
+
+// Bundle holds the translated messages for every language.
+// Nested objects in bundle files are flattened with dot keys,
+// {"user": {"hello": "Hi %s"}} is available as "user.hello".
+type Bundle struct {
+	defaultLang string
+
+	mu           sync.RWMutex
+	messages     map[string]map[string]string // lang -> key -> message
+	unmarshalers map[string]UnmarshalFunc     // file extension -> decoder
+}
+
+// NewBundle create a bundle with the fallback language.
+// JSON files are supported by default, other formats (TOML, YAML)
+// can be added with RegisterUnmarshalFunc.
+func NewBundle(defaultLang string) *Bundle {
+	return &Bundle{
+		defaultLang: normalizeLang(defaultLang),
+		messages:    make(map[string]map[string]string),
+		unmarshalers: map[string]UnmarshalFunc{
+			".json": json.Unmarshal,
+		},
+	}
+}
+
+// RegisterUnmarshalFunc register a decoder for a file extension.
+//
+//	bundle.RegisterUnmarshalFunc(".toml", toml.Unmarshal)
+func (b *Bundle) RegisterUnmarshalFunc(ext string, fn UnmarshalFunc) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.unmarshalers[ext] = fn
+}
+
+// AddMessages adds (or replace) messages of a language.
+func (b *Bundle) AddMessages(lang string, messages map[string]string) {
+	lang = normalizeLang(lang)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.messages[lang] == nil {
+		b.messages[lang] = make(map[string]string, len(messages))
+	}
+	for k, v := range messages {
+		b.messages[lang][k] = v
+	}
+}
+
+// LoadFile load a bundle file of the local filesystem.
+// The language is taken from the file name: "en.json", "active.pt-BR.toml".
+func (b *Bundle) LoadFile(filePath string) error {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+	return b.parse(path.Base(filePath), data)
+}
+
+// LoadFS load every bundle file with a registered extension found in dir of fsys.
+func (b *Bundle) LoadFS(fsys fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !b.supported(path.Ext(entry.Name())) {
+			continue
+		}
+		data, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+		if err = b.parse(entry.Name(), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Languages return the loaded languages, sorted.
+func (b *Bundle) Languages() []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	langs := make([]string, 0, len(b.messages))
+	for lang := range b.messages {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// Translate return the message of key in lang formatted with args (fmt verbs).
+// It fallback to the base language ("pt" for "pt-br"), then the default
+// language, then the key itself.
+func (b *Bundle) Translate(lang, key string, args ...any) string {
+	b.mu.RLock()
+	msg, ok := b.lookup(normalizeLang(lang), key)
+	b.mu.RUnlock()
+	if !ok {
+		msg = key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
+
+// Match return the first candidate language (or its base language)
+// available in the bundle, or the default language.
+func (b *Bundle) Match(candidates ...string) string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, lang := range candidates {
+		lang = normalizeLang(lang)
+		if _, ok := b.messages[lang]; ok {
+			return lang
+		}
+		if base, _, found := strings.Cut(lang, "-"); found {
+			if _, ok := b.messages[base]; ok {
+				return base
+			}
+		}
+	}
+	return b.defaultLang
+}
+
+// find return the message of key in lang with fallbacks, without using
+// the key as message.
+func (b *Bundle) find(lang, key string) (string, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.lookup(normalizeLang(lang), key)
+}
+
+// lookup find a message with fallbacks, lock must be held.
+func (b *Bundle) lookup(lang, key string) (string, bool) {
+	if msg, ok := b.messages[lang][key]; ok {
+		return msg, true
+	}
+	if base, _, found := strings.Cut(lang, "-"); found {
+		if msg, ok := b.messages[base][key]; ok {
+			return msg, true
+		}
+	}
+	msg, ok := b.messages[b.defaultLang][key]
+	return msg, ok
+}
+
+// supported report whether a decoder is registered for ext.
+func (b *Bundle) supported(ext string) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	_, ok := b.unmarshalers[ext]
+	return ok
+}
+
+// parse decode a bundle file and add its messages.
+func (b *Bundle) parse(name string, data []byte) error {
+	ext := path.Ext(name)
+	b.mu.RLock()
+	unmarshal := b.unmarshalers[ext]
+	b.mu.RUnlock()
+	if unmarshal == nil {
+		return fmt.Errorf("i18n: no unmarshal func registered for %q", ext)
+	}
+
+	// "active.en.json" -> "en"
+	base := strings.TrimSuffix(name, ext)
+	lang := base[strings.LastIndex(base, ".")+1:]
+
+	var raw map[string]any
+	if err := unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("i18n: parse %s: %w", name, err)
+	}
+	messages := make(map[string]string)
+	flattenMessages("", raw, messages)
+	b.AddMessages(lang, messages)
+	return nil
+}
+
+// flattenMessages turn nested objects into dot separated keys.
+func flattenMessages(prefix string, raw map[string]any, out map[string]string) {
+	for k, v := range raw {
+		if prefix != "" {
+			k = prefix + "." + k
+		}
+		switch val := v.(type) {
+		case string:
+			out[k] = val
+		case map[string]any:
+			flattenMessages(k, val, out)
+		default:
+			out[k] = fmt.Sprint(val)
+		}
+	}
+}
+
+// normalizeLang lower case the tag and use "-" as separator ("pt_BR" -> "pt-br").
+func normalizeLang(lang string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(lang), "_", "-"))
+}
+
+// I18nConfig holds the configuration of the i18n middleware.
+type I18nConfig struct {
+	// QueryKey is the query parameter selecting the locale, default "lang".
+	QueryKey string
+
+	// CookieName is the cookie selecting the locale, default "lang".
+	CookieName string
+}
+
+// localeKey is the context key of the resolved locale.
+type localeKey struct{}
+
+// localizer is stored in the context by the i18n middleware.
+type localizer struct {
+	bundle *Bundle
+	lang   string
+}
+
+// I18n returns a middleware that resolve the request locale from the query
+// parameter, then the cookie, then the Accept-Language header, and make
+// c.T and c.Locale available to the next handlers.
+func I18n(bundle *Bundle, cfg ...I18nConfig) HandlerFunc {
+	var conf I18nConfig
+	if len(cfg) > 0 {
+		conf = cfg[0]
+	}
+	if conf.QueryKey == "" {
+		conf.QueryKey = "lang"
+	}
+	if conf.CookieName == "" {
+		conf.CookieName = "lang"
+	}
+
+	return func(c *Context) {
+		candidates := make([]string, 0, 4)
+		if lang := c.Query(conf.QueryKey); lang != "" {
+			candidates = append(candidates, lang)
+		}
+		if lang, err := c.GetCookie(conf.CookieName); err == nil && lang != "" {
+			candidates = append(candidates, lang)
+		}
+		candidates = append(candidates, parseAcceptLanguage(c.GetHeader("Accept-Language"))...)
+
+		c.Set(localeKey{}, &localizer{bundle: bundle, lang: bundle.Match(candidates...)})
+	}
+}
+
+// T translate key into the request locale, formatting the message with args.
+// It return the key as is when the i18n middleware is not used.
+func (c *Context) T(key string, args ...any) string {
+	if l := c.localizer(); l != nil {
+		return l.bundle.Translate(l.lang, key, args...)
+	}
+	return key
+}
+
+// Locale return the locale resolved by the i18n middleware, or empty string.
+func (c *Context) Locale() string {
+	if l := c.localizer(); l != nil {
+		return l.lang
+	}
+	return ""
+}
+
+// FieldMessage return the message of a validation error in the request
+// locale. The bundle keys are "validation.<rule>.string" or
+// "validation.<rule>.array" for length rules, then "validation.<rule>";
+// {param} and {value} are replaced like with WithValidationMessage.
+// It fallback to the WithValidationMessage template, then FieldError.Message.
+//
+// Usage:
+//
+//	{"validation": {"required": "est obligatoire", "min": {"string": "doit contenir au moins {param} caractères"}}}
+func (c *Context) FieldMessage(e FieldError) string {
+	if l := c.localizer(); l != nil {
+		keys := []string{"validation." + e.Rule}
+		if kind := e.kind(); kind != "" {
+			keys = append([]string{"validation." + e.Rule + "." + kind}, keys...)
+		}
+		for _, key := range keys {
+			if msg, ok := l.bundle.find(l.lang, key); ok {
+				return e.format(msg)
+			}
+		}
+	}
+	if tmpl, ok := c.engine.validationMsgs[e.Rule]; ok {
+		return e.format(tmpl)
+	}
+	return e.Message()
+}
+
+// FieldMessages return the messages of errs by field name in the request
+// locale, like ValidationErrors.Fields.
+func (c *Context) FieldMessages(errs ValidationErrors) map[string]string {
+	out := make(map[string]string, len(errs))
+	for _, e := range errs {
+		if _, ok := out[e.Field]; !ok {
+			out[e.Field] = c.FieldMessage(e)
+		}
+	}
+	return out
+}
+
+// translateError return the message of key in the request locale, or msg.
+func (c *Context) translateError(key, msg string) string {
+	if l := c.localizer(); l != nil {
+		if translated, ok := l.bundle.find(l.lang, key); ok {
+			return translated
+		}
+	}
+	return msg
+}
+
+func (c *Context) localizer() *localizer {
+	v, ok := c.Get(localeKey{})
+	if !ok {
+		return nil
+	}
+	l, _ := v.(*localizer)
+	return l
+}
+
+// parseAcceptLanguage return the languages of the header sorted by quality.
+func parseAcceptLanguage(header string) []string {
+	if header == "" {
+		return nil
+	}
+	type weighted struct {
+		lang string
+		q    float64
+	}
+	var langs []weighted
+	for _, el := range ParseHeader(header) {
+		if el.Value == "" || el.Value == "*" {
+			continue
+		}
+		if q := el.Q(); q > 0 {
+			langs = append(langs, weighted{el.Value, q})
+		}
+	}
+	sort.SliceStable(langs, func(i, j int) bool { return langs[i].q > langs[j].q })
+
+	out := make([]string, len(langs))
+	for i, l := range langs {
+		out[i] = l.lang
+	}
+	return out
+}
@@ -0,0 +1,268 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// CachedResponse is a response saved by the idempotency middleware.
+type CachedResponse struct {
+	Status int
+	Header http.Header
+	Body   []byte
+
+	// BodyHash is the SHA-256 of the request body, set by the idempotency
+	// middleware; stores must keep it.
+	BodyHash string
+}
+
+// IdempotencyStore persist the responses of the idempotency middleware.
+// Implementations must be safe for concurrent use.
+type IdempotencyStore interface {
+	Get(key string) (*CachedResponse, bool)
+	Set(key string, resp *CachedResponse, ttl time.Duration)
+}
+
+// IdempotencyConfig holds the configuration of the idempotency middleware.
+type IdempotencyConfig struct {
+	// Header carry the client key, default "Idempotency-Key".
+	Header string
+
+	// Methods where the key is honored, default POST and PATCH.
+	Methods []string
+
+	// Store save the responses, default an in-memory store.
+	Store IdempotencyStore
+
+	// TTL of a saved response, default 24 hours.
+	TTL time.Duration
+
+	// Scope return a prefix isolating keys, typically the user id, so two
+	// clients can not replay each other responses. Default is empty.
+	Scope func(*Context) string
+}
+
+// Idempotency returns a middleware that save the response of requests with
+// an Idempotency-Key header and replay it when the same key is sent again,
+// so a retried POST does not create or charge twice. Concurrent requests
+// with the same key wait for the first one and get its response.
+// Server errors (5xx) are not saved, so they can be retried. A key sent
+// again with another body is rejected with 422.
+func Idempotency(cfg ...IdempotencyConfig) HandlerFunc {
+	var conf IdempotencyConfig
+	if len(cfg) > 0 {
+		conf = cfg[0]
+	}
+	if conf.Header == "" {
+		conf.Header = "Idempotency-Key"
+	}
+	if conf.Methods == nil {
+		conf.Methods = []string{http.MethodPost, http.MethodPatch}
+	}
+	if conf.Store == nil {
+		conf.Store = NewMemoryIdempotencyStore()
+	}
+	if conf.TTL <= 0 {
+		conf.TTL = 24 * time.Hour
+	}
+
+	methods := make(map[string]struct{}, len(conf.Methods))
+	for _, m := range conf.Methods {
+		methods[m] = struct{}{}
+	}
+
+	var mu sync.Mutex
+	inflight := make(map[string]chan struct{})
+
+	return func(c *Context) {
+		if _, ok := methods[c.Request.Method]; !ok {
+			return
+		}
+		key := c.GetHeader(conf.Header)
+		if key == "" {
+			return
+		}
+		if conf.Scope != nil {
+			key = conf.Scope(c) + ":" + key
+		}
+		key = c.Request.Method + " " + c.Request.URL.Path + " " + key
+		compressed := c.compressed
+		if !compressed {
+			// a Compress running after the middleware may encode the recorded body
+			key += " " + acceptedEncoding(c.GetHeader("Accept-Encoding"))
+		}
+
+		var body []byte
+		if c.Request.Body != nil {
+			var err error
+			body, err = io.ReadAll(c.Request.Body)
+			c.Request.Body.Close()
+			if err != nil {
+				c.String(http.StatusBadRequest, http.StatusText(http.StatusBadRequest))
+				c.Abort()
+				return
+			}
+		}
+		// handlers can still read and bind the body
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		sum := sha256.Sum256(body)
+		bodyHash := hex.EncodeToString(sum[:])
+
+		for {
+			if cached, ok := conf.Store.Get(key); ok {
+				if cached.BodyHash != bodyHash {
+					c.String(http.StatusUnprocessableEntity, "idempotency key reused with another request body")
+					c.Abort()
+					return
+				}
+				replayResponse(c, cached, "Idempotent-Replayed", "true")
+				c.Abort()
+				return
+			}
+
+			mu.Lock()
+			wait, busy := inflight[key]
+			if !busy {
+				inflight[key] = make(chan struct{})
+			}
+			mu.Unlock()
+			if !busy {
+				break
+			}
+
+			// same key in progress, wait for it then look at the store again
+			select {
+			case <-wait:
+			case <-c.Request.Context().Done():
+				c.Abort()
+				return
+			}
+		}
+
+		defer func() {
+			mu.Lock()
+			close(inflight[key])
+			delete(inflight, key)
+			mu.Unlock()
+		}()
+
+		rec := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = rec
+		c.Next()
+		c.Writer = rec.ResponseWriter
+
+		if status := rec.Status(); status < http.StatusInternalServerError {
+			conf.Store.Set(key, &CachedResponse{
+				Status:   status,
+				Header:   recordedHeader(rec.Header().Clone(), compressed),
+				Body:     rec.body.Bytes(),
+				BodyHash: bodyHash,
+			}, conf.TTL)
+		}
+	}
+}
+
+// replayResponse write a saved response, marked with the header key.
+func replayResponse(c *Context, resp *CachedResponse, key, value string) {
+	h := c.Writer.Header()
+	for k, v := range resp.Header {
+		if k == "Vary" {
+			for _, name := range v {
+				if !slices.Contains(h[k], name) {
+					h.Add(k, name)
+				}
+			}
+			continue
+		}
+		h[k] = v
+	}
+	h.Set(key, value)
+	c.Writer.WriteHeader(resp.Status)
+	_, _ = c.Writer.Write(resp.Body)
+}
+
+// recordingWriter keep a copy of the body written through it.
+type recordingWriter struct {
+	ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(data)
+	w.body.Write(data[:n])
+	return n, err
+}
+
+// recordedHeader returns h without the headers that must not be replayed.
+// When compressed, the body was recorded before a Compress middleware
+// encoded it, so the encoding headers are dropped too and Compress set them
+// again on replay.
+func recordedHeader(h http.Header, compressed bool) http.Header {
+	delete(h, "Content-Length")
+	if !compressed {
+		return h
+	}
+	delete(h, "Content-Encoding")
+	vary := slices.DeleteFunc(h["Vary"], func(v string) bool { return v == "Accept-Encoding" })
+	if len(vary) == 0 {
+		delete(h, "Vary")
+	} else {
+		h["Vary"] = vary
+	}
+	return h
+}
+
+// memoryIdempotencyStore is an in-process IdempotencyStore.
+type memoryIdempotencyStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryIdempotencyEntry
+	lastGC  time.Time
+}
+
+type memoryIdempotencyEntry struct {
+	resp    *CachedResponse
+	expires time.Time
+}
+
+// NewMemoryIdempotencyStore create an in-memory store. It is only
+// correct for a single instance, use a shared store (Redis, SQL)
+// when the service run behind a load balancer.
+func NewMemoryIdempotencyStore() IdempotencyStore {
+	return &memoryIdempotencyStore{entries: make(map[string]memoryIdempotencyEntry)}
+}
+
+func (s *memoryIdempotencyStore) Get(key string) (*CachedResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[key]
+	if !ok || time.Now().After(e.expires) {
+		return nil, false
+	}
+	return e.resp, true
+}
+
+func (s *memoryIdempotencyStore) Set(key string, resp *CachedResponse, ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+
+	// drop expired entries from time to time
+	if now.Sub(s.lastGC) > time.Minute {
+		for k, e := range s.entries {
+			if now.After(e.expires) {
+				delete(s.entries, k)
+			}
+		}
+		s.lastGC = now
+	}
+	s.entries[key] = memoryIdempotencyEntry{resp: resp, expires: now.Add(ttl)}
+}
@@ -0,0 +1,139 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// JSONConfig holds the JSON rendering defaults of the engine, used by
+// c.JSON, c.PureJSON and every built-in JSON response.
+type JSONConfig struct {
+	// EscapeHTML escape <, > and & in c.JSON strings. Default false,
+	// c.PureJSON always escape.
+	EscapeHTML bool
+
+	// Indent pretty print the responses in debug mode, for example "  ".
+	// Release mode always write compact JSON.
+	Indent string
+
+	// Transform change the payload before encoding, for example to wrap
+	// every response in an envelope. It is called with the status code.
+	Transform func(c *Context, code int, data any) any
+
+	// Encode replace encoding/json, for a faster library. It must write
+	// one JSON value to w. An error send a 500 response and is attached
+	// to the context with Error.
+	Encode func(w io.Writer, data any, escapeHTML bool) error
+
+	// TimeFormat is the layout of the time.Time values, like time.DateTime,
+	// instead of RFC 3339 with nanoseconds. The encoded strings holding an
+	// RFC 3339 time are rewritten, other string fields with such a value
+	// too. Zero times are omitted with the `json:",omitzero"` tag option.
+	TimeFormat string
+}
+
+// WithJSON set the engine JSON rendering defaults.
+//
+// Usage:
+//
+//	e := glaze.New(glaze.WithJSON(glaze.JSONConfig{
+//	    Indent: "  ",
+//	    Transform: func(c *glaze.Context, code int, data any) any {
+//	        return glaze.M{"data": data, "status": code}
+//	    },
+//	}))
+func WithJSON(cfg JSONConfig) ConfigsFunc {
+	return func(e *Engine) {
+		e.json = cfg
+	}
+}
+
+var jsonBufferPool = sync.Pool{New: func() any { return new(bytes.Buffer) }}
+
+// renderJSON is the single JSON renderer of the context. The body is
+// encoded first, an encoding error send a plain 500 instead.
+func (c *Context) renderJSON(code int, data any, escapeHTML bool) {
+	cfg := &c.engine.json
+	if cfg.Transform != nil {
+		data = cfg.Transform(c, code, data)
+	}
+
+	buf := jsonBufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer jsonBufferPool.Put(buf)
+	var err error
+	if cfg.Encode != nil {
+		err = cfg.Encode(buf, data, escapeHTML)
+	} else {
+		encoder := json.NewEncoder(buf)
+		encoder.SetEscapeHTML(escapeHTML)
+		if cfg.Indent != "" && IsDebugging() {
+			encoder.SetIndent("", cfg.Indent)
+		}
+		err = encoder.Encode(data)
+	}
+	if err != nil {
+		c.Error(err)
+		c.engine.logf(LogLevelError, "json: encode %s %s: %v\n", c.Request.Method, c.Request.URL.Path, err)
+		c.Writer.Header().Set("Content-Type", textPlainContentType)
+		c.Writer.WriteHeader(http.StatusInternalServerError)
+		io.WriteString(c.Writer, "500 "+http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	body := buf.Bytes()
+	if cfg.TimeFormat != "" {
+		body = formatTimes(body, cfg.TimeFormat)
+	}
+	writeContentType(c.Writer, jsonContentType)
+	c.Writer.WriteHeader(code)
+	c.Writer.Write(body)
+}
+
+// formatTimes rewrite the string values of data holding an RFC 3339 time,
+// the encoding of time.Time, with layout. Object keys are not changed.
+func formatTimes(data []byte, layout string) []byte {
+	var out []byte
+	last := 0
+	for i := 0; i < len(data); i++ {
+		if data[i] != '"' {
+			continue
+		}
+		end := i + 1
+		for end < len(data) && data[end] != '"' {
+			if data[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		if end >= len(data) {
+			break
+		}
+		next := end + 1
+		for next < len(data) && (data[next] == ' ' || data[next] == '\n' || data[next] == '\t' || data[next] == '\r') {
+			next++
+		}
+		value := data[i+1 : end]
+		// the shortest RFC 3339 time is 20 bytes, "2006-01-02T15:04:05Z"
+		if len(value) >= 20 && (next == len(data) || data[next] != ':') && bytes.IndexByte(value, '\\') < 0 {
+			if t, err := time.Parse(time.RFC3339Nano, string(value)); err == nil {
+				out = append(out, data[last:i]...)
+				formatted, _ := json.Marshal(t.Format(layout))
+				out = append(out, formatted...)
+				last = end + 1
+			}
+		}
+		i = end
+	}
+	if out == nil {
+		return data
+	}
+	return append(out, data[last:]...)
+}
@@ -0,0 +1,111 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LintIssue is a suspicious configuration reported by Engine.Lint.
+type LintIssue struct {
+	Method  string // empty for engine wide issues
+	Path    string
+	Message string
+}
+
+// String return the issue as "METHOD /path: message".
+func (i LintIssue) String() string {
+	switch {
+	case i.Path == "":
+		return i.Message
+	case i.Method == "":
+		return i.Path + ": " + i.Message
+	}
+	return i.Method + " " + i.Path + ": " + i.Message
+}
+
+// Lint check the routes and middleware configuration:
+//   - routes with no handlers at all
+//   - routes with only group middleware, no handler of their own
+//   - param segments shadowed by another name (c.Param return "")
+//   - groups with no routes
+//   - Recovery not used, a panic would kill the connection
+//
+// It is run by the Run helpers in debug mode, issues are printed as warnings.
+func (e *Engine) Lint() []LintIssue {
+	issues := append([]LintIssue(nil), e.lintIssues...)
+
+	recovered := chainHasRecovery(e.preRouting) || chainHasRecovery(e.postRouting)
+	unprotected := 0
+	for method, root := range e.trees {
+		walkRoutes(root, func(n *node) {
+			if len(n.handlers) == 0 {
+				issues = append(issues, LintIssue{method, n.route.Path, "route has no handlers"})
+			}
+			if !recovered && !chainHasRecovery(n.handlers) {
+				unprotected++
+			}
+		})
+	}
+	if unprotected > 0 {
+		issues = append(issues, LintIssue{Message: fmt.Sprintf(
+			"Recovery middleware is not used by %d routes, a panic will drop the connection", unprotected)})
+	}
+
+	for _, g := range e.groups {
+		if !e.hasRouteUnder(g.Path) {
+			issues = append(issues, LintIssue{Path: g.Path, Message: "group has no routes"})
+		}
+	}
+	return issues
+}
+
+// walkRoutes call fn for every node of the tree that is a route.
+func walkRoutes(n *node, fn func(*node)) {
+	if n.route != nil {
+		fn(n)
+	}
+	for _, child := range n.children {
+		walkRoutes(child, fn)
+	}
+	if n.paramNode != nil {
+		walkRoutes(n.paramNode, fn)
+	}
+	if n.catchAll != nil {
+		walkRoutes(n.catchAll, fn)
+	}
+}
+
+// hasRouteUnder report whether a route path is prefix or below it.
+func (e *Engine) hasRouteUnder(prefix string) bool {
+	prefix = strings.TrimSuffix(prefix, "/")
+	for _, r := range e.routeList {
+		if r.Path == prefix || prefix == "" || strings.HasPrefix(r.Path, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// chainHasRecovery report whether a recovery middleware, see
+// MarkRecovery, is in the chain.
+func chainHasRecovery(chain HandlersChain) bool {
+	for _, h := range chain {
+		if isRecovery(h) {
+			return true
+		}
+	}
+	return false
+}
+
+// debugPrintLint print the Lint issues as warnings in debug mode.
+func (e *Engine) debugPrintLint() {
+	if e.logLevel > LogLevelDebug || !IsDebugging() {
+		return
+	}
+	for _, issue := range e.Lint() {
+		e.logf(LogLevelDebug, "[WARNING] %s\n", issue)
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"fmt"
+	"io"
+)
+
+// LogLevel is the minimum level of the engine messages written.
+type LogLevel int
+
+const (
+	LogLevelDebug  LogLevel = iota // route banner, listen messages (debug mode only)
+	LogLevelInfo                   // server lifecycle (shutdown)
+	LogLevelError                  // panics and server errors, written to the error output
+	LogLevelSilent                 // nothing is written
+)
+
+// WithOutput set where the debug and info messages are written,
+// it is also the default output of the Logger middleware. Default os.Stdout.
+func WithOutput(w io.Writer) ConfigsFunc {
+	return func(e *Engine) {
+		e.writer = w
+	}
+}
+
+// WithErrorOutput set where the error messages (panic reports) are written.
+// Default os.Stderr.
+func WithErrorOutput(w io.Writer) ConfigsFunc {
+	return func(e *Engine) {
+		e.errWriter = w
+	}
+}
+
+// WithLogLevel set the minimum level of the engine messages. Default LogLevelDebug.
+//
+// Usage:
+//
+//	e := glaze.New(glaze.WithLogLevel(glaze.LogLevelError), glaze.WithErrorOutput(alerts))
+func WithLogLevel(level LogLevel) ConfigsFunc {
+	return func(e *Engine) {
+		e.logLevel = level
+	}
+}
+
+// logf write an engine message of level into the matching output.
+func (e *Engine) logf(level LogLevel, format string, args ...any) {
+	if level < e.logLevel || level == LogLevelDebug && !IsDebugging() {
+		return
+	}
+	w := e.writer
+	if level >= LogLevelError {
+		w = e.errWriter
+	}
+	fmt.Fprintf(w, format, args...)
+}
@@ -0,0 +1,123 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+// LogFormatterParams is the information passed to a LogFormatter.
+type LogFormatterParams struct {
+	Request   *http.Request
+	TimeStamp time.Time     // when the response was finished
+	Latency   time.Duration // time spent in the handler chain
+	Status    int           // response status code
+	BodySize  int           // response body size
+	ClientIP  string        // client address without port
+	Method    string
+	Path      string
+}
+
+// LogFormatter render one access log line.
+type LogFormatter func(params LogFormatterParams) string
+
+// LoggerConfig holds the configuration of the logger middleware.
+type LoggerConfig struct {
+	// Output is where the log is written, default is the engine's writer.
+	Output io.Writer
+
+	// Formatter render the log line, default is defaultLogFormatter.
+	Formatter LogFormatter
+
+	// SkipPaths are request paths never logged (health checks, metrics).
+	SkipPaths []string
+
+	// Skip is a custom predicate, request is not logged when it return true.
+	Skip func(*Context) bool
+
+	// SampleRates log only a fraction (0..1) of the requests of a path,
+	// paths not listed are always logged.
+	SampleRates map[string]float64
+
+	// SampleErrors make sampled paths also sample the 5xx responses,
+	// by default server errors are always logged.
+	SampleErrors bool
+}
+
+// Logger returns a middleware that write an access log line
+// for every request into the engine's writer.
+func Logger() HandlerFunc {
+	return LoggerWithConfig(LoggerConfig{})
+}
+
+// LoggerWithConfig returns a logger middleware with custom config.
+//
+// Usage:
+//
+//	r.Use(glaze.LoggerWithConfig(glaze.LoggerConfig{
+//	    SkipPaths:   []string{"/health/live", "/metrics"},
+//	    SampleRates: map[string]float64{"/api/events": 0.01},
+//	}))
+func LoggerWithConfig(cfg LoggerConfig) HandlerFunc {
+	if cfg.Formatter == nil {
+		cfg.Formatter = defaultLogFormatter
+	}
+
+	skip := make(map[string]struct{}, len(cfg.SkipPaths))
+	for _, p := range cfg.SkipPaths {
+		skip[p] = struct{}{}
+	}
+
+	return func(c *Context) {
+		path := c.Request.URL.Path
+		if _, ok := skip[path]; ok {
+			return
+		}
+		if cfg.Skip != nil && cfg.Skip(c) {
+			return
+		}
+
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		if rate, ok := cfg.SampleRates[path]; ok && rand.Float64() >= rate {
+			if cfg.SampleErrors || status < http.StatusInternalServerError {
+				return
+			}
+		}
+
+		out := cfg.Output
+		if out == nil {
+			out = c.engine.writer
+		}
+		now := time.Now()
+		fmt.Fprint(out, cfg.Formatter(LogFormatterParams{
+			Request:   c.Request,
+			TimeStamp: now,
+			Latency:   now.Sub(start),
+			Status:    status,
+			BodySize:  c.Writer.Size(),
+			ClientIP:  c.ClientIP(),
+			Method:    c.Request.Method,
+			Path:      c.Request.URL.RequestURI(),
+		}))
+	}
+}
+
+// defaultLogFormatter is the default log format of the logger middleware.
+func defaultLogFormatter(p LogFormatterParams) string {
+	return fmt.Sprintf("[GLAZE] %s | %3d | %13v | %15s | %-7s %s\n",
+		p.TimeStamp.Format("2006/01/02 - 15:04:05"),
+		p.Status,
+		p.Latency,
+		p.ClientIP,
+		p.Method,
+		p.Path,
+	)
+}
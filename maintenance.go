@@ -0,0 +1,83 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// MaintenanceConfig holds the configuration of the maintenance middleware.
+type MaintenanceConfig struct {
+	// Enabled report whether maintenance mode is on for this request.
+	// It is called on every request, so it can be switched at runtime.
+	Enabled func(*Context) bool
+
+	// Skip excludes requests from maintenance mode (health checks, admin routes).
+	Skip func(*Context) bool
+
+	// RetryAfter is sent as Retry-After header (in seconds) when > 0.
+	RetryAfter time.Duration
+
+	// ContentType of Body, default is plain text.
+	ContentType string
+
+	// Body of the 503 response, default is "Service Unavailable".
+	Body []byte
+}
+
+// Maintenance returns a middleware that respond 503 (Service Unavailable)
+// while the given flag is true. The flag can be toggled at runtime.
+//
+// Usage:
+//
+//	var down atomic.Bool
+//	r.Use(glaze.Maintenance(&down))
+//	down.Store(true) // every request now get 503
+func Maintenance(enabled *atomic.Bool) HandlerFunc {
+	return MaintenanceWithConfig(MaintenanceConfig{
+		Enabled: func(*Context) bool { return enabled.Load() },
+	})
+}
+
+// MaintenanceWithConfig returns a maintenance middleware with custom config.
+// Attach it with Use on the engine for all routes, or on a group for selected routes.
+func MaintenanceWithConfig(cfg MaintenanceConfig) HandlerFunc {
+	if cfg.Enabled == nil {
+		panic("maintenance: Enabled callback is required")
+	}
+	if cfg.ContentType == "" {
+		cfg.ContentType = textPlainContentType
+	}
+	if cfg.Body == nil {
+		cfg.Body = []byte(http.StatusText(http.StatusServiceUnavailable))
+	}
+	retryAfter := ""
+	if cfg.RetryAfter > 0 {
+		retryAfter = retryAfterSeconds(cfg.RetryAfter)
+	}
+
+	return func(c *Context) {
+		if !cfg.Enabled(c) || (cfg.Skip != nil && cfg.Skip(c)) {
+			return
+		}
+
+		h := c.Writer.Header()
+		if retryAfter != "" {
+			h.Set("Retry-After", retryAfter)
+		}
+		h.Set("Content-Type", cfg.ContentType)
+		c.Writer.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = c.Writer.Write(cfg.Body)
+		c.Abort()
+	}
+}
+
+// retryAfterSeconds format d as a Retry-After value, rounded up so a
+// sub-second delay does not invite an immediate retry.
+func retryAfterSeconds(d time.Duration) string {
+	return strconv.Itoa(int(max((d+time.Second-1)/time.Second, 1)))
+}
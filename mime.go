@@ -9,6 +9,9 @@ const (
 	MIME_PLAIN               = "text/plain"
 	MIME_POST_FORM           = "application/x-www-form-urlencoded"
 	MIME_MULTIPART_POST_FORM = "multipart/form-data"
+	MIME_EVENT_STREAM        = "text/event-stream"
+	MIME_NDJSON              = "application/x-ndjson"
+	MIME_PROBLEM_JSON        = "application/problem+json"
 )
 
 var (
@@ -0,0 +1,57 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"os"
+	"sync/atomic"
+)
+
+// EnvGlazeMode is the environment variable read at startup to set the mode.
+const EnvGlazeMode = "GLAZE_MODE"
+
+const (
+	// DebugMode print the route banner and listen messages. It is the default.
+	DebugMode = "debug"
+
+	// ReleaseMode disable every debug behavior, use it in production.
+	ReleaseMode = "release"
+
+	// TestMode is like release mode without output, but debug
+	// safety nets (like RecoveryConfig.RePanic) stay enabled.
+	TestMode = "test"
+)
+
+var glazeMode atomic.Value // string
+
+func init() {
+	SetMode(os.Getenv(EnvGlazeMode))
+}
+
+// SetMode set the mode of every engine, empty value means DebugMode.
+// It panic on unknown mode.
+//
+// Usage:
+//
+//	glaze.SetMode(glaze.ReleaseMode)
+func SetMode(value string) {
+	switch value {
+	case "":
+		value = DebugMode
+	case DebugMode, ReleaseMode, TestMode:
+	default:
+		panic("glaze mode unknown: " + value + " (available mode: debug release test)")
+	}
+	glazeMode.Store(value)
+}
+
+// Mode return the current mode.
+func Mode() string {
+	return glazeMode.Load().(string)
+}
+
+// IsDebugging report whether the mode is DebugMode.
+func IsDebugging() bool {
+	return Mode() == DebugMode
+}
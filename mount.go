@@ -0,0 +1,65 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// mount is a handler chain serving every method and path under a prefix.
+// Routes of the trees win over mounts, the longest prefix win between mounts.
+//
+// methods are the methods the mount serve, nil for all. For the others, a
+// route of the path with another method answer 405 before the mount run.
+type mount struct {
+	prefix  string
+	methods []string
+	node    *node
+}
+
+// readMethods are the methods of the file serving mounts.
+var readMethods = []string{http.MethodGet, http.MethodHead}
+
+// addMount register handlers for every request under prefix.
+func (e *Engine) addMount(prefix string, methods []string, meta map[string]any, handlers HandlersChain) {
+	prefix = "/" + strings.Join(splitClean(prefix), "/")
+	for _, m := range e.mounts {
+		if m.prefix == prefix {
+			panic("duplicate mount detected: " + prefix)
+		}
+	}
+	n := &node{
+		handlers: handlers,
+		route: &RouteInfo{
+			Method:   "*",
+			Path:     prefix,
+			Meta:     meta,
+			Handler:  lastHandlerName(handlers),
+			Handlers: len(handlers),
+		},
+	}
+	e.mounts = append(e.mounts, mount{prefix: prefix, methods: methods, node: n})
+	sort.SliceStable(e.mounts, func(i, j int) bool {
+		return len(e.mounts[i].prefix) > len(e.mounts[j].prefix)
+	})
+	e.routeList = append(e.routeList, *n.route)
+}
+
+// findMount return the mount of the longest prefix matching path.
+func (e *Engine) findMount(path string) *mount {
+	for i, m := range e.mounts {
+		if m.prefix == "/" || path == m.prefix || strings.HasPrefix(path, m.prefix+"/") {
+			return &e.mounts[i]
+		}
+	}
+	return nil
+}
+
+// serves report whether m serve method.
+func (m *mount) serves(method string) bool {
+	return m.methods == nil || slices.Contains(m.methods, method)
+}
@@ -0,0 +1,99 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+)
+
+// clientAuthConfig holds the client certificate settings of the TLS Run helpers.
+type clientAuthConfig struct {
+	auth   tls.ClientAuthType
+	cas    *x509.CertPool
+	verify func(cert *x509.Certificate) error
+}
+
+// WithClientCAs enable mutual TLS in RunTLS, RunTLSConfig and
+// ListenAndGracefulTLS: client certificates must be signed by one of
+// the CAs of pool. Use WithClientAuth to make the certificate optional.
+//
+// Usage:
+//
+//	pool := x509.NewCertPool()
+//	pool.AppendCertsFromPEM(caPEM)
+//	e := glaze.New(glaze.WithClientCAs(pool))
+//	e.RunTLS(":443", "cert.pem", "key.pem")
+func WithClientCAs(pool *x509.CertPool) ConfigsFunc {
+	return func(e *Engine) {
+		e.server.client.cas = pool
+		if e.server.client.auth == tls.NoClientCert {
+			e.server.client.auth = tls.RequireAndVerifyClientCert
+		}
+	}
+}
+
+// WithClientAuth set the client certificate policy,
+// for example tls.VerifyClientCertIfGiven.
+func WithClientAuth(auth tls.ClientAuthType) ConfigsFunc {
+	return func(e *Engine) {
+		e.server.client.auth = auth
+	}
+}
+
+// WithClientVerify add a check of the client certificate (pinning,
+// revocation, allowed subjects) run after the chain verification.
+// Returning an error abort the TLS handshake.
+func WithClientVerify(fn func(cert *x509.Certificate) error) ConfigsFunc {
+	return func(e *Engine) {
+		e.server.client.verify = fn
+	}
+}
+
+// tlsConfig return cfg with the client certificate settings applied,
+// cfg is cloned and may be nil.
+func (e *Engine) tlsConfig(cfg *tls.Config) *tls.Config {
+	client := e.server.client
+	if client.auth == tls.NoClientCert && client.verify == nil {
+		return cfg
+	}
+
+	if cfg == nil {
+		cfg = &tls.Config{}
+	} else {
+		cfg = cfg.Clone()
+	}
+	cfg.ClientAuth = client.auth
+	if client.cas != nil {
+		cfg.ClientCAs = client.cas
+	}
+	if client.verify != nil {
+		cfg.VerifyConnection = func(cs tls.ConnectionState) error {
+			if len(cs.PeerCertificates) == 0 {
+				return nil
+			}
+			return client.verify(cs.PeerCertificates[0])
+		}
+	}
+	return cfg
+}
+
+// PeerCertificate return the client certificate of a mutual TLS
+// connection, only when it was verified against the client CAs.
+// It return nil for plain HTTP or unverified certificates.
+//
+// Usage:
+//
+//	cert := c.PeerCertificate()
+//	if cert == nil || cert.Subject.CommonName != "billing" {
+//	    c.String(http.StatusForbidden, "forbidden")
+//	    return
+//	}
+func (c *Context) PeerCertificate() *x509.Certificate {
+	state := c.Request.TLS
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return state.VerifiedChains[0][0]
+}
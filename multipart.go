@@ -0,0 +1,138 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// multipartMeta is the route metadata key of the MultipartLimits.
+const multipartMeta = "glaze.multipart"
+
+// MultipartLimits holds the multipart limits of a group, overriding the
+// engine ones. Zero fields keep the engine (or parent group) value.
+type MultipartLimits struct {
+	// Memory is the part of a parsed form kept in memory, the rest of the
+	// files spill to temporary files. Default Engine.MultipartMemory.
+	Memory int64
+
+	// MaxFileSize is the size limit of one file of FormFile, MultipartForm,
+	// Bind and Upload (its own MaxFileSize win). Default no limit, 32 MB
+	// for Upload.
+	MaxFileSize int64
+
+	// MaxBodySize is the size limit of the whole multipart body, requests
+	// over it fail with *http.MaxBytesError. Default no limit.
+	MaxBodySize int64
+
+	// TempDir is the directory of the files of Upload without Storage.
+	// Forms parsed by the standard library (FormFile, MultipartForm, Bind)
+	// always spill to os.TempDir.
+	TempDir string
+}
+
+// MultipartLimits returns a copy of the group whose routes use l, like Meta.
+//
+// Usage:
+//
+//	imports := admin.MultipartLimits(glaze.MultipartLimits{
+//	    Memory:      64 << 20,
+//	    MaxFileSize: 1 << 30,
+//	    TempDir:     "/var/lib/app/imports",
+//	})
+//	imports.Post("/import", importHandler)
+func (r *Route) MultipartLimits(l MultipartLimits) *Route {
+	if parent, ok := r.meta[multipartMeta].(MultipartLimits); ok {
+		l = parent.merge(l)
+	}
+	return r.Meta(multipartMeta, l)
+}
+
+// merge return m with the non zero fields of o.
+func (m MultipartLimits) merge(o MultipartLimits) MultipartLimits {
+	if o.Memory > 0 {
+		m.Memory = o.Memory
+	}
+	if o.MaxFileSize > 0 {
+		m.MaxFileSize = o.MaxFileSize
+	}
+	if o.MaxBodySize > 0 {
+		m.MaxBodySize = o.MaxBodySize
+	}
+	if o.TempDir != "" {
+		m.TempDir = o.TempDir
+	}
+	return m
+}
+
+// multipartLimits return the limits of the request route.
+func (c *Context) multipartLimits() MultipartLimits {
+	l := MultipartLimits{Memory: c.engine.MultipartMemory}
+	if c.route != nil {
+		if v, ok := c.route.Meta[multipartMeta].(MultipartLimits); ok {
+			l = l.merge(v)
+		}
+	}
+	return l
+}
+
+// limitMultipartBody apply MaxBodySize to the request body, once.
+func (c *Context) limitMultipartBody(l MultipartLimits) {
+	if l.MaxBodySize > 0 && c.Request.Body != nil {
+		if _, ok := c.Request.Body.(*maxBytesBody); !ok {
+			c.Request.Body = &maxBytesBody{http.MaxBytesReader(c.Writer, c.Request.Body, l.MaxBodySize)}
+		}
+	}
+}
+
+// maxBytesBody mark a body already limited by limitMultipartBody.
+type maxBytesBody struct {
+	body io.ReadCloser
+}
+
+func (b *maxBytesBody) Read(p []byte) (int, error) { return b.body.Read(p) }
+func (b *maxBytesBody) Close() error               { return b.body.Close() }
+
+// parseMultipart parse the multipart form with the route limits, files
+// over MaxFileSize are ValidationErrors with the rule "size".
+func (c *Context) parseMultipart() error {
+	if c.Request.MultipartForm != nil {
+		return nil
+	}
+	l := c.multipartLimits()
+	c.limitMultipartBody(l)
+	counter := c.countBody()
+	err := c.checkMultipart(l)
+	ev := BodyEvent{Kind: "multipart", Size: c.restoreBody(counter), Err: err}
+	ev.Spilled, ev.SpilledBytes = c.multipartSpills()
+	if err != nil && c.Request.MultipartForm != nil {
+		c.Request.MultipartForm.RemoveAll()
+	}
+	c.observeBody(ev)
+	return err
+}
+
+// checkMultipart parse the form and check the file sizes.
+func (c *Context) checkMultipart(l MultipartLimits) error {
+	if err := c.Request.ParseMultipartForm(l.Memory); err != nil {
+		return err
+	}
+	if l.MaxFileSize <= 0 {
+		return nil
+	}
+	var errs ValidationErrors
+	for field, files := range c.Request.MultipartForm.File {
+		for _, fh := range files {
+			if fh.Size > l.MaxFileSize {
+				errs = append(errs, FieldError{Field: field, Rule: "size", Param: strconv.FormatInt(l.MaxFileSize, 10), Value: fh.Size})
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// NDJSONStream send the values returned by next as newline delimited JSON,
+// one line per value flushed as soon as it is written, until next return
+// false. It return the context error when the client is gone and nil when
+// the server shut down, like Stream. The engine Transform is not applied
+// to the lines.
+//
+// Usage:
+//
+//	rows := db.Export(ctx)
+//	err := c.NDJSONStream(http.StatusOK, func() (any, bool) {
+//	    row, ok := <-rows
+//	    return row, ok
+//	})
+func (c *Context) NDJSONStream(code int, next func() (any, bool)) error {
+	h := c.Writer.Header()
+	h.Set("Content-Type", MIME_NDJSON)
+	h.Set("X-Accel-Buffering", "no") // nginx
+	c.Writer.WriteHeader(code)
+
+	encoder := json.NewEncoder(c.Writer)
+	encoder.SetEscapeHTML(c.engine.json.EscapeHTML)
+	ctx := c.Request.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-c.Done():
+			return nil
+		default:
+		}
+		v, ok := next()
+		if !ok {
+			return nil
+		}
+		if err := encoder.Encode(v); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+	}
+}
+
+// BindNDJSON decode a newline delimited JSON body record by record, each
+// record is validated (see Validate) and passed to handler before the next
+// one is read, so big imports are not held in memory. The first decode,
+// validation or handler error stop the reading and is returned; decode and
+// validation errors tell the record number, starting at 1.
+//
+// Usage:
+//
+//	err := glaze.BindNDJSON(c, func(u User) error {
+//	    return repo.Insert(c.Request.Context(), u)
+//	})
+func BindNDJSON[T any](c *Context, handler func(item T) error) error {
+	if c.Request.Body == nil || c.Request.Body == http.NoBody {
+		return nil
+	}
+	counter := c.countBody()
+	err := decodeNDJSON(c, handler)
+	ev := BodyEvent{Kind: "ndjson", Size: c.restoreBody(counter)}
+	if _, ok := err.(ndjsonError); ok {
+		ev.Err = err
+	}
+	c.observeBody(ev)
+	return err
+}
+
+// ndjsonError is a decode or validation error of a record, not an error of
+// the handler.
+type ndjsonError struct{ error }
+
+func (e ndjsonError) Unwrap() error { return e.error }
+
+func decodeNDJSON[T any](c *Context, handler func(item T) error) error {
+	decoder := json.NewDecoder(c.Request.Body)
+	for n := 1; ; n++ {
+		var item T
+		if err := decoder.Decode(&item); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return ndjsonError{fmt.Errorf("invalid NDJSON record %d: %w", n, err)}
+		}
+		if err := Validate(&item); err != nil {
+			return ndjsonError{fmt.Errorf("NDJSON record %d: %w", n, err)}
+		}
+		if err := handler(item); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"strings"
+)
+
+// negotiateFormat return the offer preferred by the accept header,
+// or "" when none is acceptable. An empty header accept the first offer.
+// Structured suffix types like application/problem+json match application/json.
+// The quality of an offer is the one of the most specific range matching it,
+// so "*/*, application/json;q=0" refuse JSON.
+func negotiateFormat(accept string, offers ...string) string {
+	if len(offers) == 0 {
+		return ""
+	}
+	elements := ParseHeader(accept)
+	if len(elements) == 0 {
+		return offers[0]
+	}
+
+	best, bestQ, bestSpecificity := "", 0.0, -1
+	for _, offer := range offers {
+		q, specificity := 0.0, -1
+		for _, el := range elements {
+			if s := matchMediaRange(mediaRange(el.Value), offer); s > specificity {
+				q, specificity = el.Q(), s
+			}
+		}
+		if q > bestQ || q > 0 && q == bestQ && specificity > bestSpecificity {
+			best, bestQ, bestSpecificity = offer, q, specificity
+		}
+	}
+	return best
+}
+
+// mediaRange normalize an Accept media range for matchMediaRange.
+func mediaRange(value string) string {
+	value = strings.ToLower(value)
+	if strings.HasSuffix(value, "+json") {
+		return MIME_JSON
+	}
+	return value
+}
+
+// matchMediaRange return how specific mediaRange match offer:
+// 2 exact, 1 "type/*", 0 "*/*" and -1 no match.
+func matchMediaRange(mediaRange, offer string) int {
+	switch {
+	case mediaRange == offer:
+		return 2
+	case mediaRange == "*/*":
+		return 0
+	case strings.HasSuffix(mediaRange, "/*"):
+		if strings.HasPrefix(offer, mediaRange[:len(mediaRange)-1]) {
+			return 1
+		}
+	}
+	return -1
+}
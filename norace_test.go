@@ -0,0 +1,9 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+//go:build !race
+
+package glaze
+
+// raceEnabled report whether the tests run with the race detector.
+const raceEnabled = false
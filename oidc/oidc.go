@@ -0,0 +1,423 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+// Package oidc implements OpenID Connect login for glaze applications
+// with the authorization code flow and PKCE.
+//
+// Usage:
+//
+//	auth, err := oidc.New(ctx, oidc.Config{
+//	    Issuer:       "https://accounts.example.com",
+//	    ClientID:     "my-app",
+//	    ClientSecret: os.Getenv("OIDC_SECRET"),
+//	    RedirectURL:  "https://app.example.com/auth/callback",
+//	    CookieSecret: []byte(os.Getenv("COOKIE_SECRET")),
+//	})
+//	auth.Mount(r, "/auth") // /auth/login, /auth/callback, /auth/logout
+//	r.Use(auth.Middleware())
+//	r.Group("/account", auth.RequireLogin("/auth/login")).Get("/", accountPage)
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/nrhox/glaze"
+)
+
+const (
+	flowCookie    = "glaze_oidc_flow"
+	sessionCookie = "glaze_oidc_session"
+	flowMaxAge    = 10 * 60 // seconds to finish the login at the provider
+	clockSkew     = time.Minute
+)
+
+// Config holds the configuration of the OpenID Connect client.
+type Config struct {
+	// Issuer is the provider URL, discovery is read from
+	// Issuer + "/.well-known/openid-configuration".
+	Issuer string
+
+	ClientID     string
+	ClientSecret string
+
+	// RedirectURL is the absolute URL of the callback route.
+	RedirectURL string
+
+	// Scopes requested, "openid" is always included.
+	// Default is openid, profile and email.
+	Scopes []string
+
+	// CookieSecret sign the flow and session cookies, at least 32 bytes.
+	CookieSecret []byte
+
+	// SessionTTL is the lifetime of the login session, default 8 hours.
+	SessionTTL time.Duration
+
+	// AfterLogoutURL is where the user land after logout, default "/".
+	AfterLogoutURL string
+
+	// OnLogin is called after the ID token is verified, a returned error
+	// abort the login with 500. Use it to map the identity to a local user
+	// or to store the tokens server side.
+	OnLogin func(c *glaze.Context, id *Identity, tokens *Tokens) error
+
+	// HTTPClient is used to talk to the provider, default http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// Identity is the authenticated user, stored in the session cookie.
+type Identity struct {
+	Subject   string    `json:"sub"`
+	Email     string    `json:"email,omitempty"`
+	Name      string    `json:"name,omitempty"`
+	ExpiresAt time.Time `json:"exp"`
+}
+
+// Tokens is the token response of the provider.
+type Tokens struct {
+	AccessToken  string         `json:"access_token"`
+	TokenType    string         `json:"token_type"`
+	RefreshToken string         `json:"refresh_token,omitempty"`
+	ExpiresIn    int            `json:"expires_in,omitempty"`
+	IDToken      string         `json:"id_token"`
+	Claims       map[string]any `json:"-"` // verified ID token claims
+}
+
+// discovery is the subset of the provider metadata used by the client.
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+	EndSessionEndpoint    string `json:"end_session_endpoint"`
+}
+
+// flowState is kept in the flow cookie between login and callback.
+type flowState struct {
+	State    string `json:"s"`
+	Nonce    string `json:"n"`
+	Verifier string `json:"v"`
+	ReturnTo string `json:"r"`
+}
+
+// Client is an OpenID Connect relying party.
+type Client struct {
+	cfg      Config
+	provider discovery
+	keys     *keySet
+}
+
+// identityKey is the context key of the logged in identity.
+type identityKey struct{}
+
+// New create a client and fetch the provider discovery document.
+func New(ctx context.Context, cfg Config) (*Client, error) {
+	if cfg.Issuer == "" || cfg.ClientID == "" || cfg.RedirectURL == "" {
+		return nil, errors.New("oidc: Issuer, ClientID and RedirectURL are required")
+	}
+	if len(cfg.CookieSecret) < 32 {
+		return nil, errors.New("oidc: CookieSecret must be at least 32 bytes")
+	}
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = http.DefaultClient
+	}
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"openid", "profile", "email"}
+	} else if !contains(cfg.Scopes, "openid") {
+		cfg.Scopes = append([]string{"openid"}, cfg.Scopes...)
+	}
+	if cfg.SessionTTL <= 0 {
+		cfg.SessionTTL = 8 * time.Hour
+	}
+	if cfg.AfterLogoutURL == "" {
+		cfg.AfterLogoutURL = "/"
+	}
+
+	var d discovery
+	wellKnown := strings.TrimSuffix(cfg.Issuer, "/") + "/.well-known/openid-configuration"
+	if err := getJSON(ctx, cfg.HTTPClient, wellKnown, &d); err != nil {
+		return nil, err
+	}
+	if d.Issuer != cfg.Issuer {
+		return nil, fmt.Errorf("oidc: issuer mismatch, got %q", d.Issuer)
+	}
+
+	return &Client{
+		cfg:      cfg,
+		provider: d,
+		keys:     &keySet{uri: d.JWKSURI, client: cfg.HTTPClient},
+	}, nil
+}
+
+// Mount registers the login, callback and logout routes under prefix.
+// The callback path must match Config.RedirectURL.
+func (cl *Client) Mount(r glaze.Routes, prefix string) {
+	r.Get(prefix+"/login", cl.Login())
+	r.Get(prefix+"/callback", cl.Callback())
+	r.Get(prefix+"/logout", cl.Logout())
+	r.Post(prefix+"/logout", cl.Logout())
+}
+
+// Login returns a handler redirecting the user to the provider.
+// The "return_to" query parameter (a local path) is where the user
+// land after a successful login.
+func (cl *Client) Login() glaze.HandlerFunc {
+	return func(c *glaze.Context) {
+		flow := flowState{
+			State:    randomString(),
+			Nonce:    randomString(),
+			Verifier: randomString(),
+			ReturnTo: localPath(c.Query("return_to")),
+		}
+		value, err := signedCookie(cl.cfg.CookieSecret, flow)
+		if err != nil {
+			c.String(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+			return
+		}
+		setCookie(c.Writer, c.Request, flowCookie, value, "/", flowMaxAge)
+
+		challenge := sha256.Sum256([]byte(flow.Verifier))
+		q := url.Values{
+			"response_type":         {"code"},
+			"client_id":             {cl.cfg.ClientID},
+			"redirect_uri":          {cl.cfg.RedirectURL},
+			"scope":                 {strings.Join(cl.cfg.Scopes, " ")},
+			"state":                 {flow.State},
+			"nonce":                 {flow.Nonce},
+			"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+			"code_challenge_method": {"S256"},
+		}
+		http.Redirect(c.Writer, c.Request, withQuery(cl.provider.AuthorizationEndpoint, q), http.StatusFound)
+	}
+}
+
+// Callback returns the handler of the redirect URL. It check the state,
+// exchange the code, verify the ID token and start the session.
+func (cl *Client) Callback() glaze.HandlerFunc {
+	return func(c *glaze.Context) {
+		raw, err := c.Request.Cookie(flowCookie)
+		var flow flowState
+		if err != nil || readSignedCookie(cl.cfg.CookieSecret, raw.Value, &flow) != nil {
+			c.String(http.StatusBadRequest, "invalid login flow")
+			return
+		}
+		setCookie(c.Writer, c.Request, flowCookie, "", "/", -1)
+
+		if e := c.Query("error"); e != "" {
+			c.String(http.StatusUnauthorized, "login failed: "+e)
+			return
+		}
+		if c.Query("state") != flow.State {
+			c.String(http.StatusBadRequest, "invalid state")
+			return
+		}
+
+		ctx := c.Request.Context()
+		tokens, err := cl.exchange(ctx, c.Query("code"), flow.Verifier)
+		if err != nil {
+			c.String(http.StatusBadGateway, "token exchange failed")
+			return
+		}
+		id, err := cl.verifyIDToken(ctx, tokens, flow.Nonce)
+		if err != nil {
+			c.String(http.StatusUnauthorized, "invalid id token")
+			return
+		}
+
+		if cl.cfg.OnLogin != nil {
+			if err = cl.cfg.OnLogin(c, id, tokens); err != nil {
+				c.String(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+				return
+			}
+		}
+		value, err := signedCookie(cl.cfg.CookieSecret, id)
+		if err != nil {
+			c.String(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+			return
+		}
+		setCookie(c.Writer, c.Request, sessionCookie, value, "/", int(cl.cfg.SessionTTL.Seconds()))
+		http.Redirect(c.Writer, c.Request, flow.ReturnTo, http.StatusFound)
+	}
+}
+
+// Logout returns a handler that end the session and redirect to the
+// provider end session endpoint when it is advertised.
+func (cl *Client) Logout() glaze.HandlerFunc {
+	return func(c *glaze.Context) {
+		setCookie(c.Writer, c.Request, sessionCookie, "", "/", -1)
+
+		target := cl.cfg.AfterLogoutURL
+		if cl.provider.EndSessionEndpoint != "" {
+			target = withQuery(cl.provider.EndSessionEndpoint, url.Values{
+				"client_id":                {cl.cfg.ClientID},
+				"post_logout_redirect_uri": {cl.cfg.AfterLogoutURL},
+			})
+		}
+		http.Redirect(c.Writer, c.Request, target, http.StatusFound)
+	}
+}
+
+// Middleware returns a middleware loading the session identity,
+// available with IdentityFrom. Anonymous requests pass through.
+func (cl *Client) Middleware() glaze.HandlerFunc {
+	return func(c *glaze.Context) {
+		raw, err := c.Request.Cookie(sessionCookie)
+		if err != nil {
+			return
+		}
+		var id Identity
+		if readSignedCookie(cl.cfg.CookieSecret, raw.Value, &id) != nil || time.Now().After(id.ExpiresAt) {
+			return
+		}
+		c.Set(identityKey{}, &id)
+	}
+}
+
+// RequireLogin returns a middleware redirecting anonymous users to
+// the login route mounted at loginPath, then back to the current page.
+func (cl *Client) RequireLogin(loginPath string) glaze.HandlerFunc {
+	return func(c *glaze.Context) {
+		if IdentityFrom(c) != nil {
+			return
+		}
+		target := withQuery(loginPath, url.Values{"return_to": {c.Request.URL.RequestURI()}})
+		http.Redirect(c.Writer, c.Request, target, http.StatusFound)
+		c.Abort()
+	}
+}
+
+// IdentityFrom return the identity loaded by Middleware, or nil.
+func IdentityFrom(c *glaze.Context) *Identity {
+	v, _ := c.Get(identityKey{})
+	id, _ := v.(*Identity)
+	return id
+}
+
+// exchange trade the authorization code for tokens.
+func (cl *Client) exchange(ctx context.Context, code, verifier string) (*Tokens, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {cl.cfg.RedirectURL},
+		"code_verifier": {verifier},
+		"client_id":     {cl.cfg.ClientID},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cl.provider.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", glaze.MIME_POST_FORM)
+	if cl.cfg.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(cl.cfg.ClientID), url.QueryEscape(cl.cfg.ClientSecret))
+	}
+
+	resp, err := cl.cfg.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("oidc: token endpoint: %s", resp.Status)
+	}
+
+	var tokens Tokens
+	if err = json.NewDecoder(resp.Body).Decode(&tokens); err != nil {
+		return nil, err
+	}
+	if tokens.IDToken == "" {
+		return nil, errors.New("oidc: token response without id_token")
+	}
+	return &tokens, nil
+}
+
+// verifyIDToken check signature and standard claims of the ID token.
+func (cl *Client) verifyIDToken(ctx context.Context, tokens *Tokens, nonce string) (*Identity, error) {
+	var claims struct {
+		Issuer   string   `json:"iss"`
+		Audience audience `json:"aud"`
+		Expiry   int64    `json:"exp"`
+		Nonce    string   `json:"nonce"`
+		Subject  string   `json:"sub"`
+		Email    string   `json:"email"`
+		Name     string   `json:"name"`
+	}
+	if err := verifyJWT(ctx, cl.keys, tokens.IDToken, &claims); err != nil {
+		return nil, err
+	}
+	if err := decodeSegment(strings.Split(tokens.IDToken, ".")[1], &tokens.Claims); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case claims.Issuer != cl.provider.Issuer:
+		return nil, errors.New("oidc: issuer mismatch")
+	case !claims.Audience.contains(cl.cfg.ClientID):
+		return nil, errors.New("oidc: audience mismatch")
+	case time.Unix(claims.Expiry, 0).Add(clockSkew).Before(time.Now()):
+		return nil, errors.New("oidc: id token expired")
+	case claims.Nonce != nonce:
+		return nil, errors.New("oidc: nonce mismatch")
+	case claims.Subject == "":
+		return nil, errors.New("oidc: missing subject")
+	}
+
+	return &Identity{
+		Subject:   claims.Subject,
+		Email:     claims.Email,
+		Name:      claims.Name,
+		ExpiresAt: time.Now().Add(cl.cfg.SessionTTL),
+	}, nil
+}
+
+// randomString return 32 random bytes encoded with base64url.
+func randomString() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// localPath only accept a local absolute path to avoid open redirects.
+func localPath(p string) string {
+	if !strings.HasPrefix(p, "/") || strings.HasPrefix(p, "//") {
+		return "/"
+	}
+	// browsers drop tabs and newlines and read a backslash as "/", so
+	// "/<tab>/evil" would become "//evil"
+	for i := 0; i < len(p); i++ {
+		if p[i] < 0x20 || p[i] == 0x7f || p[i] == '\\' {
+			return "/"
+		}
+	}
+	if u, err := url.Parse(p); err != nil || u.Scheme != "" || u.Host != "" {
+		return "/"
+	}
+	return p
+}
+
+func withQuery(base string, q url.Values) string {
+	if strings.Contains(base, "?") {
+		return base + "&" + q.Encode()
+	}
+	return base + "?" + q.Encode()
+}
+
+func contains(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/nrhox/glaze"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeProvider is a minimal OpenID provider signing tokens with RS256.
+func fakeProvider(t *testing.T, nonce *string) *httptest.Server {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	var srv *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 srv.URL,
+			"authorization_endpoint": srv.URL + "/authorize",
+			"token_endpoint":         srv.URL + "/token",
+			"jwks_uri":               srv.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "k1",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.PostForm.Get("code") != "good-code" || r.PostForm.Get("code_verifier") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","kid":"k1"}`))
+		claims, _ := json.Marshal(map[string]any{
+			"iss": srv.URL, "aud": "app", "sub": "user-1", "email": "bob@example.com",
+			"exp": time.Now().Add(time.Hour).Unix(), "nonce": *nonce,
+		})
+		payload := header + "." + base64.RawURLEncoding.EncodeToString(claims)
+		digest := sha256.Sum256([]byte(payload))
+		sig, _ := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+		json.NewEncoder(w).Encode(map[string]string{
+			"access_token": "at", "token_type": "Bearer",
+			"id_token": payload + "." + base64.RawURLEncoding.EncodeToString(sig),
+		})
+	})
+	srv = httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestLoginFlow(t *testing.T) {
+	var nonce string
+	provider := fakeProvider(t, &nonce)
+
+	auth, err := New(context.Background(), Config{
+		Issuer:       provider.URL,
+		ClientID:     "app",
+		RedirectURL:  "http://app.test/auth/callback",
+		CookieSecret: []byte("0123456789abcdef0123456789abcdef"),
+	})
+	require.NoError(t, err)
+
+	r := glaze.New()
+	auth.Mount(r, "/auth")
+	r.Use(auth.Middleware())
+	r.Get("/me", auth.RequireLogin("/auth/login"), func(c *glaze.Context) {
+		c.String(200, IdentityFrom(c).Email)
+	})
+
+	// anonymous user is sent to login
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/me", nil))
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "/auth/login?return_to=%2Fme", w.Header().Get("Location"))
+
+	// login redirect to the provider with PKCE
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/auth/login?return_to=/me", nil))
+	loc, err := url.Parse(w.Header().Get("Location"))
+	require.NoError(t, err)
+	assert.Equal(t, "S256", loc.Query().Get("code_challenge_method"))
+	nonce = loc.Query().Get("nonce")
+	flow := w.Result().Cookies()[0]
+
+	// wrong state is rejected
+	req := httptest.NewRequest("GET", "/auth/callback?code=good-code&state=forged", nil)
+	req.AddCookie(flow)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	req = httptest.NewRequest("GET", "/auth/callback?code=good-code&state="+loc.Query().Get("state"), nil)
+	req.AddCookie(flow)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "/me", w.Header().Get("Location"))
+
+	var session *http.Cookie
+	for _, ck := range w.Result().Cookies() {
+		if ck.Name == sessionCookie {
+			session = ck
+		}
+	}
+	require.NotNil(t, session)
+
+	req = httptest.NewRequest("GET", "/me", nil)
+	req.AddCookie(session)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "bob@example.com", w.Body.String())
+}
+
+func TestLocalPath(t *testing.T) {
+	for target, want := range map[string]string{
+		"/orders?id=1":     "/orders?id=1",
+		"//evil.example":   "/",
+		"/\\evil.example":  "/",
+		"https://evil":     "/",
+		"/\t/evil.example": "/",
+		"/\n/evil.example": "/",
+		"/\x7f/evil":       "/",
+		"/a\\b":            "/",
+		"":                 "/",
+	} {
+		assert.Equal(t, want, localPath(target), target)
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/nrhox/glaze"
+)
+
+// AddExamples return spec with the route examples (see glaze.Route.Example)
+// added to the request bodies and responses of the matching operations,
+// so the examples stay next to the handler code. basePath is removed from
+// the route paths, like Config.BasePath. Request bodies and responses
+// given by $ref are left untouched. A route with examples and no operation
+// in the spec is an error, the spec is out of date.
+//
+// Usage:
+//
+//	spec, err := openapi.AddExamples(spec, "/api", e.RoutesInfo())
+//	docs.Mount(r, "/docs", docs.Config{Spec: spec})
+func AddExamples(spec []byte, basePath string, routes []glaze.RouteInfo) ([]byte, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		return nil, fmt.Errorf("openapi: invalid document: %w", err)
+	}
+	paths, _ := doc["paths"].(map[string]any)
+
+	for _, ri := range routes {
+		examples := ri.Examples()
+		if len(examples) == 0 {
+			continue
+		}
+		path := specPath(strings.TrimPrefix(ri.Path, strings.TrimSuffix(basePath, "/")))
+		item, _ := paths[path].(map[string]any)
+		op, _ := item[strings.ToLower(ri.Method)].(map[string]any)
+		if op == nil {
+			return nil, fmt.Errorf("openapi: %s %s has examples and no operation", ri.Method, path)
+		}
+		for _, ex := range examples {
+			if ex.Request != nil {
+				addExample(op, "requestBody", ex.ContentType, ex)
+			}
+			if ex.Response != nil {
+				responses := child(op, "responses")
+				if responses == nil {
+					continue
+				}
+				addExample(responses, strconv.Itoa(ex.Status), ex.ContentType, ex)
+			}
+		}
+	}
+	return json.Marshal(doc)
+}
+
+// addExample set parent[key].content[contentType].examples[ex.Name].
+func addExample(parent map[string]any, key, contentType string, ex glaze.Example) {
+	obj := child(parent, key)
+	if obj == nil {
+		return
+	}
+	value := ex.Request
+	if key != "requestBody" {
+		value = ex.Response
+		if obj["description"] == nil {
+			obj["description"] = http.StatusText(ex.Status) // required by the spec
+		}
+	}
+	examples := child(child(child(obj, "content"), contentType), "examples")
+	if examples == nil {
+		return
+	}
+	example := map[string]any{"value": value}
+	if ex.Summary != "" {
+		example["summary"] = ex.Summary
+	}
+	examples[ex.Name] = example
+}
+
+// child return parent[key] as an object, created when missing, nil when
+// it is a $ref.
+func child(parent map[string]any, key string) map[string]any {
+	if parent == nil {
+		return nil
+	}
+	obj, ok := parent[key].(map[string]any)
+	if !ok {
+		obj = map[string]any{}
+		parent[key] = obj
+	}
+	if _, ref := obj["$ref"]; ref {
+		return nil
+	}
+	return obj
+}
+
+// specPath convert a glaze route path to an OpenAPI one, "/users/:id"
+// become "/users/{id}" and "/files/*path" become "/files/{path}".
+func specPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		if strings.HasPrefix(s, ":") || strings.HasPrefix(s, "*") {
+			segments[i] = "{" + s[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,532 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+// Package openapi validate requests against an OpenAPI 3 document, for
+// contract-first APIs: path, query and header parameters and JSON bodies
+// are checked with the schemas of the matching operation, non-conforming
+// requests are rejected with an RFC 7807 problem response.
+//
+// The document must be JSON, convert YAML specs first. The schema support
+// cover the common keywords: type, format, enum, required, properties,
+// additionalProperties, items, min/max lengths and values, pattern,
+// nullable, allOf, anyOf, oneOf and local $ref.
+//
+// Usage:
+//
+//	//go:embed openapi.json
+//	var spec []byte
+//
+//	r.Use(openapi.Validator(openapi.Config{Spec: spec}))
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nrhox/glaze"
+)
+
+const defaultMaxBodySize = 10 << 20 // 10 MB
+
+// Config holds the configuration of the validator.
+type Config struct {
+	// Spec is the OpenAPI 3 document, as JSON.
+	Spec []byte
+
+	// BasePath is removed from the request path before matching the
+	// spec paths, "/api" when the spec paths are served under /api.
+	BasePath string
+
+	// Strict reject the requests of routes whose path (404) or method
+	// (405) is missing from the spec, they are passed by default.
+	Strict bool
+
+	// MaxBodySize limit the JSON bodies read for validation, larger ones
+	// are rejected with 413. Default 10 MB.
+	MaxBodySize int64
+}
+
+// FieldError is a part of the request not matching the spec.
+type FieldError struct {
+	In      string `json:"in"`             // path, query, header or body
+	Name    string `json:"name,omitempty"` // parameter name, or JSON pointer of the body value
+	Message string `json:"message"`
+}
+
+// Error return "in name: message".
+func (e FieldError) Error() string {
+	if e.Name == "" {
+		return e.In + ": " + e.Message
+	}
+	return e.In + " " + e.Name + ": " + e.Message
+}
+
+// document is the part of the OpenAPI document used for validation.
+type document struct {
+	OpenAPI    string                                `json:"openapi"`
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Schemas       map[string]*Schema      `json:"schemas"`
+		Parameters    map[string]*parameter   `json:"parameters"`
+		RequestBodies map[string]*requestBody `json:"requestBodies"`
+	} `json:"components"`
+}
+
+type parameter struct {
+	Ref      string  `json:"$ref"`
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Schema   *Schema `json:"schema"`
+	Explode  *bool   `json:"explode"`
+}
+
+type requestBody struct {
+	Ref      string                `json:"$ref"`
+	Required bool                  `json:"required"`
+	Content  map[string]*mediaType `json:"content"`
+}
+
+type mediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type operation struct {
+	Parameters  []*parameter `json:"parameters"`
+	RequestBody *requestBody `json:"requestBody"`
+}
+
+// route is a spec path with its operations.
+type route struct {
+	segments   []string // "{id}" for parameters
+	operations map[string]*operation
+	params     []*parameter // shared by the operations
+}
+
+// validator is a loaded document.
+type validator struct {
+	doc    *document
+	routes []*route
+}
+
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// load parse the spec and resolve its parameters and request bodies.
+func load(spec []byte) (*validator, error) {
+	doc := &document{}
+	if err := json.Unmarshal(spec, doc); err != nil {
+		return nil, fmt.Errorf("openapi: invalid document: %w", err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		return nil, fmt.Errorf("openapi: unsupported version %q", doc.OpenAPI)
+	}
+
+	v := &validator{doc: doc}
+	for path, item := range doc.Paths {
+		rt := &route{segments: strings.Split(strings.Trim(path, "/"), "/"), operations: map[string]*operation{}}
+		if raw, ok := item["parameters"]; ok {
+			if err := json.Unmarshal(raw, &rt.params); err != nil {
+				return nil, fmt.Errorf("openapi: %s parameters: %w", path, err)
+			}
+		}
+		for _, m := range methods {
+			raw, ok := item[m]
+			if !ok {
+				continue
+			}
+			op := &operation{}
+			if err := json.Unmarshal(raw, op); err != nil {
+				return nil, fmt.Errorf("openapi: %s %s: %w", strings.ToUpper(m), path, err)
+			}
+			rt.operations[strings.ToUpper(m)] = op
+		}
+		if err := v.resolveRoute(rt); err != nil {
+			return nil, fmt.Errorf("openapi: %s: %w", path, err)
+		}
+		v.routes = append(v.routes, rt)
+	}
+
+	// static segments before parameters, "/users/me" before "/users/{id}"
+	if err := v.checkSchemas(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(v.routes, func(i, j int) bool {
+		return routeRank(v.routes[i]) < routeRank(v.routes[j])
+	})
+	return v, nil
+}
+
+// checkSchemas report the unknown schema references of the document.
+func (v *validator) checkSchemas() error {
+	seen := map[*Schema]bool{}
+	check := func(s *Schema) error {
+		if err := v.checkRefs(s, seen); err != nil {
+			return fmt.Errorf("openapi: %w", err)
+		}
+		return nil
+	}
+	for _, s := range v.doc.Components.Schemas {
+		if err := check(s); err != nil {
+			return err
+		}
+	}
+	for _, rt := range v.routes {
+		for _, op := range rt.operations {
+			for _, p := range op.Parameters {
+				if err := check(p.Schema); err != nil {
+					return err
+				}
+			}
+			if op.RequestBody != nil {
+				for _, m := range op.RequestBody.Content {
+					if m == nil {
+						continue
+					}
+					if err := check(m.Schema); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func routeRank(rt *route) string {
+	var b strings.Builder
+	for _, s := range rt.segments {
+		if isParam(s) {
+			b.WriteByte('1')
+		} else {
+			b.WriteByte('0')
+		}
+	}
+	return b.String()
+}
+
+func isParam(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+// resolveRoute replace the $ref of parameters and request bodies, and
+// merge the path parameters into every operation.
+func (v *validator) resolveRoute(rt *route) error {
+	resolveParams := func(params []*parameter) error {
+		for i, p := range params {
+			if p.Ref == "" {
+				continue
+			}
+			ref, ok := v.doc.Components.Parameters[refName(p.Ref, "parameters")]
+			if !ok {
+				return fmt.Errorf("unknown parameter %s", p.Ref)
+			}
+			params[i] = ref
+		}
+		return nil
+	}
+	if err := resolveParams(rt.params); err != nil {
+		return err
+	}
+	for _, op := range rt.operations {
+		if err := resolveParams(op.Parameters); err != nil {
+			return err
+		}
+		// operation parameters override the path ones with the same name and location
+		merged := op.Parameters
+		for _, p := range rt.params {
+			found := false
+			for _, o := range op.Parameters {
+				found = found || o.Name == p.Name && o.In == p.In
+			}
+			if !found {
+				merged = append(merged, p)
+			}
+		}
+		op.Parameters = merged
+
+		if body := op.RequestBody; body != nil && body.Ref != "" {
+			ref, ok := v.doc.Components.RequestBodies[refName(body.Ref, "requestBodies")]
+			if !ok {
+				return fmt.Errorf("unknown request body %s", body.Ref)
+			}
+			op.RequestBody = ref
+		}
+	}
+	return nil
+}
+
+// refName return the component name of a local reference.
+func refName(ref, kind string) string {
+	return strings.TrimPrefix(ref, "#/components/"+kind+"/")
+}
+
+// match return the route of path and its path parameters.
+func (v *validator) match(path string) (*route, map[string]string) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+next:
+	for _, rt := range v.routes {
+		if len(rt.segments) != len(segments) {
+			continue
+		}
+		params := map[string]string{}
+		for i, s := range rt.segments {
+			if isParam(s) {
+				if segments[i] == "" {
+					continue next
+				}
+				params[s[1:len(s)-1]] = segments[i]
+			} else if s != segments[i] {
+				continue next
+			}
+		}
+		return rt, params
+	}
+	return nil, nil
+}
+
+// Validator returns a middleware validating the requests against cfg.Spec,
+// it panics when the spec is invalid. Requests matching no operation are
+// passed unless cfg.Strict is set.
+func Validator(cfg Config) glaze.HandlerFunc {
+	v, err := load(cfg.Spec)
+	if err != nil {
+		panic(err)
+	}
+	base := strings.TrimSuffix(cfg.BasePath, "/")
+	if cfg.MaxBodySize <= 0 {
+		cfg.MaxBodySize = defaultMaxBodySize
+	}
+
+	return func(c *glaze.Context) {
+		path := c.Request.URL.Path
+		if base != "" {
+			if path != base && !strings.HasPrefix(path, base+"/") {
+				c.Next()
+				return
+			}
+			path = strings.TrimPrefix(path, base)
+		}
+
+		rt, pathParams := v.match(path)
+		if rt == nil {
+			if cfg.Strict {
+				problem(c, http.StatusNotFound, "path is not part of the API", nil)
+				return
+			}
+			c.Next()
+			return
+		}
+		op, ok := rt.operations[c.Request.Method]
+		if !ok && c.Request.Method == http.MethodHead {
+			op, ok = rt.operations[http.MethodGet]
+		}
+		if !ok {
+			if cfg.Strict {
+				allowed := make([]string, 0, len(rt.operations))
+				for m := range rt.operations {
+					allowed = append(allowed, m)
+				}
+				sort.Strings(allowed)
+				c.Writer.Header().Set("Allow", strings.Join(allowed, ", "))
+				problem(c, http.StatusMethodNotAllowed, "method is not part of the API", nil)
+				return
+			}
+			c.Next()
+			return
+		}
+
+		errs := v.checkParams(c.Request, op, pathParams)
+		status := http.StatusBadRequest
+		if op.RequestBody != nil {
+			code, bodyErrs := v.checkBody(c, op.RequestBody, cfg.MaxBodySize)
+			if code != 0 {
+				status = code
+			}
+			errs = append(errs, bodyErrs...)
+		}
+		if len(errs) > 0 {
+			problem(c, status, "request does not match the API contract", errs)
+			return
+		}
+		c.Next()
+	}
+}
+
+// problem abort the request with a problem response, the field errors in
+// its "errors" member.
+func problem(c *glaze.Context, status int, detail string, errs []FieldError) {
+	c.Abort()
+	var ext glaze.M
+	if len(errs) > 0 {
+		ext = glaze.M{"errors": errs}
+	}
+	c.Problem(status, "", "", detail, ext)
+}
+
+// checkParams validate the parameters of op.
+func (v *validator) checkParams(req *http.Request, op *operation, pathParams map[string]string) []FieldError {
+	var errs []FieldError
+	query := req.URL.Query()
+	for _, p := range op.Parameters {
+		var values []string
+		switch p.In {
+		case "path":
+			if s, ok := pathParams[p.Name]; ok {
+				values = []string{s}
+			}
+		case "query":
+			values = query[p.Name]
+		case "header":
+			values = req.Header.Values(p.Name)
+		case "cookie":
+			if ck, err := req.Cookie(p.Name); err == nil {
+				values = []string{ck.Value}
+			}
+		}
+		if len(values) == 0 {
+			if p.Required || p.In == "path" {
+				errs = append(errs, FieldError{In: p.In, Name: p.Name, Message: "is required"})
+			}
+			continue
+		}
+		if p.Schema == nil {
+			continue
+		}
+		value, err := v.coerce(p.Schema, values, p.Explode == nil || *p.Explode)
+		if err != nil {
+			errs = append(errs, FieldError{In: p.In, Name: p.Name, Message: err.Error()})
+			continue
+		}
+		for _, e := range v.validate(p.Schema, value, "") {
+			name := p.Name
+			if e.path != "" {
+				name += e.path
+			}
+			errs = append(errs, FieldError{In: p.In, Name: name, Message: e.msg})
+		}
+	}
+	return errs
+}
+
+// coerce convert the raw parameter values to the JSON value of schema.
+func (v *validator) coerce(s *Schema, values []string, explode bool) (any, error) {
+	s = v.resolve(s)
+	if s.Type.is("array") {
+		if !explode || len(values) == 1 {
+			values = strings.Split(values[0], ",")
+		}
+		items := make([]any, len(values))
+		for i, raw := range values {
+			items[i] = raw
+			if s.Items != nil {
+				item, err := v.coerce(s.Items, []string{raw}, true)
+				if err != nil {
+					return nil, err
+				}
+				items[i] = item
+			}
+		}
+		return items, nil
+	}
+
+	raw := values[0]
+	switch {
+	case s.Type.is("integer"):
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("must be an integer")
+		}
+		return float64(n), nil
+	case s.Type.is("number"):
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("must be a number")
+		}
+		return f, nil
+	case s.Type.is("boolean"):
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("must be a boolean")
+		}
+		return b, nil
+	}
+	return raw, nil
+}
+
+// checkBody validate the request body, the status is 415 for unsupported
+// content types and 413 for bodies over maxSize.
+func (v *validator) checkBody(c *glaze.Context, rb *requestBody, maxSize int64) (int, []FieldError) {
+	req := c.Request
+	if req.Body == nil || req.Body == http.NoBody || req.ContentLength == 0 {
+		if rb.Required {
+			return 0, []FieldError{{In: "body", Message: "is required"}}
+		}
+		return 0, nil
+	}
+
+	ctype, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	media, ok := findMediaType(rb.Content, ctype)
+	if !ok {
+		return http.StatusUnsupportedMediaType, []FieldError{{In: "body", Message: "unsupported content type " + strconv.Quote(ctype)}}
+	}
+	if media == nil || media.Schema == nil || !isJSON(ctype) {
+		return 0, nil
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(c.Writer, req.Body, maxSize))
+	req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	if tooLarge := (*http.MaxBytesError)(nil); errors.As(err, &tooLarge) {
+		return http.StatusRequestEntityTooLarge, []FieldError{{In: "body", Message: fmt.Sprintf("must be at most %d bytes", maxSize)}}
+	}
+	if err != nil {
+		return 0, []FieldError{{In: "body", Message: err.Error()}}
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		if rb.Required {
+			return 0, []FieldError{{In: "body", Message: "is required"}}
+		}
+		return 0, nil
+	}
+
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return 0, []FieldError{{In: "body", Message: "invalid JSON: " + err.Error()}}
+	}
+	var errs []FieldError
+	for _, e := range v.validate(media.Schema, value, "") {
+		errs = append(errs, FieldError{In: "body", Name: e.path, Message: e.msg})
+	}
+	return 0, errs
+}
+
+// findMediaType return the content entry of ctype, with "type/*" and
+// "*/*" ranges.
+func findMediaType(content map[string]*mediaType, ctype string) (*mediaType, bool) {
+	if len(content) == 0 {
+		return nil, true
+	}
+	if m, ok := content[ctype]; ok {
+		return m, true
+	}
+	if major, _, ok := strings.Cut(ctype, "/"); ok {
+		if m, ok := content[major+"/*"]; ok {
+			return m, true
+		}
+	}
+	m, ok := content["*/*"]
+	return m, ok
+}
+
+func isJSON(ctype string) bool {
+	return ctype == "application/json" || strings.HasSuffix(ctype, "+json")
+}
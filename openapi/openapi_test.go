@@ -0,0 +1,192 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package openapi
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nrhox/glaze"
+	"github.com/stretchr/testify/assert"
+)
+
+const spec = `{
+  "openapi": "3.0.3",
+  "paths": {
+    "/users/{id}": {
+      "parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "integer", "minimum": 1}}],
+      "get": {
+        "parameters": [
+          {"name": "fields", "in": "query", "schema": {"type": "array", "items": {"type": "string", "enum": ["name", "email"]}}},
+          {"name": "X-Tenant", "in": "header", "required": true, "schema": {"type": "string"}}
+        ]
+      },
+      "put": {"requestBody": {"$ref": "#/components/requestBodies/User"}}
+    },
+    "/users/me": {"get": {}}
+  },
+  "components": {
+    "schemas": {
+      "User": {
+        "type": "object",
+        "required": ["name", "email"],
+        "additionalProperties": false,
+        "properties": {
+          "name": {"type": "string", "minLength": 2},
+          "email": {"type": "string", "format": "email"},
+          "tags": {"type": "array", "maxItems": 2, "items": {"type": "string"}},
+          "manager": {"$ref": "#/components/schemas/Ref"}
+        }
+      },
+      "Ref": {"type": "object", "nullable": true, "required": ["id"], "properties": {"id": {"type": "integer"}}}
+    },
+    "requestBodies": {
+      "User": {"required": true, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/User"}}}}
+    }
+  }
+}`
+
+func TestValidator(t *testing.T) {
+	r := glaze.New()
+	r.Use(Validator(Config{Spec: []byte(spec), BasePath: "/api", Strict: true, MaxBodySize: 1024}))
+	ok := func(c *glaze.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, "ok "+string(body))
+	}
+	r.Get("/api/users/me", ok)
+	r.Get("/api/users/:id", ok)
+	r.Put("/api/users/:id", ok)
+	r.Delete("/api/users/:id", ok)
+	r.Get("/api/orders", ok)
+	r.Get("/health", ok)
+
+	type problemDoc struct {
+		Title  string       `json:"title"`
+		Status int          `json:"status"`
+		Errors []FieldError `json:"errors"`
+	}
+	do := func(method, path, body string, header ...string) (*httptest.ResponseRecorder, problemDoc) {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		for i := 0; i+1 < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		var p problemDoc
+		if w.Header().Get("Content-Type") == glaze.MIME_PROBLEM_JSON {
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+		}
+		return w, p
+	}
+
+	w, _ := do("GET", "/api/users/7?fields=name,email", "", "X-Tenant", "acme")
+	assert.Equal(t, http.StatusOK, w.Code)
+	w, _ = do("GET", "/api/users/me", "")
+	assert.Equal(t, http.StatusOK, w.Code, "static path before the template")
+	w, _ = do("GET", "/health", "")
+	assert.Equal(t, http.StatusOK, w.Code, "outside BasePath")
+
+	w, p := do("GET", "/api/users/0?fields=name,phone", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "Bad Request", p.Title)
+	assert.Equal(t, []FieldError{
+		{In: "query", Name: "fields/1", Message: "must be one of [name email]"},
+		{In: "header", Name: "X-Tenant", Message: "is required"},
+		{In: "path", Name: "id", Message: "must be at least 1"},
+	}, p.Errors)
+
+	w, p = do("GET", "/api/users/abc", "", "X-Tenant", "acme")
+	assert.Equal(t, []FieldError{{In: "path", Name: "id", Message: "must be an integer"}}, p.Errors)
+
+	// body, read again by the handler
+	w, _ = do("PUT", "/api/users/7", `{"name":"Ann","email":"ann@example.com","manager":null}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"name":"Ann"`)
+
+	w, p = do("PUT", "/api/users/7", `{"name":"A","tags":["a","b","c"],"manager":{},"admin":true}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, []FieldError{
+		{In: "body", Name: "/admin", Message: "is not allowed"},
+		{In: "body", Name: "/email", Message: "is required"},
+		{In: "body", Name: "/manager/id", Message: "is required"},
+		{In: "body", Name: "/name", Message: "must be at least 2 characters"},
+		{In: "body", Name: "/tags", Message: "must have at most 2 items"},
+	}, p.Errors)
+
+	_, p = do("PUT", "/api/users/7", "")
+	assert.Equal(t, []FieldError{{In: "body", Message: "is required"}}, p.Errors)
+
+	w, p = do("PUT", "/api/users/7", `{"name":"`+strings.Repeat("a", 2000)+`"}`)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Equal(t, []FieldError{{In: "body", Message: "must be at most 1024 bytes"}}, p.Errors)
+
+	req := httptest.NewRequest("PUT", "/api/users/7", strings.NewReader("name=Ann"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+
+	// strict mode
+	w, _ = do("DELETE", "/api/users/7", "")
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, "GET, PUT", w.Header().Get("Allow"))
+	w, _ = do("GET", "/api/orders", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, glaze.MIME_PROBLEM_JSON, w.Header().Get("Content-Type"))
+}
+
+func TestValidatorInvalidSpec(t *testing.T) {
+	assert.Panics(t, func() { Validator(Config{Spec: []byte(`{"swagger": "2.0"}`)}) })
+	assert.PanicsWithError(t, "openapi: unknown schema #/components/schemas/Missing", func() {
+		Validator(Config{Spec: []byte(`{"openapi": "3.1.0", "paths": {"/a": {"post": {"requestBody": {
+			"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Missing"}}}}}}}}`)})
+	})
+}
+
+func TestAddExamples(t *testing.T) {
+	r := glaze.New()
+	api := r.Group("/api")
+	api.Example(glaze.Example{Name: "found", Summary: "existing user", Response: glaze.M{"id": 7}}).
+		Example(glaze.Example{Name: "missing", Status: http.StatusNotFound, Response: glaze.M{"error": "not found"}}).
+		Get("/users/:id", func(c *glaze.Context) {})
+	api.Example(glaze.Example{Name: "rename", Request: glaze.M{"name": "Ann"}}).Put("/users/:id", func(c *glaze.Context) {})
+
+	out, err := AddExamples([]byte(spec), "/api", r.RoutesInfo())
+	assert.NoError(t, err)
+	type operation struct {
+		RequestBody map[string]any `json:"requestBody"`
+		Responses   map[string]struct {
+			Description string `json:"description"`
+			Content     map[string]struct {
+				Examples map[string]map[string]any `json:"examples"`
+			} `json:"content"`
+		} `json:"responses"`
+	}
+	var doc struct {
+		Paths map[string]struct {
+			Get, Put operation
+		} `json:"paths"`
+	}
+	assert.NoError(t, json.Unmarshal(out, &doc))
+
+	get := doc.Paths["/users/{id}"].Get
+	assert.Equal(t, map[string]any{"summary": "existing user", "value": map[string]any{"id": 7.0}},
+		get.Responses["200"].Content["application/json"].Examples["found"])
+	assert.Equal(t, "Not Found", get.Responses["404"].Description)
+	assert.Contains(t, get.Responses["404"].Content["application/json"].Examples, "missing")
+
+	// request bodies given by $ref are kept
+	assert.Equal(t, map[string]any{"$ref": "#/components/requestBodies/User"}, doc.Paths["/users/{id}"].Put.RequestBody)
+
+	api.Example(glaze.Example{Name: "list"}).Get("/orders", func(c *glaze.Context) {})
+	_, err = AddExamples([]byte(spec), "/api", r.RoutesInfo())
+	assert.EqualError(t, err, "openapi: GET /orders has examples and no operation")
+}
@@ -0,0 +1,316 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Schema is the subset of the OpenAPI schema object used for validation.
+type Schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 schemaType         `json:"type"`
+	Format               string             `json:"format"`
+	Enum                 []any              `json:"enum"`
+	Nullable             bool               `json:"nullable"`
+	Properties           map[string]*Schema `json:"properties"`
+	Required             []string           `json:"required"`
+	AdditionalProperties json.RawMessage    `json:"additionalProperties"`
+	Items                *Schema            `json:"items"`
+	MinItems             *int               `json:"minItems"`
+	MaxItems             *int               `json:"maxItems"`
+	MinLength            *int               `json:"minLength"`
+	MaxLength            *int               `json:"maxLength"`
+	Pattern              string             `json:"pattern"`
+	Minimum              *float64           `json:"minimum"`
+	Maximum              *float64           `json:"maximum"`
+	AllOf                []*Schema          `json:"allOf"`
+	AnyOf                []*Schema          `json:"anyOf"`
+	OneOf                []*Schema          `json:"oneOf"`
+
+	once       sync.Once
+	pattern    *regexp.Regexp
+	additional *Schema // additionalProperties schema
+	closed     bool    // additionalProperties: false
+}
+
+// schemaType is the type keyword, a string or (OpenAPI 3.1) a list.
+type schemaType []string
+
+func (t *schemaType) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*t = schemaType{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*t = many
+	return nil
+}
+
+func (t schemaType) is(name string) bool {
+	for _, s := range t {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+// compile parse the pattern and additionalProperties of s once.
+func (s *Schema) compile() {
+	s.once.Do(func() {
+		if s.Pattern != "" {
+			s.pattern = regexp.MustCompile(s.Pattern)
+		}
+		if len(s.AdditionalProperties) > 0 {
+			var allowed bool
+			if json.Unmarshal(s.AdditionalProperties, &allowed) == nil {
+				s.closed = !allowed
+			} else {
+				s.additional = &Schema{}
+				json.Unmarshal(s.AdditionalProperties, s.additional)
+			}
+		}
+	})
+}
+
+// schemaError is a value failing a schema, path is a JSON pointer.
+type schemaError struct {
+	path string
+	msg  string
+}
+
+// resolve follow the $ref of s, they are checked by load.
+func (v *validator) resolve(s *Schema) *Schema {
+	for depth := 0; s.Ref != "" && depth < 32; depth++ {
+		s = v.doc.Components.Schemas[refName(s.Ref, "schemas")]
+	}
+	return s
+}
+
+// validate check value, decoded from JSON, with schema s.
+func (v *validator) validate(s *Schema, value any, path string) []schemaError {
+	s = v.resolve(s)
+	s.compile()
+	fail := func(format string, args ...any) []schemaError {
+		return []schemaError{{path: path, msg: fmt.Sprintf(format, args...)}}
+	}
+
+	if value == nil {
+		if s.Nullable || s.Type.is("null") || len(s.Type) == 0 && len(s.AllOf)+len(s.AnyOf)+len(s.OneOf) == 0 {
+			return nil
+		}
+		return fail("must not be null")
+	}
+	if len(s.Type) > 0 && !matchType(s.Type, value) {
+		return fail("must be of type %s", strings.Join(s.Type, " or "))
+	}
+	if len(s.Enum) > 0 && !inEnum(s.Enum, value) {
+		words := make([]string, len(s.Enum))
+		for i, e := range s.Enum {
+			words[i] = fmt.Sprint(e)
+		}
+		return fail("must be one of [%s]", strings.Join(words, " "))
+	}
+
+	var errs []schemaError
+	switch value := value.(type) {
+	case string:
+		n := utf8.RuneCountInString(value)
+		if s.MinLength != nil && n < *s.MinLength {
+			errs = append(errs, fail("must be at least %d characters", *s.MinLength)...)
+		}
+		if s.MaxLength != nil && n > *s.MaxLength {
+			errs = append(errs, fail("must be at most %d characters", *s.MaxLength)...)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(value) {
+			errs = append(errs, fail("must match %s", s.Pattern)...)
+		}
+		if msg := checkFormat(s.Format, value); msg != "" {
+			errs = append(errs, fail("%s", msg)...)
+		}
+	case float64:
+		if s.Minimum != nil && value < *s.Minimum {
+			errs = append(errs, fail("must be at least %s", formatNumber(*s.Minimum))...)
+		}
+		if s.Maximum != nil && value > *s.Maximum {
+			errs = append(errs, fail("must be at most %s", formatNumber(*s.Maximum))...)
+		}
+	case []any:
+		if s.MinItems != nil && len(value) < *s.MinItems {
+			errs = append(errs, fail("must have at least %d items", *s.MinItems)...)
+		}
+		if s.MaxItems != nil && len(value) > *s.MaxItems {
+			errs = append(errs, fail("must have at most %d items", *s.MaxItems)...)
+		}
+		if s.Items != nil {
+			for i, item := range value {
+				errs = append(errs, v.validate(s.Items, item, path+"/"+strconv.Itoa(i))...)
+			}
+		}
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := value[name]; !ok {
+				errs = append(errs, schemaError{path: path + "/" + escapePointer(name), msg: "is required"})
+			}
+		}
+		for name, item := range value {
+			itemPath := path + "/" + escapePointer(name)
+			if prop, ok := s.Properties[name]; ok {
+				errs = append(errs, v.validate(prop, item, itemPath)...)
+			} else if s.closed {
+				errs = append(errs, schemaError{path: itemPath, msg: "is not allowed"})
+			} else if s.additional != nil {
+				errs = append(errs, v.validate(s.additional, item, itemPath)...)
+			}
+		}
+	}
+
+	for _, sub := range s.AllOf {
+		errs = append(errs, v.validate(sub, value, path)...)
+	}
+	if len(s.AnyOf) > 0 && v.countMatches(s.AnyOf, value, path) == 0 {
+		errs = append(errs, fail("must match at least one schema of anyOf")...)
+	}
+	if len(s.OneOf) > 0 && v.countMatches(s.OneOf, value, path) != 1 {
+		errs = append(errs, fail("must match exactly one schema of oneOf")...)
+	}
+	sortErrors(errs)
+	return errs
+}
+
+func (v *validator) countMatches(schemas []*Schema, value any, path string) int {
+	n := 0
+	for _, sub := range schemas {
+		if len(v.validate(sub, value, path)) == 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// matchType report whether value is one of the JSON types.
+func matchType(types schemaType, value any) bool {
+	for _, t := range types {
+		switch value := value.(type) {
+		case string:
+			if t == "string" {
+				return true
+			}
+		case bool:
+			if t == "boolean" {
+				return true
+			}
+		case float64:
+			if t == "number" || t == "integer" && value == math.Trunc(value) {
+				return true
+			}
+		case []any:
+			if t == "array" {
+				return true
+			}
+		case map[string]any:
+			if t == "object" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func inEnum(enum []any, value any) bool {
+	for _, e := range enum {
+		if e == value {
+			return true
+		}
+	}
+	return false
+}
+
+// checkFormat return the message of a string not matching a known format,
+// unknown formats are accepted.
+func checkFormat(format, s string) string {
+	switch format {
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, s); err != nil {
+			return "must be a RFC 3339 date-time"
+		}
+	case "date":
+		if _, err := time.Parse(time.DateOnly, s); err != nil {
+			return "must be a date (YYYY-MM-DD)"
+		}
+	case "email":
+		if addr, err := mail.ParseAddress(s); err != nil || addr.Address != s {
+			return "must be a valid email address"
+		}
+	case "uri":
+		if u, err := url.Parse(s); err != nil || u.Scheme == "" {
+			return "must be a valid URI"
+		}
+	case "uuid":
+		if !uuidPattern.MatchString(s) {
+			return "must be a UUID"
+		}
+	}
+	return ""
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// escapePointer escape a JSON pointer token (RFC 6901).
+func escapePointer(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
+
+// sortErrors order the errors by path, map iteration is random.
+func sortErrors(errs []schemaError) {
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].path < errs[j].path })
+}
+
+// checkRefs report the unknown schema references of s.
+func (v *validator) checkRefs(s *Schema, seen map[*Schema]bool) error {
+	if s == nil || seen[s] {
+		return nil
+	}
+	seen[s] = true
+	if s.Ref != "" {
+		ref, ok := v.doc.Components.Schemas[refName(s.Ref, "schemas")]
+		if !ok {
+			return fmt.Errorf("unknown schema %s", s.Ref)
+		}
+		return v.checkRefs(ref, seen)
+	}
+	s.compile()
+	subs := append([]*Schema{s.Items, s.additional}, s.AllOf...)
+	subs = append(subs, s.AnyOf...)
+	subs = append(subs, s.OneOf...)
+	for _, p := range s.Properties {
+		subs = append(subs, p)
+	}
+	for _, sub := range subs {
+		if err := v.checkRefs(sub, seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
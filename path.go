@@ -0,0 +1,241 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+const (
+	defaultMaxPathLength    = 8 << 10 // 8 KB
+	defaultMaxSegmentLength = 1 << 10 // 1 KB
+)
+
+// PathPolicy holds the rules the request path must follow before routing.
+// The router match the decoded path (URL.Path) segment by segment and
+// ignore empty segments ("/a//b" is "/a/b"). Requests breaking the policy
+// are rejected before any middleware of the route run:
+//   - 400 for control characters (null bytes...) and dot segments
+//     ("." and "..", also when percent-encoded as %2e%2e); clients resolve
+//     them, so they only come from crafted requests
+//   - 400 for encoded slashes and backslashes (%2F, %5C), which would be
+//     decoded into separators, unless AllowEncodedSlash is set
+//   - 414 for paths or segments over the length limits
+type PathPolicy struct {
+	// AllowEncodedSlash accept %2F and %5C in the path. They are still
+	// decoded before routing, "/files/a%2Fb" match "/files/:dir/:name".
+	AllowEncodedSlash bool
+
+	// MaxPathLength of the decoded path, default 8 KB.
+	MaxPathLength int
+
+	// MaxSegmentLength of each path segment, default 1 KB.
+	MaxSegmentLength int
+}
+
+// WithPathPolicy set the request path rules, see PathPolicy.
+//
+// Usage:
+//
+//	glaze.New(glaze.WithPathPolicy(glaze.PathPolicy{AllowEncodedSlash: true}))
+func WithPathPolicy(p PathPolicy) ConfigsFunc {
+	return func(e *Engine) {
+		if p.MaxPathLength <= 0 {
+			p.MaxPathLength = defaultMaxPathLength
+		}
+		if p.MaxSegmentLength <= 0 {
+			p.MaxSegmentLength = defaultMaxSegmentLength
+		}
+		e.pathPolicy = p
+	}
+}
+
+// WithRedirectTrailingSlash make the trailing slash of the registered path
+// significant. Without it "/users/" and "/users" match the same route;
+// with it the request with the other form is redirected to the registered
+// one, 301 for GET and HEAD and 308 for the other methods, keeping the
+// query. The root and the catch-all routes accept both forms.
+//
+// Usage:
+//
+//	e := glaze.New(glaze.WithRedirectTrailingSlash())
+//	e.Get("/users", listUsers) // GET /users/?page=2 → 301 /users?page=2
+func WithRedirectTrailingSlash() ConfigsFunc {
+	return func(e *Engine) {
+		e.trailingSlash = true
+	}
+}
+
+// redirectTrailingSlash redirect the request of c to the trailing slash of
+// the registered route path, and report whether it did.
+func redirectTrailingSlash(c *Context, route string) bool {
+	if route == "/" || strings.Contains(route, "/*") {
+		return false
+	}
+	reqPath := c.Request.URL.EscapedPath()
+	want := strings.HasSuffix(route, "/")
+	if strings.HasSuffix(reqPath, "/") == want {
+		return false
+	}
+
+	// a single leading slash, "//host/" would be a protocol relative URL
+	target := "/" + strings.Trim(reqPath, "/")
+	if want {
+		target += "/"
+	}
+	redirectPath(c, target)
+	return true
+}
+
+// WithRedirectFixedPath redirect the requests matching a route only once
+// their path is cleaned to the clean path: repeated slashes and dot
+// segments are removed ("/a//b/../c" → "/a/c"). With caseInsensitive the
+// static segments are matched ignoring case too, and the redirect give
+// them the case of the registered path ("/USERS/Bob" → "/users/Bob").
+// The redirect is 301 for GET and HEAD and 308 for the other methods,
+// keeping the query. Without it repeated slashes match silently and dot
+// segments are rejected by the PathPolicy.
+//
+// Usage:
+//
+//	e := glaze.New(glaze.WithRedirectFixedPath(true))
+func WithRedirectFixedPath(caseInsensitive bool) ConfigsFunc {
+	return func(e *Engine) {
+		e.fixedPath = true
+		e.fixedPathCase = caseInsensitive
+	}
+}
+
+// redirectFixedPath redirect the request of c to its fixed path when it
+// match a route there, and report whether it did.
+func (e *Engine) redirectFixedPath(c *Context) bool {
+	reqPath := c.Request.URL.Path
+	fixed := fixPath(reqPath)
+	if fixed == reqPath {
+		if !e.fixedPathCase {
+			return false
+		}
+		if n := e.findRoute(c.Request.Method, reqPath, nil); n != nil && n.handlers != nil {
+			return false
+		}
+	}
+
+	if n := e.findRoute(c.Request.Method, fixed, nil); n == nil || n.handlers == nil {
+		if !e.fixedPathCase {
+			return false
+		}
+		root := e.trees[c.Request.Method]
+		if root == nil {
+			return false
+		}
+		out, ok := root.findCaseInsensitive(cleanPath(fixed), nil)
+		if !ok {
+			return false
+		}
+		if fixed = "/" + string(out); strings.HasSuffix(reqPath, "/") && fixed != "/" {
+			fixed += "/"
+		}
+	}
+	if fixed == reqPath || e.pathPolicy.checkPath(fixed, c.Request.URL.RawPath) != 0 {
+		return false
+	}
+	redirectPath(c, (&url.URL{Path: fixed}).EscapedPath())
+	return true
+}
+
+// fixPath return p without repeated slashes and dot segments, the
+// trailing slash is kept. Clean paths are returned without allocation.
+func fixPath(p string) string {
+	if p == "" || p[0] != '/' {
+		p = "/" + p
+	}
+	fixed := path.Clean(p)
+	if strings.HasSuffix(p, "/") && fixed != "/" {
+		fixed += "/"
+	}
+	if fixed == p {
+		return p
+	}
+	return fixed
+}
+
+// redirectPath redirect the request of c to the escaped path target with
+// the same query, 301 for GET and HEAD and 308 for the other methods.
+func redirectPath(c *Context, target string) {
+	if c.Request.URL.RawQuery != "" {
+		target += "?" + c.Request.URL.RawQuery
+	}
+	status := http.StatusMovedPermanently
+	if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+		status = http.StatusPermanentRedirect
+	}
+	http.Redirect(c.Writer, c.Request, target, status)
+}
+
+var defaultPathPolicy = PathPolicy{
+	MaxPathLength:    defaultMaxPathLength,
+	MaxSegmentLength: defaultMaxSegmentLength,
+}
+
+// check return the status rejecting req, or 0 when the path is valid.
+// It does not allocate.
+func (p *PathPolicy) check(req *http.Request) int {
+	return p.checkPath(req.URL.Path, req.URL.RawPath)
+}
+
+// checkPath is check for the decoded path and its raw form.
+func (p *PathPolicy) checkPath(path, rawPath string) int {
+	if len(path) > p.MaxPathLength {
+		return http.StatusRequestURITooLong
+	}
+	if !p.AllowEncodedSlash && hasEncodedSlash(rawPath) {
+		return http.StatusBadRequest
+	}
+
+	start := 0
+	for i := 0; i <= len(path); i++ {
+		if i < len(path) {
+			if b := path[i]; b < 0x20 || b == 0x7f || b == '\\' && !p.AllowEncodedSlash {
+				return http.StatusBadRequest
+			}
+			if path[i] != '/' {
+				continue
+			}
+		}
+		segment := path[start:i]
+		if segment == "." || segment == ".." {
+			return http.StatusBadRequest
+		}
+		if len(segment) > p.MaxSegmentLength {
+			return http.StatusRequestURITooLong
+		}
+		start = i + 1
+	}
+	return 0
+}
+
+// hasEncodedSlash report whether raw contain %2F, in any case. Backslashes
+// are checked in the decoded path: RawPath is empty when "%5C" is the
+// canonical escaping.
+func hasEncodedSlash(raw string) bool {
+	for i := 0; i+2 < len(raw); i++ {
+		if raw[i] != '%' {
+			continue
+		}
+		if raw[i+1] == '2' && raw[i+2]|0x20 == 'f' {
+			return true
+		}
+	}
+	return false
+}
+
+// safeFileName report whether name, a cleaned static file path, can be
+// opened: no null byte (rejected by most filesystems with an error) and no
+// backslash, a separator for http.Dir on Windows.
+func safeFileName(name string) bool {
+	return !strings.ContainsAny(name, "\x00\\")
+}
@@ -0,0 +1,76 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"sort"
+)
+
+// Phase is the moment of the request where a phase middleware run.
+type Phase int
+
+const (
+	// PhasePreRouting run before the route lookup, for every request
+	// (including 404). It can rewrite c.Request.URL.Path before routing.
+	PhasePreRouting Phase = iota
+
+	// PhasePostRouting run after a route matched, before the group
+	// and route middleware. c.FullPath and c.Param are available.
+	PhasePostRouting
+
+	// PhasePostResponse run after the handler chain is done, even if it
+	// was aborted. Handlers run in sequence, Next and Abort have no effect.
+	PhasePostResponse
+)
+
+// phaseHandler is a registered phase middleware.
+type phaseHandler struct {
+	priority int
+	order    int // registration order, keep sort stable
+	handler  HandlerFunc
+}
+
+// UsePhase register middleware in a phase with a priority.
+// Inside a phase, lower priority run first (outermost), handlers with the
+// same priority keep the registration order. This make the order independent
+// of which package call Use first:
+//
+//	e.UsePhase(glaze.PhasePreRouting, 10, tracing.Middleware())
+//	e.UsePhase(glaze.PhasePreRouting, 20, glaze.Logger()) // always inside tracing
+func (e *Engine) UsePhase(phase Phase, priority int, handlers ...HandlerFunc) *Engine {
+	if phase < PhasePreRouting || phase > PhasePostResponse {
+		panic("invalid middleware phase")
+	}
+	for _, h := range handlers {
+		e.phases[phase] = append(e.phases[phase], phaseHandler{
+			priority: priority,
+			order:    len(e.phases[phase]),
+			handler:  h,
+		})
+	}
+
+	list := e.phases[phase]
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].priority == list[j].priority {
+			return list[i].order < list[j].order
+		}
+		return list[i].priority < list[j].priority
+	})
+
+	chain := make(HandlersChain, 0, len(list)+1)
+	for _, ph := range list {
+		chain = append(chain, ph.handler)
+	}
+
+	switch phase {
+	case PhasePreRouting:
+		// routing itself is the last step of the pre-routing chain
+		e.preRouting = append(chain, e.dispatch)
+	case PhasePostRouting:
+		e.postRouting = chain
+	case PhasePostResponse:
+		e.postResponse = chain
+	}
+	return e
+}
@@ -0,0 +1,46 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Poll answer a long poll: it block until source send a value, rendered
+// with render, or wait expire and respond 204 No Content. A closed source
+// and the server shutting down respond 204 at once, so the client poll
+// again (another instance). When the client disconnect or ctx is done
+// nothing is written. It report whether a value was rendered.
+//
+// Usage:
+//
+//	r.Get("/messages", func(c *glaze.Context) {
+//	    glaze.Poll(c.Request.Context(), c, 30*time.Second, hub.Subscribe(c.Query("room")), func(m Message) {
+//	        c.JSON(http.StatusOK, m)
+//	    })
+//	})
+func Poll[T any](ctx context.Context, c *Context, wait time.Duration, source <-chan T, render func(T)) bool {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case v, ok := <-source:
+		if ok {
+			render(v)
+			return true
+		}
+	case <-timer.C:
+	case <-c.Done():
+	case <-ctx.Done():
+		c.Abort()
+		return false
+	case <-c.Request.Context().Done():
+		c.Abort()
+		return false
+	}
+	c.Writer.WriteHeader(http.StatusNoContent)
+	return false
+}
@@ -0,0 +1,174 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"syscall"
+	"time"
+)
+
+// envPreforkChild mark the worker processes started by RunPrefork.
+const envPreforkChild = "GLAZE_PREFORK_CHILD"
+
+// PreforkConfig holds the configuration of RunPrefork.
+type PreforkConfig struct {
+	// Children is the number of worker processes, default runtime.NumCPU().
+	Children int
+
+	// RestartDelay is the wait before a crashed child is started again, default 1s.
+	RestartDelay time.Duration
+}
+
+// IsPreforkChild report whether the process is a worker started by RunPrefork.
+// Use it to run one time jobs (migrations, cron) only in the master.
+func IsPreforkChild() bool {
+	return os.Getenv(envPreforkChild) == "1"
+}
+
+// RunPrefork serve addr from several processes sharing the port with
+// SO_REUSEPORT, each one with GOMAXPROCS=1. The master process start the
+// children (the same executable and arguments), restart the ones that crash
+// and forward SIGINT/SIGTERM to them for a graceful shutdown.
+// It is only useful when a single accept loop is the bottleneck, the
+// children do not share memory (caches, rate limit counters).
+// Not supported on Windows.
+//
+// Example:
+//
+//	e.RunPrefork(":8080")
+func (e *Engine) RunPrefork(addr string, cfg ...PreforkConfig) error {
+	if addr == "" {
+		addr = ":http"
+	}
+	if IsPreforkChild() {
+		return e.runPreforkChild(addr)
+	}
+
+	var conf PreforkConfig
+	if len(cfg) > 0 {
+		conf = cfg[0]
+	}
+	if conf.Children <= 0 {
+		conf.Children = runtime.NumCPU()
+	}
+	if conf.RestartDelay <= 0 {
+		conf.RestartDelay = time.Second
+	}
+	return e.runPreforkMaster(addr, conf)
+}
+
+// runPreforkChild serve until a signal or the master exit.
+func (e *Engine) runPreforkChild(addr string) error {
+	runtime.GOMAXPROCS(1)
+	listener, err := listenReusePort(addr)
+	if err != nil {
+		return err
+	}
+	srv := e.newServer(addr)
+
+	// stop when the master is gone, the child is adopted by another process
+	master := os.Getppid()
+	go func() {
+		for range time.Tick(time.Second) {
+			if os.Getppid() != master {
+				srv.Close()
+				return
+			}
+		}
+	}()
+
+	return e.graceful(srv, func() error {
+		return e.start(srv, listener, srv.Serve)
+	})
+}
+
+// runPreforkMaster start and supervise the children.
+func (e *Engine) runPreforkMaster(addr string, conf PreforkConfig) error {
+	// fail early when the port is used or SO_REUSEPORT not supported
+	listener, err := listenReusePort(addr)
+	if err != nil {
+		return err
+	}
+	listener.Close()
+
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	e.debugPrintRoutes()
+	e.debugPrintListen("listen on %s (prefork, %d children)\n", addr, conf.Children)
+
+	exits := make(chan *exec.Cmd)
+	children := make(map[*exec.Cmd]struct{}, conf.Children)
+	spawn := func() error {
+		cmd := exec.Command(exe, os.Args[1:]...)
+		cmd.Env = append(os.Environ(), envPreforkChild+"=1")
+		cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+		if err := cmd.Start(); err != nil {
+			return err
+		}
+		children[cmd] = struct{}{}
+		go func() {
+			cmd.Wait()
+			exits <- cmd
+		}()
+		return nil
+	}
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(quit)
+
+	stopping := false
+	stop := func() {
+		stopping = true
+		for cmd := range children {
+			cmd.Process.Signal(syscall.SIGTERM)
+		}
+	}
+
+	for i := 0; i < conf.Children; i++ {
+		if err := spawn(); err != nil {
+			stop()
+			return err
+		}
+	}
+
+	restart := make(chan struct{})
+	for {
+		select {
+		case cmd := <-exits:
+			delete(children, cmd)
+			if stopping {
+				if len(children) == 0 {
+					return nil
+				}
+				continue
+			}
+			e.logf(LogLevelError, "prefork: child %d exited (%s), restarting\n", cmd.Process.Pid, cmd.ProcessState)
+			go func() {
+				time.Sleep(conf.RestartDelay)
+				restart <- struct{}{}
+			}()
+		case <-restart:
+			if stopping {
+				continue
+			}
+			if err := spawn(); err != nil {
+				e.logf(LogLevelError, "prefork: %s\n", err)
+			}
+		case <-quit:
+			e.logf(LogLevelInfo, "Shutdown Server\n")
+			if len(children) == 0 {
+				return nil
+			}
+			stop()
+		}
+	}
+}
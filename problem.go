@@ -0,0 +1,109 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Problem is an RFC 7807 problem document. Extensions are added as
+// top level members, they can not replace the standard ones.
+type Problem struct {
+	Type       string // URI of the problem type, "about:blank" when empty
+	Title      string // summary of the type, default to the status text
+	Status     int
+	Detail     string // explanation of this occurrence
+	Instance   string // URI of this occurrence, the request path for handler errors
+	Extensions M
+}
+
+// MarshalJSON flatten the extensions into the document.
+func (p Problem) MarshalJSON() ([]byte, error) {
+	doc := make(M, len(p.Extensions)+5)
+	for k, v := range p.Extensions {
+		doc[k] = v
+	}
+	if p.Type != "" {
+		doc["type"] = p.Type
+	}
+	doc["title"] = p.Title
+	doc["status"] = p.Status
+	if p.Detail != "" {
+		doc["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		doc["instance"] = p.Instance
+	}
+	return json.Marshal(doc)
+}
+
+// WithProblemDetails render the handler errors (HTTPError, MapError without
+// body, bind and validation errors, unmapped errors) as
+// application/problem+json documents instead of {"error": message}.
+// Bodies given to MapError are sent as is, unless they are a Problem.
+//
+// Usage:
+//
+//	e := glaze.New(glaze.WithProblemDetails())
+func WithProblemDetails() ConfigsFunc {
+	return func(e *Engine) {
+		e.problems = true
+	}
+}
+
+// Problem send an RFC 7807 problem document, typ and title can be empty,
+// extensions can be nil. The engine JSON Transform is not applied.
+//
+// Usage:
+//
+//	c.Problem(http.StatusForbidden, "https://example.com/probs/out-of-credit",
+//	    "You do not have enough credit", "Your balance is 30, but that costs 50",
+//	    glaze.M{"balance": 30})
+func (c *Context) Problem(status int, typ, title, detail string, extensions M) {
+	c.renderProblem(&Problem{Type: typ, Title: title, Status: status, Detail: detail, Extensions: extensions})
+}
+
+// renderProblem write p with the problem content type.
+func (c *Context) renderProblem(p *Problem) {
+	if p.Title == "" {
+		p.Title = http.StatusText(p.Status)
+	}
+	c.Writer.Header().Set("Content-Type", MIME_PROBLEM_JSON)
+	c.Writer.WriteHeader(p.Status)
+	encoder := json.NewEncoder(c.Writer)
+	encoder.SetEscapeHTML(c.engine.json.EscapeHTML)
+	encoder.Encode(p)
+}
+
+// problemOf convert the built-in error bodies to a problem, nil for the
+// custom bodies of MapError.
+func problemOf(status int, body any) *Problem {
+	switch b := body.(type) {
+	case Problem:
+		if b.Status == 0 {
+			b.Status = status
+		}
+		return &b
+	case *Problem:
+		return problemOf(status, *b)
+	case *HTTPError:
+		p := &Problem{Status: status, Detail: b.Message}
+		if b.Code != "" || b.Details != nil {
+			p.Extensions = M{}
+			if b.Code != "" {
+				p.Extensions["code"] = b.Code
+			}
+			if b.Details != nil {
+				p.Extensions["details"] = b.Details
+			}
+		}
+		return p
+	case M:
+		if msg, ok := b["error"].(string); ok && len(b) == 1 {
+			return &Problem{Status: status, Detail: msg}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrNoProvider is returned by Resolve for keys without a provider.
+var ErrNoProvider = errors.New("glaze: no provider")
+
+// provider build the value of a key, once for singletons or once per request.
+type provider struct {
+	scoped    func(c *Context) (any, error)
+	singleton func() (any, error)
+
+	mu    sync.Mutex
+	done  bool
+	value any
+}
+
+// Provide register a singleton constructor for key, called on the first
+// Resolve of the key. A failed construction is retried on the next Resolve.
+// Keys are usually unexported types, like context keys. It panic when
+// constructor is nil or key has a provider already.
+//
+// Usage:
+//
+//	type dbKey struct{}
+//
+//	e.Provide(dbKey{}, func() (any, error) {
+//	    return sql.Open("postgres", dsn)
+//	})
+//
+//	db, err := glaze.Resolve[*sql.DB](c, dbKey{})
+func (e *Engine) Provide(key any, constructor func() (any, error)) *Engine {
+	if constructor == nil {
+		panic(fmt.Sprintf("glaze: nil constructor for %v", key))
+	}
+	e.addProvider(key, &provider{singleton: constructor})
+	return e
+}
+
+// ProvideScoped register a per request constructor for key, called on the
+// first Resolve of the key in a request, the value is reused until the end
+// of the request. It panic like Provide.
+//
+// Usage:
+//
+//	e.ProvideScoped(userRepoKey{}, func(c *glaze.Context) (any, error) {
+//	    db, err := glaze.Resolve[*sql.DB](c, dbKey{})
+//	    return &UserRepo{db: db, tenant: c.GetHeader("X-Tenant")}, err
+//	})
+func (e *Engine) ProvideScoped(key any, constructor func(c *Context) (any, error)) *Engine {
+	if constructor == nil {
+		panic(fmt.Sprintf("glaze: nil constructor for %v", key))
+	}
+	e.addProvider(key, &provider{scoped: constructor})
+	return e
+}
+
+func (e *Engine) addProvider(key any, p *provider) {
+	if e.providers == nil {
+		e.providers = make(map[any]*provider)
+	}
+	if _, exists := e.providers[key]; exists {
+		panic(fmt.Sprintf("glaze: duplicate provider for %v", key))
+	}
+	e.providers[key] = p
+}
+
+// Resolve return the value of key built by its provider.
+func (c *Context) Resolve(key any) (any, error) {
+	p := c.engine.providers[key]
+	if p == nil {
+		return nil, fmt.Errorf("%w for %v", ErrNoProvider, key)
+	}
+	if p.singleton != nil {
+		return p.get()
+	}
+
+	if v, ok := c.scoped[key]; ok {
+		return v, nil
+	}
+	v, err := p.scoped(c)
+	if err != nil {
+		return nil, err
+	}
+	if c.scoped == nil {
+		c.scoped = make(map[any]any)
+	}
+	c.scoped[key] = v
+	return v, nil
+}
+
+// get return the singleton value, building it if needed.
+func (p *provider) get() (any, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.done {
+		v, err := p.singleton()
+		if err != nil {
+			return nil, err
+		}
+		p.value, p.done = v, true
+	}
+	return p.value, nil
+}
+
+// Resolve return the value of key as T, see Context.Resolve.
+//
+// Usage:
+//
+//	repo, err := glaze.Resolve[*UserRepo](c, userRepoKey{})
+func Resolve[T any](c *Context, key any) (T, error) {
+	var zero T
+	v, err := c.Resolve(key)
+	if err != nil {
+		return zero, err
+	}
+	t, ok := v.(T)
+	if !ok {
+		return zero, fmt.Errorf("glaze: provider for %v return %T, not %T", key, v, zero)
+	}
+	return t, nil
+}
@@ -0,0 +1,155 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ProxyOptions holds the configuration of Context.Proxy and Route.Proxy.
+type ProxyOptions struct {
+	// StripPrefix is removed from the request path before it is joined
+	// to the target path. Route.Proxy set it to the mount path.
+	StripPrefix string
+
+	// PreserveHost keep the Host header of the incoming request,
+	// default send the target host.
+	PreserveHost bool
+
+	// Rewrite change the outgoing request after the default rewriting
+	// (target URL, X-Forwarded-* headers), to set or remove headers.
+	Rewrite func(c *Context, out *http.Request)
+
+	// ModifyResponse change the response of the target before it is
+	// copied, an error is handled by ErrorHandler.
+	ModifyResponse func(c *Context, resp *http.Response) error
+
+	// ErrorHandler write the response when the target can not be
+	// reached. Default 504 on timeouts and 502 otherwise.
+	ErrorHandler func(c *Context, err error)
+
+	// Transport used to reach the target, default http.DefaultTransport.
+	Transport http.RoundTripper
+
+	// FlushInterval of the response copy, negative flush after every
+	// write. Streaming responses (text/event-stream) are always flushed.
+	FlushInterval time.Duration
+}
+
+// Proxy forward the request to target with httputil.ReverseProxy and
+// copy the response. The path of the request is joined to the path of
+// target, the query is merged, and X-Forwarded-For, X-Forwarded-Host and
+// X-Forwarded-Proto are set from ClientIP, Host and Scheme.
+//
+// Usage:
+//
+//	backend, _ := url.Parse("http://users.internal:8080/v2")
+//	r.Get("/users/:id", authorize, func(c *glaze.Context) {
+//	    c.Proxy(backend) // GET /users/7 → http://users.internal:8080/v2/users/7
+//	})
+func (c *Context) Proxy(target *url.URL, opts ...ProxyOptions) {
+	var opt ProxyOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	newReverseProxy(c, target, opt).ServeHTTP(c.Writer, c.Request)
+}
+
+// Proxy forward every request under prefix, of any method, to target, with
+// the prefix stripped. Routes registered under prefix are served first.
+//
+// Usage:
+//
+//	billing, _ := url.Parse("http://billing.internal")
+//	api := r.Group("/api", auth)
+//	api.Proxy("/billing", billing) // /api/billing/invoices → http://billing.internal/invoices
+func (r *Route) Proxy(prefix string, target *url.URL, opts ...ProxyOptions) Routes {
+	var opt ProxyOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	absolutePath := r.jointAbsolutePath(prefix)
+	if opt.StripPrefix == "" {
+		opt.StripPrefix = absolutePath
+	}
+	r.engine.addMount(absolutePath, nil, r.meta, r.joinHandler(HandlersChain{func(c *Context) {
+		c.Proxy(target, opt)
+	}}))
+	return r.engineInfo()
+}
+
+func newReverseProxy(c *Context, target *url.URL, opt ProxyOptions) *httputil.ReverseProxy {
+	client := c.ClientIP()
+	host := c.Host()
+	scheme := c.Scheme()
+
+	return &httputil.ReverseProxy{
+		Transport:     opt.Transport,
+		FlushInterval: opt.FlushInterval,
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			if opt.StripPrefix != "" {
+				stripPath(pr.Out.URL, strings.TrimSuffix(opt.StripPrefix, "/"))
+			}
+			pr.SetURL(target)
+			if opt.PreserveHost {
+				pr.Out.Host = pr.In.Host
+			}
+
+			pr.Out.Header.Del("X-Forwarded-For")
+			if client != "" {
+				pr.Out.Header.Set("X-Forwarded-For", client)
+			}
+			pr.Out.Header.Set("X-Forwarded-Host", host)
+			pr.Out.Header.Set("X-Forwarded-Proto", scheme)
+			if opt.Rewrite != nil {
+				opt.Rewrite(c, pr.Out)
+			}
+		},
+		ModifyResponse: func(resp *http.Response) error {
+			if opt.ModifyResponse != nil {
+				return opt.ModifyResponse(c, resp)
+			}
+			return nil
+		},
+		ErrorHandler: func(w http.ResponseWriter, req *http.Request, err error) {
+			if opt.ErrorHandler != nil {
+				opt.ErrorHandler(c, err)
+				return
+			}
+			if errors.Is(err, context.Canceled) {
+				return // client gone
+			}
+			var netErr net.Error
+			if errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr) && netErr.Timeout() {
+				c.defaultResponse(http.StatusGatewayTimeout)
+				return
+			}
+			c.defaultResponse(http.StatusBadGateway)
+		},
+	}
+}
+
+// stripPath remove prefix from the path of u, keeping the leading slash.
+func stripPath(u *url.URL, prefix string) {
+	if prefix == "" {
+		return
+	}
+	p, ok := strings.CutPrefix(u.Path, prefix)
+	if !ok || p != "" && p[0] != '/' {
+		return // not under prefix, /api/billingx for /api/billing
+	}
+	u.Path = "/" + strings.TrimPrefix(p, "/")
+	if rp, ok := strings.CutPrefix(u.RawPath, prefix); ok {
+		u.RawPath = "/" + strings.TrimPrefix(rp, "/")
+	} else {
+		u.RawPath = ""
+	}
+}
@@ -4,11 +4,24 @@
 package glaze
 
 import (
+	"bytes"
 	"fmt"
 	"net/http"
+	"net/url"
+	"reflect"
 	"runtime/debug"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
+// RecoveryFunc is called by the recovery middleware with the value
+// passed to panic, it is responsible to write the error response.
+type RecoveryFunc func(c *Context, err any)
+
 // Recovery returns a middleware that recovers from panics
 // during request handling. It prevents the server from crashing
 // and instead logs the panic stack trace, then responds with
@@ -18,7 +31,7 @@ import (
 //
 //	r := glaze.New()
 //	r.Use(glaze.Recovery())
-//	r.GET("/", func(c *glaze.Context) {
+//	r.Get("/", func(c *glaze.Context) {
 //	    panic("something went wrong")
 //	})
 //
@@ -27,7 +40,112 @@ import (
 // 2. Log the panic message and stack trace to the engine's writer.
 // 3. Send a 500 response with "Internal Server Error".
 func Recovery() HandlerFunc {
-	return func(c *Context) {
+	return RecoveryWithHandler(defaultRecoveryHandler)
+}
+
+// RecoveryWithHandler returns a recovery middleware that call handle
+// to render the response, instead of the fixed plain text 500.
+// The panic is still logged with the stack trace before handle is called.
+//
+// Usage:
+//
+//	r.Use(glaze.RecoveryWithHandler(func(c *glaze.Context, err any) {
+//	    sentry.CurrentHub().Recover(err)
+//	    c.JSON(500, glaze.M{"error": "internal error"})
+//	}))
+func RecoveryWithHandler(handle RecoveryFunc) HandlerFunc {
+	return RecoveryWithConfig(RecoveryConfig{Handler: handle})
+}
+
+// RecoveryConfig holds the configuration of the recovery middleware.
+type RecoveryConfig struct {
+	// Handler render the response after a panic, default is plain text 500.
+	Handler RecoveryFunc
+
+	// Report receive the structured panic record,
+	// default write it to the engine's error output.
+	// The engine Reporter, if any, is called as well.
+	Report func(c *Context, report PanicReport)
+
+	// RedactHeaders are request headers replaced by "[REDACTED]" in the report.
+	// Default is DefaultRedactHeaders.
+	RedactHeaders []string
+
+	// RedactQuery are query parameters whose value is replaced by
+	// "[REDACTED]" in the report URI. Default (nil) redact every value,
+	// an empty list keep them all.
+	RedactQuery []string
+
+	// RePanic panic again after the response is written when the
+	// mode is not ReleaseMode, so tests and debug sessions fail loudly.
+	RePanic bool
+}
+
+// DefaultRedactHeaders are headers never written into panic reports.
+var DefaultRedactHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Api-Key",
+	"X-Auth-Token",
+}
+
+// PanicReport is the structured record of a recovered panic.
+type PanicReport struct {
+	Time      time.Time   // when the panic was recovered
+	Error     any         // value passed to panic
+	Method    string      // request method
+	URI       string      // request URI as sent by the client, query values redacted
+	Proto     string      // protocol version
+	Header    http.Header // request headers, secrets redacted
+	RequestID string      // id of the RequestID middleware, if used
+	Route     string      // matched route, "/users/:id"
+	ClientIP  string      // client address, see Context.ClientIP
+	Goroutine uint64      // id of the panicking goroutine
+	Stack     []byte      // stack trace
+}
+
+// String format the report as a readable multi-line block.
+func (p PanicReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[PANIC] %s %v\n", p.Time.Format(time.RFC3339), p.Error)
+	fmt.Fprintf(&b, "%s %s %s (goroutine %d)\n", p.Method, p.URI, p.Proto, p.Goroutine)
+	if p.RequestID != "" {
+		fmt.Fprintf(&b, "request_id=%s ", p.RequestID)
+	}
+	if p.Route != "" {
+		fmt.Fprintf(&b, "route=%s ", p.Route)
+	}
+	fmt.Fprintf(&b, "client_ip=%s\n", p.ClientIP)
+
+	keys := make([]string, 0, len(p.Header))
+	for k := range p.Header {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%s: %s\n", k, strings.Join(p.Header[k], ", "))
+	}
+	b.Write(p.Stack)
+	return b.String()
+}
+
+// RecoveryWithConfig returns a recovery middleware with custom config.
+func RecoveryWithConfig(cfg RecoveryConfig) HandlerFunc {
+	if cfg.Handler == nil {
+		cfg.Handler = defaultRecoveryHandler
+	}
+	if cfg.Report == nil {
+		cfg.Report = func(c *Context, report PanicReport) {
+			c.engine.logf(LogLevelError, "%s\n", report.String())
+		}
+	}
+	if cfg.RedactHeaders == nil {
+		cfg.RedactHeaders = DefaultRedactHeaders
+	}
+
+	return MarkRecovery(func(c *Context) {
 		defer func() {
 			if r := recover(); r != nil {
 				// stop next middleware execution
@@ -36,20 +154,108 @@ func Recovery() HandlerFunc {
 				// capture stack trace for debugging
 				stack := debug.Stack()
 
-				// log panic and stack trace
-				fmt.Fprintf(c.engine.writer, "[PANIC] %v\n%s\n", r, stack)
+				// report panic with request information
+				report := newPanicReport(c, r, stack, cfg.RedactHeaders, cfg.RedactQuery)
+				cfg.Report(c, report)
+				if c.engine.reporter != nil {
+					c.engine.reporter.ReportPanic(c, report)
+				}
+
+				// let the handler render the response
+				cfg.Handler(c, r)
 
-				// send 500 response to client
-				h := c.Writer.Header()
-				if h.Get("Content-Type") == "" {
-					h.Set("Content-Type", textPlainContentType)
+				if cfg.RePanic && Mode() != ReleaseMode {
+					panic(r)
 				}
-				c.Writer.WriteHeader(http.StatusInternalServerError)
-				_, _ = c.Writer.Write([]byte("Internal Server Error"))
 			}
 		}()
 
 		// continue executing next handlers if no panic
 		c.Next()
+	})
+}
+
+// recoveryHandlers are the code pointers of the middleware given to
+// MarkRecovery.
+var recoveryHandlers sync.Map
+
+// MarkRecovery declare h as a middleware recovering panics and return it,
+// so Engine.Lint does not warn about the routes using it. The handlers of
+// Recovery and its variants are marked already.
+//
+// Usage:
+//
+//	func SentryRecovery() glaze.HandlerFunc {
+//	    return glaze.MarkRecovery(func(c *glaze.Context) { ... })
+//	}
+func MarkRecovery(h HandlerFunc) HandlerFunc {
+	recoveryHandlers.Store(reflect.ValueOf(h).Pointer(), struct{}{})
+	return h
+}
+
+// isRecovery report whether h was marked with MarkRecovery. Closures of
+// a function literal share its code pointer, marking one mark them all.
+func isRecovery(h HandlerFunc) bool {
+	_, ok := recoveryHandlers.Load(reflect.ValueOf(h).Pointer())
+	return ok
+}
+
+// newPanicReport build the report of a panic during the request of c.
+// The query values are all redacted when redactQuery is nil.
+func newPanicReport(c *Context, err any, stack []byte, redact, redactQuery []string) PanicReport {
+	req := c.Request
+	header := req.Header.Clone()
+	for _, k := range redact {
+		if _, ok := header[http.CanonicalHeaderKey(k)]; ok {
+			header.Set(k, "[REDACTED]")
+		}
+	}
+	return PanicReport{
+		Time:      time.Now(),
+		Error:     err,
+		Method:    req.Method,
+		URI:       redactURI(req.RequestURI, redactQuery),
+		Proto:     req.Proto,
+		Header:    header,
+		RequestID: c.RequestID(),
+		Route:     c.FullPath(),
+		ClientIP:  c.ClientIP(),
+		Goroutine: goroutineID(stack),
+		Stack:     stack,
+	}
+}
+
+// redactURI replace the values of the query parameters names of uri by
+// "[REDACTED]", every value when names is nil.
+func redactURI(uri string, names []string) string {
+	path, query, ok := strings.Cut(uri, "?")
+	if !ok || query == "" {
+		return uri
+	}
+	params := strings.Split(query, "&")
+	for i, p := range params {
+		name, _, _ := strings.Cut(p, "=")
+		key, err := url.QueryUnescape(name)
+		if err != nil {
+			key = name
+		}
+		if p != "" && (names == nil || slices.Contains(names, key)) {
+			params[i] = name + "=[REDACTED]"
+		}
 	}
+	return path + "?" + strings.Join(params, "&")
+}
+
+// goroutineID parse the id from the first stack line "goroutine 12 [running]:".
+func goroutineID(stack []byte) uint64 {
+	line, _, _ := bytes.Cut(stack, []byte("\n"))
+	line = bytes.TrimPrefix(line, []byte("goroutine "))
+	id, _, _ := bytes.Cut(line, []byte(" "))
+	n, _ := strconv.ParseUint(string(id), 10, 64)
+	return n
+}
+
+// defaultRecoveryHandler send the default 500 response, see ErrorTemplate.
+func defaultRecoveryHandler(c *Context, _ any) {
+	c.defaultResponse(http.StatusInternalServerError)
 }
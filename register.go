@@ -0,0 +1,139 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+)
+
+// RouteSpec is a route declared as data, registered with Engine.Register.
+type RouteSpec struct {
+	Method  string
+	Path    string
+	Handler HandlerFunc
+
+	// Middleware are the names given with RegisterMiddleware, run in
+	// order after the engine middleware.
+	Middleware []string
+
+	// Meta is the metadata of the route, like Route.Meta.
+	Meta map[string]any
+}
+
+// RegisterMiddleware name the middleware h for the RouteSpec tables, a
+// name registered again is replaced.
+//
+// Usage:
+//
+//	e.RegisterMiddleware("auth", auth.RequireLogin("/login"))
+func (e *Engine) RegisterMiddleware(name string, h HandlerFunc) *Engine {
+	if e.middleware == nil {
+		e.middleware = make(map[string]HandlerFunc)
+	}
+	e.middleware[name] = h
+	return e
+}
+
+// Register add the routes of a declarative table, written by hand or
+// generated. The whole table is checked first: invalid method or path,
+// missing handler, unknown middleware name, route already registered and
+// route conflicting with another one, tried on a scratch tree. All the
+// problems are returned together and no route is added then, so a broken
+// table stop the startup.
+//
+// Usage:
+//
+//	e.RegisterMiddleware("auth", requireLogin)
+//	err := e.Register([]glaze.RouteSpec{
+//	    {Method: "GET", Path: "/users", Handler: listUsers},
+//	    {Method: "POST", Path: "/users", Handler: createUser, Middleware: []string{"auth"}},
+//	})
+func (e *Engine) Register(specs []RouteSpec) error {
+	var errs []error
+	seen := make(map[string]struct{}, len(e.routeList)+len(specs))
+	scratch := &Engine{trees: make(map[string]*node), namedRoutes: maps.Clone(e.namedRoutes)}
+	for _, ri := range e.routeList {
+		seen[routeKey(ri.Method, ri.Path)] = struct{}{}
+		if ri.Method != "*" { // mounts are not in the trees
+			scratch.addRoute(ri.Method, ri.Path, nil, noRouteHandler)
+		}
+	}
+	for i, spec := range specs {
+		before := len(errs)
+		fail := func(format string, args ...any) {
+			errs = append(errs, fmt.Errorf("glaze: route spec %d (%s %s): %s", i, spec.Method, spec.Path, fmt.Sprintf(format, args...)))
+		}
+		if !regexMethodLetter.MatchString(spec.Method) {
+			fail("invalid method")
+		}
+		if !strings.HasPrefix(spec.Path, "/") {
+			fail("path must start with /")
+		}
+		if spec.Handler == nil {
+			fail("no handler")
+		}
+		for _, name := range spec.Middleware {
+			if _, ok := e.middleware[name]; !ok {
+				fail("unknown middleware %q", name)
+			}
+		}
+		key := routeKey(spec.Method, e.jointAbsolutePath(spec.Path))
+		if _, dup := seen[key]; dup {
+			fail("route already registered")
+		}
+		seen[key] = struct{}{}
+		if len(errs) == before {
+			if err := scratch.tryRoute(spec.Method, e.jointAbsolutePath(spec.Path), spec.Meta); err != nil {
+				fail("%v", err)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	for _, spec := range specs {
+		r := &e.Route
+		for k, v := range spec.Meta {
+			r = r.Meta(k, v)
+		}
+		handlers := make(HandlersChain, 0, len(spec.Middleware)+1)
+		for _, name := range spec.Middleware {
+			handlers = append(handlers, e.middleware[name])
+		}
+		r.handle(spec.Method, spec.Path, append(handlers, spec.Handler)...)
+	}
+	return nil
+}
+
+// noRouteHandler is the handler of the routes of the scratch trees.
+func noRouteHandler(*Context) {}
+
+// tryRoute add a route to the scratch engine e, the panic of a conflict
+// returned as error.
+func (e *Engine) tryRoute(method, path string, meta map[string]any) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = errors.New(strings.TrimPrefix(fmt.Sprint(v), "glaze: "))
+		}
+	}()
+	e.addRoute(method, path, meta, noRouteHandler)
+	e.addRouteName(meta, method, path)
+	return nil
+}
+
+// routeKey return the tree position of a route, the parameter names
+// removed: "GET /users/:id" and "GET /users/:name/" are the same route.
+func routeKey(method, path string) string {
+	parts := splitClean(path)
+	for i, part := range parts {
+		if part[0] == ':' || part[0] == '*' {
+			parts[i] = part[:1]
+		}
+	}
+	return method + " /" + strings.Join(parts, "/")
+}
@@ -0,0 +1,25 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+// Reporter receive panics and server errors, it is the integration
+// point of error tracking services (Sentry, Rollbar, Bugsnag).
+// Methods are called synchronously in the request goroutine, so
+// implementations should hand the work off quickly.
+type Reporter interface {
+	// ReportPanic is called by the recovery middleware.
+	ReportPanic(c *Context, report PanicReport)
+	// ReportError is called by the error middleware for errors
+	// rendered as a server error (5xx).
+	ReportError(c *Context, err error)
+}
+
+// WithReporter set the engine reporter.
+//
+//	e := glaze.New(glaze.WithReporter(sentryReporter{}))
+func WithReporter(r Reporter) ConfigsFunc {
+	return func(e *Engine) {
+		e.reporter = r
+	}
+}
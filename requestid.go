@@ -0,0 +1,110 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+)
+
+// RequestIDConfig holds the configuration of the RequestID middleware.
+type RequestIDConfig struct {
+	// Header carry the id in the request and the response, default "X-Request-ID".
+	Header string
+
+	// Generator return a new id, default 16 random bytes in hex.
+	Generator func() string
+
+	// IgnoreIncoming always generate the id, instead of keeping a valid
+	// one sent by the client or the proxy.
+	IgnoreIncoming bool
+}
+
+// requestIDKey is the context key of the request id.
+type requestIDKey struct{}
+
+// RequestID returns a middleware giving an id to every request, read with
+// c.RequestID in handlers and RequestIDFromContext in services. Incoming ids
+// of up to 128 printable characters are kept, so a trace cross services.
+// The id is sent back in the response header, and added to the panic
+// reports of Recovery and the server error logs of ErrorHandler.
+//
+// Usage:
+//
+//	r.Use(glaze.RequestID(), glaze.Recovery(), glaze.ErrorHandler())
+func RequestID(cfg ...RequestIDConfig) HandlerFunc {
+	var conf RequestIDConfig
+	if len(cfg) > 0 {
+		conf = cfg[0]
+	}
+	if conf.Header == "" {
+		conf.Header = "X-Request-ID"
+	}
+	if conf.Generator == nil {
+		conf.Generator = newRequestID
+	}
+
+	return func(c *Context) {
+		id := ""
+		if !conf.IgnoreIncoming {
+			id = c.GetHeader(conf.Header)
+		}
+		if !validRequestID(id) {
+			id = conf.Generator()
+		}
+		c.Writer.Header().Set(conf.Header, id)
+		c.Set(requestIDKey{}, id)
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), requestIDKey{}, id))
+		c.Next()
+	}
+}
+
+// RequestID return the id given by the RequestID middleware, "" without it.
+func (c *Context) RequestID() string {
+	v, _ := c.Get(requestIDKey{})
+	id, _ := v.(string)
+	return id
+}
+
+// RequestIDFromContext return the request id of a request context, for the
+// code receiving only c.Request.Context().
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+func newRequestID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// validRequestID report whether an incoming id is safe to log and send back.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// requestFields return the request id, matched route and client address of
+// c for log lines, "request_id=7f3a route=/users/:id client_ip=10.0.0.7".
+func requestFields(c *Context) string {
+	var b strings.Builder
+	if id := c.RequestID(); id != "" {
+		b.WriteString("request_id=" + id + " ")
+	}
+	if route := c.FullPath(); route != "" {
+		b.WriteString("route=" + route + " ")
+	}
+	b.WriteString("client_ip=" + c.ClientIP())
+	return b.String()
+}
@@ -0,0 +1,140 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// cacheMeta is the route metadata key of Route.Cache.
+const cacheMeta = "glaze.cache"
+
+// routeCache is the cache policy of a route, see Route.Cache.
+type routeCache struct {
+	ttl  time.Duration
+	vary []string
+}
+
+// Cache returns a copy of the group where the GET and HEAD responses of
+// the routes are kept ttl by the ResponseCache middleware. varyHeaders are
+// the request headers changing the response (Accept-Language...), each
+// value is cached apart and the headers are listed in Vary.
+//
+// Usage:
+//
+//	r.Use(glaze.ResponseCache())
+//	r.Cache(5*time.Minute, "Accept-Language").Get("/products", listProducts)
+func (r *Route) Cache(ttl time.Duration, varyHeaders ...string) *Route {
+	if ttl <= 0 {
+		panic("glaze: cache ttl must be positive")
+	}
+	vary := make([]string, len(varyHeaders))
+	for i, h := range varyHeaders {
+		vary[i] = http.CanonicalHeaderKey(h)
+	}
+	return r.Meta(cacheMeta, routeCache{ttl: ttl, vary: vary})
+}
+
+// ResponseCacheConfig holds the configuration of the ResponseCache middleware.
+type ResponseCacheConfig struct {
+	// Store save the responses, default an in-memory store. Any
+	// IdempotencyStore works, the shared ones too.
+	Store IdempotencyStore
+
+	// Scope return a prefix isolating the cached responses, the user id
+	// for private pages. Default is empty, the responses are shared.
+	Scope func(*Context) string
+}
+
+// ResponseCache returns a middleware replaying the responses of the routes
+// declared with Route.Cache, the others are not touched. Only 200
+// responses without Set-Cookie, "no-store" or "private" are saved. Replayed
+// responses have "X-Cache: HIT", the others "X-Cache: MISS".
+//
+// Responses are cached per host, and only the headers set after the
+// middleware are saved: the ones of the earlier middlewares (X-Request-ID...)
+// are kept from the current request. Used after Compress, the body is saved
+// uncompressed and encoded again for each client; used before, it is cached
+// per negotiated encoding.
+func ResponseCache(cfg ...ResponseCacheConfig) HandlerFunc {
+	var conf ResponseCacheConfig
+	if len(cfg) > 0 {
+		conf = cfg[0]
+	}
+	if conf.Store == nil {
+		conf.Store = NewMemoryIdempotencyStore()
+	}
+
+	return func(c *Context) {
+		v, _ := c.RouteMeta(cacheMeta)
+		policy, ok := v.(routeCache)
+		if !ok || c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			return
+		}
+
+		var b strings.Builder
+		if conf.Scope != nil {
+			b.WriteString(conf.Scope(c) + ":")
+		}
+		b.WriteString(c.Request.Method + " " + c.Request.Host + c.Request.URL.RequestURI())
+		for _, name := range policy.vary {
+			b.WriteString("\n" + name + ": " + c.GetHeader(name))
+		}
+		compressed := c.compressed
+		if !compressed {
+			// a Compress running after the cache may encode the recorded body
+			b.WriteString("\nContent-Encoding: " + acceptedEncoding(c.GetHeader("Accept-Encoding")))
+		}
+		key := b.String()
+
+		if cached, ok := conf.Store.Get(key); ok {
+			replayResponse(c, cached, "X-Cache", "HIT")
+			c.Abort()
+			return
+		}
+
+		h := c.Writer.Header()
+		before := h.Clone()
+		for _, name := range policy.vary {
+			h.Add("Vary", name)
+		}
+		h.Set("X-Cache", "MISS")
+
+		rec := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = rec
+		c.Next()
+		c.Writer = rec.ResponseWriter
+
+		if rec.Status() == http.StatusOK && cacheable(rec.Header()) {
+			conf.Store.Set(key, &CachedResponse{
+				Status: http.StatusOK,
+				Header: recordedHeader(headerDiff(before, rec.Header()), compressed),
+				Body:   rec.body.Bytes(),
+			}, policy.ttl)
+		}
+	}
+}
+
+// cacheable report whether a response with header h can be shared.
+func cacheable(h http.Header) bool {
+	if h.Get("Set-Cookie") != "" {
+		return false
+	}
+	cc := strings.ToLower(h.Get("Cache-Control"))
+	return !strings.Contains(cc, "no-store") && !strings.Contains(cc, "private")
+}
+
+// headerDiff returns the headers of after missing or changed in before.
+func headerDiff(before, after http.Header) http.Header {
+	diff := make(http.Header)
+	for k, v := range after {
+		if !slices.Equal(before[k], v) {
+			diff[k] = slices.Clone(v)
+		}
+	}
+	return diff
+}
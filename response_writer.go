@@ -0,0 +1,90 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"net/http"
+)
+
+const noWritten = -1
+
+// ResponseWriter extends http.ResponseWriter with information
+// about the response, so middleware can inspect it after
+// the handler chain is done (status code, body size).
+type ResponseWriter interface {
+	http.ResponseWriter
+	http.Flusher
+
+	// Status return the HTTP status code sent (200 if not set yet).
+	Status() int
+	// Size return the number of bytes written to the body,
+	// or -1 if nothing has been written.
+	Size() int
+	// Written report whether the header already sent to the client.
+	Written() bool
+	// Unwrap return the original http.ResponseWriter,
+	// it is used by http.ResponseController.
+	Unwrap() http.ResponseWriter
+}
+
+// responseWriter is the default ResponseWriter implementation.
+type responseWriter struct {
+	http.ResponseWriter
+	status int
+	size   int
+}
+
+var _ ResponseWriter = (*responseWriter)(nil)
+
+// reset prepare writer for a new response.
+func (w *responseWriter) reset(rw http.ResponseWriter) {
+	w.ResponseWriter = rw
+	w.status = http.StatusOK
+	w.size = noWritten
+}
+
+// WriteHeader record the status code, only the first call is sent.
+func (w *responseWriter) WriteHeader(code int) {
+	if code > 0 && !w.Written() {
+		w.status = code
+		w.size = 0
+		w.ResponseWriter.WriteHeader(code)
+	}
+}
+
+// Write send the header (if not yet) and write data to the body.
+func (w *responseWriter) Write(data []byte) (int, error) {
+	if !w.Written() {
+		w.WriteHeader(w.status)
+	}
+	n, err := w.ResponseWriter.Write(data)
+	w.size += n
+	return n, err
+}
+
+// Flush send buffered data to the client if the underlying writer support it.
+func (w *responseWriter) Flush() {
+	if !w.Written() {
+		w.WriteHeader(w.status)
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *responseWriter) Status() int {
+	return w.status
+}
+
+func (w *responseWriter) Size() int {
+	return w.size
+}
+
+func (w *responseWriter) Written() bool {
+	return w.size != noWritten
+}
+
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
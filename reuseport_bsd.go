@@ -0,0 +1,10 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+//go:build darwin || dragonfly || freebsd || netbsd || openbsd
+
+package glaze
+
+import "syscall"
+
+const soReusePort = syscall.SO_REUSEPORT
@@ -0,0 +1,9 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+//go:build linux && !mips && !mipsle && !mips64 && !mips64le
+
+package glaze
+
+// soReusePort is SO_REUSEPORT, missing from the frozen syscall package on linux.
+const soReusePort = 0xf
@@ -0,0 +1,16 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+//go:build !((linux && !mips && !mipsle && !mips64 && !mips64le) || darwin || dragonfly || freebsd || netbsd || openbsd)
+
+package glaze
+
+import (
+	"errors"
+	"net"
+)
+
+// listenReusePort is not supported on this platform.
+func listenReusePort(addr string) (net.Listener, error) {
+	return nil, errors.New("glaze: prefork (SO_REUSEPORT) is not supported on this platform")
+}
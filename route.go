@@ -22,10 +22,13 @@ type HandlersChain []HandlerFunc
 type M map[string]any
 
 // RouteInfo describes a single registered route,
-// including the HTTP method and the route path.
+// including the HTTP method, the route path and its metadata.
 type RouteInfo struct {
-	Method string
-	Path   string
+	Method   string
+	Path     string
+	Meta     map[string]any
+	Handler  string // name of the last handler of the chain
+	Handlers int    // number of handlers, middleware included
 }
 
 // Router is the main interface for grouping and
@@ -49,6 +52,11 @@ type Routes interface {
 	Put(string, ...HandlerFunc) Routes
 	Options(string, ...HandlerFunc) Routes
 	Head(string, ...HandlerFunc) Routes
+
+	StaticFile(string, string) Routes
+	StaticFileFS(string, string, http.FileSystem) Routes
+	Static(string, string, ...StaticConfig) Routes
+	StaticFS(string, http.FileSystem, ...StaticConfig) Routes
 }
 
 // Route represents a registered route or a route group.
@@ -60,6 +68,7 @@ type Route struct {
 	Handler HandlersChain
 	root    bool
 	engine  *Engine
+	meta    map[string]any // metadata attached to routes registered from here
 }
 
 // ensure Route implements IRouter
@@ -78,10 +87,34 @@ func (r *Route) Use(middleware ...HandlerFunc) Routes {
 // Group creates a new route group with a common path prefix
 // and optional middleware handlers.
 func (r *Route) Group(path string, handlers ...HandlerFunc) *Route {
-	return &Route{
+	g := &Route{
 		engine:  r.engine,
 		Path:    r.jointAbsolutePath(path),
 		Handler: r.joinHandler(handlers),
+		meta:    r.meta,
+	}
+	r.engine.groups = append(r.engine.groups, g)
+	return g
+}
+
+// Meta returns a copy of the group where every route registered
+// through it carries the key/value metadata. Groups created from it
+// inherit the metadata too. Handlers read it with Context.RouteMeta.
+//
+// Example:
+//
+//	r.Meta("audit", true).Post("/transfer", transferHandler)
+func (r *Route) Meta(key string, value any) *Route {
+	meta := make(map[string]any, len(r.meta)+1)
+	for k, v := range r.meta {
+		meta[k] = v
+	}
+	meta[key] = value
+	return &Route{
+		engine:  r.engine,
+		Path:    r.Path,
+		Handler: r.joinHandler(nil),
+		meta:    meta,
 	}
 }
 
@@ -92,8 +125,13 @@ func (r *Route) handle(method, relativePath string, handlers ...HandlerFunc) Rou
 		panic("invalid method '" + method + "'")
 	}
 	absolutePath := r.jointAbsolutePath(relativePath)
+	if len(handlers) == 0 && len(r.Handler) > 0 {
+		r.engine.lintIssues = append(r.engine.lintIssues, LintIssue{
+			method, absolutePath, "route has only middleware, no handler of its own"})
+	}
 	handlers = r.joinHandler(handlers)
-	r.engine.addRoute(method, absolutePath, handlers...)
+	r.engine.addRoute(method, absolutePath, r.meta, handlers...)
+	r.engine.addRouteName(r.meta, method, absolutePath)
 	return r.engineInfo()
 }
 
@@ -0,0 +1,333 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// RunAndListen starts an HTTP server at the given address.
+// This function is simple: it does not support graceful shutdown.
+//
+// Example:
+//
+//	e := glaze.New()
+//	e.RunAndListen(":8080")
+func (e *Engine) RunAndListen(addr string) error {
+	e.debugPrintRoutes()
+	listener, err := listenTCP(addr, ":http")
+	if err != nil {
+		return err
+	}
+	e.debugPrintListen("listen on %s\n", addr)
+	srv := e.newServer(addr)
+	return e.start(srv, listener, srv.Serve)
+}
+
+// RunTLS starts an HTTPS server at the given address with the
+// certificate and key files. Like RunAndListen it does not support
+// graceful shutdown, see ListenAndGracefulTLS.
+//
+// Example:
+//
+//	e.RunTLS(":443", "cert.pem", "key.pem")
+func (e *Engine) RunTLS(addr, certFile, keyFile string) error {
+	return e.runTLS(addr, nil, certFile, keyFile)
+}
+
+// RunTLSConfig starts an HTTPS server at the given address using cfg,
+// which must provide the certificates (Certificates or GetCertificate).
+func (e *Engine) RunTLSConfig(addr string, cfg *tls.Config) error {
+	return e.runTLS(addr, cfg, "", "")
+}
+
+func (e *Engine) runTLS(addr string, cfg *tls.Config, certFile, keyFile string) error {
+	e.debugPrintRoutes()
+	listener, err := listenTCP(addr, ":https")
+	if err != nil {
+		return err
+	}
+
+	srv := e.newServer(addr)
+	if cfg != nil {
+		srv.TLSConfig = cfg
+	}
+	certFile, keyFile, stop, err := e.prepareTLS(srv, certFile, keyFile)
+	if err != nil {
+		listener.Close()
+		return err
+	}
+	defer stop()
+
+	e.debugPrintListen("listen on %s (https)\n", addr)
+	return e.start(srv, listener, func(l net.Listener) error {
+		return srv.ServeTLS(l, certFile, keyFile)
+	})
+}
+
+// listenTCP bind addr, empty addr use fallback (":http" or ":https")
+// like the net/http ListenAndServe functions.
+func listenTCP(addr, fallback string) (net.Listener, error) {
+	if addr == "" {
+		addr = fallback
+	}
+	return net.Listen("tcp", addr)
+}
+
+// RunUnix starts an HTTP server listening on the unix socket file,
+// with the given file permissions (0o660 let the proxy group connect).
+// A stale socket file left by a previous process is removed first.
+//
+// Example:
+//
+//	e.RunUnix("/run/app/app.sock", 0o660)
+func (e *Engine) RunUnix(file string, perm os.FileMode) error {
+	e.debugPrintRoutes()
+	if err := removeStaleSocket(file); err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("unix", file)
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+
+	if err = os.Chmod(file, perm); err != nil {
+		return err
+	}
+
+	e.debugPrintListen("listen on unix:%s\n", file)
+	srv := e.newServer("")
+	return e.start(srv, listener, srv.Serve)
+}
+
+// removeStaleSocket delete file if it is a socket nobody listen on.
+func removeStaleSocket(file string) error {
+	info, err := os.Stat(file)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("%s exists and is not a socket", file)
+	}
+
+	// a live server still accept connections, do not steal its socket
+	if conn, err := net.DialTimeout("unix", file, time.Second); err == nil {
+		conn.Close()
+		return fmt.Errorf("%s is in use by another process", file)
+	}
+	return os.Remove(file)
+}
+
+// RunListener starts an HTTP server accepting connections on an existing
+// listener, useful for port sharing and tests binding ":0" upfront.
+// The listener is closed when the server stop.
+func (e *Engine) RunListener(listener net.Listener) error {
+	e.debugPrintRoutes()
+	e.debugPrintListen("listen on %s\n", listener.Addr())
+	srv := e.newServer(listener.Addr().String())
+	return e.start(srv, listener, srv.Serve)
+}
+
+// RunFd starts an HTTP server on an inherited listening socket file
+// descriptor, like the ones passed by systemd socket activation
+// (the first one is fd 3).
+//
+// Example:
+//
+//	e.RunFd(3)
+func (e *Engine) RunFd(fd uintptr) error {
+	f := os.NewFile(fd, "fd@"+strconv.FormatUint(uint64(fd), 10))
+	if f == nil {
+		return fmt.Errorf("invalid file descriptor %d", fd)
+	}
+	defer f.Close()
+
+	listener, err := net.FileListener(f)
+	if err != nil {
+		return err
+	}
+	return e.RunListener(listener)
+}
+
+// ListenAndGraceful starts an HTTP server at the given address,
+// but it also listen for system signals (SIGINT, SIGTERM).
+// When signal received, it shutdown the server gracefully with timeout.
+//
+// Example:
+//
+//	e := glaze.New()
+//	e.ListenAndGraceful(":8080")
+func (e *Engine) ListenAndGraceful(addr string) error {
+	e.debugPrintRoutes()
+	listener, err := listenTCP(addr, ":http")
+	if err != nil {
+		return err
+	}
+
+	// create http server
+	srv := e.newServer(addr)
+
+	e.debugPrintListen("listen on %s\n", addr)
+	return e.graceful(srv, func() error {
+		return e.start(srv, listener, srv.Serve)
+	})
+}
+
+// ListenAndGracefulTLS is like ListenAndGraceful but serve HTTPS
+// with the certificate and key files.
+func (e *Engine) ListenAndGracefulTLS(addr, certFile, keyFile string) error {
+	e.debugPrintRoutes()
+	listener, err := listenTCP(addr, ":https")
+	if err != nil {
+		return err
+	}
+	srv := e.newServer(addr)
+	certFile, keyFile, stop, err := e.prepareTLS(srv, certFile, keyFile)
+	if err != nil {
+		listener.Close()
+		return err
+	}
+	defer stop()
+
+	e.debugPrintListen("listen on %s (https)\n", addr)
+	return e.graceful(srv, func() error {
+		return e.start(srv, listener, func(l net.Listener) error {
+			return srv.ServeTLS(l, certFile, keyFile)
+		})
+	})
+}
+
+// graceful run serve in background and shutdown srv when
+// SIGINT or SIGTERM is received. It return early if serve fail.
+func (e *Engine) graceful(srv *http.Server, serve func() error) error {
+	// run server in goroutine
+	errc := make(chan error, 1)
+	go func() {
+		errc <- serve()
+	}()
+
+	// wait for signal
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(quit)
+	select {
+	case err := <-errc:
+		if err != http.ErrServerClosed {
+			return err
+		}
+		return nil
+	case <-quit:
+	}
+	e.logf(LogLevelInfo, "Shutdown Server\n")
+
+	// graceful shutdown with context timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		return err
+	}
+	if err := e.waitBackground(ctx); err != nil {
+		return err
+	}
+	e.logf(LogLevelInfo, "Server exiting\n")
+	return nil
+}
+
+// Shutdown gracefully stop every server started by the Run helpers:
+// listeners are closed, then it wait for active requests to finish or
+// ctx to be done, as well as goroutines started with Context.Go.
+// The Run helpers return http.ErrServerClosed.
+//
+// Usage:
+//
+//	go e.RunAndListen(":8080")
+//	...
+//	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+//	defer cancel()
+//	e.Shutdown(ctx)
+func (e *Engine) Shutdown(ctx context.Context) error {
+	var errs []error
+	for _, srv := range e.runningServers() {
+		if err := srv.Shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := e.waitBackground(ctx); err != nil && len(errs) == 0 {
+		errs = append(errs, err) // else already the ctx error of srv.Shutdown
+	}
+	return errors.Join(errs...)
+}
+
+// Close immediately stop every server started by the Run helpers,
+// closing the listeners and all connections.
+func (e *Engine) Close() error {
+	e.beginDrain()
+	var errs []error
+	for _, srv := range e.runningServers() {
+		if err := srv.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// runningServers return a snapshot of the tracked servers.
+func (e *Engine) runningServers() []*http.Server {
+	e.serversMu.Lock()
+	defer e.serversMu.Unlock()
+	list := make([]*http.Server, 0, len(e.servers))
+	for srv := range e.servers {
+		list = append(list, srv)
+	}
+	return list
+}
+
+// DebugPrintRouteFunc print one route of the banner shown by the Run
+// helpers in debug mode. Replace it to render colored, aligned or JSON
+// banners, or to hide some routes.
+//
+// Usage:
+//
+//	glaze.DebugPrintRouteFunc = func(w io.Writer, method, path, handler string, handlers int) {
+//	    if !strings.HasPrefix(path, "/internal") {
+//	        fmt.Fprintf(w, "%-7s %-40s --> %s (%d handlers)\n", method, path, handler, handlers)
+//	    }
+//	}
+var DebugPrintRouteFunc func(w io.Writer, method, path, handler string, handlers int)
+
+// debugPrintRoutes show all routes in console in debug mode.
+func (e *Engine) debugPrintRoutes() {
+	if e.logLevel > LogLevelDebug || !IsDebugging() {
+		return
+	}
+	for _, r := range e.RoutesInfo() {
+		if DebugPrintRouteFunc != nil {
+			DebugPrintRouteFunc(e.writer, r.Method, r.Path, r.Handler, r.Handlers)
+			continue
+		}
+		e.logf(LogLevelDebug, "%-6s %s\n", r.Method, r.Path)
+	}
+	e.debugPrintLint()
+}
+
+// debugPrintListen print the listen message in debug mode.
+func (e *Engine) debugPrintListen(format string, args ...any) {
+	e.logf(LogLevelDebug, format, args...)
+}
@@ -0,0 +1,169 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultReadHeaderTimeout = 10 * time.Second
+	defaultIdleTimeout       = 120 * time.Second
+)
+
+// serverConfig holds the http.Server parameters used by the Run helpers.
+type serverConfig struct {
+	readTimeout       time.Duration
+	readHeaderTimeout time.Duration
+	writeTimeout      time.Duration
+	idleTimeout       time.Duration
+	maxHeaderBytes    int
+	base              *http.Server     // user supplied server, see WithServer
+	client            clientAuthConfig // mutual TLS, see WithClientCAs
+	connState         func(net.Conn, http.ConnState)
+	baseContext       func(net.Listener) context.Context
+
+	certReload         bool          // see WithCertReload
+	certReloadInterval time.Duration // files polling interval
+}
+
+// WithReadTimeout set http.Server.ReadTimeout, the maximum duration
+// to read the entire request including the body. Default 0 (no limit).
+func WithReadTimeout(d time.Duration) ConfigsFunc {
+	return func(e *Engine) {
+		e.server.readTimeout = d
+	}
+}
+
+// WithReadHeaderTimeout set http.Server.ReadHeaderTimeout. Default 10s.
+func WithReadHeaderTimeout(d time.Duration) ConfigsFunc {
+	return func(e *Engine) {
+		e.server.readHeaderTimeout = d
+	}
+}
+
+// WithWriteTimeout set http.Server.WriteTimeout, the maximum duration
+// before timing out writes of the response. Default 0 (no limit),
+// keep it 0 or long enough for streaming endpoints.
+func WithWriteTimeout(d time.Duration) ConfigsFunc {
+	return func(e *Engine) {
+		e.server.writeTimeout = d
+	}
+}
+
+// WithIdleTimeout set http.Server.IdleTimeout for keep-alive connections. Default 120s.
+func WithIdleTimeout(d time.Duration) ConfigsFunc {
+	return func(e *Engine) {
+		e.server.idleTimeout = d
+	}
+}
+
+// WithMaxHeaderBytes set http.Server.MaxHeaderBytes. Default is http.DefaultMaxHeaderBytes.
+func WithMaxHeaderBytes(n int) ConfigsFunc {
+	return func(e *Engine) {
+		e.server.maxHeaderBytes = n
+	}
+}
+
+// WithBaseContext set http.Server.BaseContext, the parent context of every
+// request of a listener. Values added there (build version, region, clients)
+// are available with c.Request.Context().Value, see Engine.InjectContext for
+// per request values.
+//
+// Usage:
+//
+//	e := glaze.New(glaze.WithBaseContext(func(net.Listener) context.Context {
+//	    return context.WithValue(context.Background(), regionKey{}, "eu-west-1")
+//	}))
+func WithBaseContext(fn func(net.Listener) context.Context) ConfigsFunc {
+	return func(e *Engine) {
+		e.server.baseContext = fn
+	}
+}
+
+// WithServer use srv as the model of the servers of the Run helpers, for
+// full control of its fields. Each Run helper serve a copy of it, Addr and
+// Handler are set when empty, the other timeout options and the connection
+// tracking (WithConnState, ConnStats) are ignored. Stop the servers with
+// Engine.Shutdown or Engine.Close, not srv.
+func WithServer(srv *http.Server) ConfigsFunc {
+	return func(e *Engine) {
+		e.server.base = srv
+	}
+}
+
+// Server return the http.Server copied by every Run helper, created on
+// first call from the engine options. Change advanced fields (TLSNextProto,
+// ErrorLog, BaseContext...) before calling a Run helper. Each Run helper
+// serve its own copy, so concurrent Run helpers do not share one; the
+// functions given to its RegisterOnShutdown are not copied.
+//
+// Usage:
+//
+//	srv := e.Server()
+//	srv.ErrorLog = log.New(logFile, "http: ", log.LstdFlags)
+//	e.ListenAndGraceful(":8080")
+func (e *Engine) Server() *http.Server {
+	e.serversMu.Lock()
+	defer e.serversMu.Unlock()
+	if e.srv == nil {
+		e.srv = e.buildServer()
+	}
+	return e.srv
+}
+
+// newServer return a new server of a Run helper listening on addr, a
+// copy of Server.
+func (e *Engine) newServer(addr string) *http.Server {
+	base := e.Server()
+	srv := &http.Server{
+		Addr:                         base.Addr,
+		Handler:                      base.Handler,
+		DisableGeneralOptionsHandler: base.DisableGeneralOptionsHandler,
+		ReadTimeout:                  base.ReadTimeout,
+		ReadHeaderTimeout:            base.ReadHeaderTimeout,
+		WriteTimeout:                 base.WriteTimeout,
+		IdleTimeout:                  base.IdleTimeout,
+		MaxHeaderBytes:               base.MaxHeaderBytes,
+		TLSNextProto:                 base.TLSNextProto,
+		ConnState:                    base.ConnState,
+		ErrorLog:                     base.ErrorLog,
+		BaseContext:                  base.BaseContext,
+		ConnContext:                  base.ConnContext,
+		HTTP2:                        base.HTTP2,
+		Protocols:                    base.Protocols,
+	}
+	if base.TLSConfig != nil {
+		srv.TLSConfig = base.TLSConfig.Clone()
+	}
+	if srv.Addr == "" {
+		srv.Addr = addr
+	}
+	srv.RegisterOnShutdown(e.beginDrain)
+	return srv
+}
+
+// buildServer create the http.Server from the engine options.
+func (e *Engine) buildServer() *http.Server {
+	if srv := e.server.base; srv != nil {
+		if srv.Handler == nil {
+			srv.Handler = e.engine
+		}
+		return srv
+	}
+	srv := &http.Server{
+		Handler:           e.engine,
+		ReadTimeout:       e.server.readTimeout,
+		ReadHeaderTimeout: e.server.readHeaderTimeout,
+		WriteTimeout:      e.server.writeTimeout,
+		IdleTimeout:       e.server.idleTimeout,
+		MaxHeaderBytes:    e.server.maxHeaderBytes,
+		ConnState:         e.connStateHook,
+		BaseContext:       e.server.baseContext,
+	}
+	return srv
+}
@@ -0,0 +1,223 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// ServiceRoutes can be implemented by a service given to RegisterService
+// to choose the route of its methods. Keys are method names, values are
+// "METHOD /path", or "-" to skip the method.
+//
+// Usage:
+//
+//	func (s *UserService) ServiceRoutes() map[string]string {
+//	    return map[string]string{
+//	        "GetUser":  "GET /users/:id",
+//	        "Internal": "-",
+//	    }
+//	}
+type ServiceRoutes interface {
+	ServiceRoutes() map[string]string
+}
+
+// serviceVerbs map the method name prefix to the HTTP method.
+var serviceVerbs = []struct {
+	prefix, method string
+}{
+	{"Get", http.MethodGet},
+	{"List", http.MethodGet},
+	{"Find", http.MethodGet},
+	{"Create", http.MethodPost},
+	{"Add", http.MethodPost},
+	{"Update", http.MethodPut},
+	{"Replace", http.MethodPut},
+	{"Patch", http.MethodPatch},
+	{"Delete", http.MethodDelete},
+	{"Remove", http.MethodDelete},
+}
+
+var (
+	contextType    = reflect.TypeFor[*Context]()
+	stdContextType = reflect.TypeFor[context.Context]()
+	errorType      = reflect.TypeFor[error]()
+)
+
+// RegisterService register the exported methods of svc as routes of r.
+// A method is registered when its signature is one of:
+//
+//	func(c *glaze.Context, req Req) (Resp, error)
+//	func(ctx context.Context, req Req) (Resp, error)
+//	func(c *glaze.Context) (Resp, error)
+//	func(ctx context.Context) (Resp, error)
+//
+// where Req is a struct or a pointer to struct, bound and validated like
+// Handler do, and Resp is rendered as JSON. Other methods are ignored.
+//
+// The HTTP method come from the name prefix (Get, List, Find → GET;
+// Create, Add → POST; Update, Replace → PUT; Patch → PATCH; Delete,
+// Remove → DELETE; others → POST) and the path is the kebab case name,
+// GetUser → GET /get-user. Implement ServiceRoutes to set other routes.
+// It panic when a route of ServiceRoutes is invalid.
+//
+// Usage:
+//
+//	type UserService struct{ db *sql.DB }
+//
+//	func (s *UserService) GetUser(ctx context.Context, in GetUserInput) (User, error)
+//	func (s *UserService) CreateUser(ctx context.Context, in CreateUserInput) (User, error)
+//
+//	glaze.RegisterService(r.Group("/rpc"), &UserService{db: db})
+func RegisterService(r Routes, svc any) {
+	var routes map[string]string
+	if sr, ok := svc.(ServiceRoutes); ok {
+		routes = sr.ServiceRoutes()
+	}
+
+	v := reflect.ValueOf(svc)
+	t := v.Type()
+	for i := 0; i < t.NumMethod(); i++ {
+		m := t.Method(i)
+		if m.Name == "ServiceRoutes" {
+			continue
+		}
+		route, custom := routes[m.Name]
+		if route == "-" {
+			continue
+		}
+		fn := v.Method(i)
+		if !isServiceMethod(fn.Type()) {
+			if custom {
+				panic("glaze: service method " + m.Name + " has an invalid signature")
+			}
+			continue
+		}
+
+		method, path := serviceRoute(m.Name)
+		if custom {
+			var ok bool
+			method, path, ok = strings.Cut(route, " ")
+			if !ok {
+				panic("glaze: invalid service route " + route + " for " + m.Name)
+			}
+			path = strings.TrimSpace(path)
+		}
+
+		h := serviceHandler(fn)
+		switch method {
+		case http.MethodGet:
+			r.Get(path, h)
+		case http.MethodPost:
+			r.Post(path, h)
+		case http.MethodPut:
+			r.Put(path, h)
+		case http.MethodPatch:
+			r.Patch(path, h)
+		case http.MethodDelete:
+			r.Delete(path, h)
+		case http.MethodOptions:
+			r.Options(path, h)
+		case http.MethodHead:
+			r.Head(path, h)
+		default:
+			panic("glaze: invalid service method " + method + " for " + m.Name)
+		}
+	}
+}
+
+// isServiceMethod report whether t is a signature handled by RegisterService.
+func isServiceMethod(t reflect.Type) bool {
+	if t.NumIn() < 1 || t.NumIn() > 2 || t.NumOut() != 2 || t.Out(1) != errorType {
+		return false
+	}
+	if in := t.In(0); in != contextType && in != stdContextType {
+		return false
+	}
+	if t.NumIn() == 2 {
+		req := t.In(1)
+		if req.Kind() == reflect.Pointer {
+			req = req.Elem()
+		}
+		return req.Kind() == reflect.Struct
+	}
+	return true
+}
+
+// serviceRoute return the default HTTP method and path of a method name.
+func serviceRoute(name string) (string, string) {
+	method := http.MethodPost
+	for _, verb := range serviceVerbs {
+		rest, ok := strings.CutPrefix(name, verb.prefix)
+		if ok && (rest == "" || unicode.IsUpper(rune(rest[0]))) {
+			method = verb.method
+			break
+		}
+	}
+	return method, "/" + kebabCase(name)
+}
+
+// kebabCase convert GetUserByID to get-user-by-id.
+func kebabCase(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			// new word on lower→Upper, or on the last upper of an acronym
+			if i > 0 && (unicode.IsLower(runes[i-1]) ||
+				i+1 < len(runes) && unicode.IsUpper(runes[i-1]) && unicode.IsLower(runes[i+1])) {
+				b.WriteByte('-')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// serviceHandler return the handler calling the service method fn.
+func serviceHandler(fn reflect.Value) HandlerFunc {
+	t := fn.Type()
+	var reqType reflect.Type
+	if t.NumIn() == 2 {
+		reqType = t.In(1)
+	}
+	stdContext := t.In(0) == stdContextType
+
+	return func(c *Context) {
+		args := make([]reflect.Value, 1, 2)
+		if stdContext {
+			args[0] = reflect.ValueOf(c.Request.Context())
+		} else {
+			args[0] = reflect.ValueOf(c)
+		}
+
+		if reqType != nil {
+			ptr := reqType
+			if ptr.Kind() == reflect.Pointer {
+				ptr = ptr.Elem()
+			}
+			req := reflect.New(ptr)
+			if err := c.Bind(req.Interface()); err != nil {
+				c.renderBindError(err)
+				return
+			}
+			if reqType.Kind() != reflect.Pointer {
+				req = req.Elem()
+			}
+			args = append(args, req)
+		}
+
+		out := fn.Call(args)
+		if err, _ := out[1].Interface().(error); err != nil {
+			c.renderError(err)
+			return
+		}
+		c.renderResult(out[0].Interface())
+	}
+}
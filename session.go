@@ -0,0 +1,294 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const maxSessionCookieSize = 4096
+
+// SessionConfig holds the configuration of the Sessions middleware.
+type SessionConfig struct {
+	// Secret encrypt and authenticate the session cookie (AES-GCM),
+	// at least 32 bytes. Changing it invalidate every session.
+	Secret []byte
+
+	// CookieName of the session, default "glaze_session".
+	CookieName string
+
+	// MaxAge is the lifetime of a session, default 0: the cookie last
+	// until the browser is closed and the session never expire.
+	MaxAge time.Duration
+
+	// Path of the cookie, default "/". Domain is empty by default.
+	Path   string
+	Domain string
+
+	// SameSite of the cookie, default http.SameSiteLaxMode. The cookie is
+	// HttpOnly, and Secure when the request is HTTPS (see Context.Scheme).
+	SameSite http.SameSite
+}
+
+// sessionKey is the context key of the request session state.
+type sessionKey struct{}
+
+// sessionState is the session of a request, loaded on first use.
+type sessionState struct {
+	cfg     *SessionConfig
+	aead    cipher.AEAD
+	session *Session
+}
+
+// sessionData is the encrypted content of the cookie.
+type sessionData struct {
+	Values   map[string]any `json:"v,omitempty"`
+	Flashes  []Flash        `json:"f,omitempty"`
+	IssuedAt int64          `json:"t"`
+}
+
+// Session is the cookie session of a request. Values are stored as JSON,
+// numbers come back as float64 and structs as maps after a redirect.
+// Changes are written to the Set-Cookie header at once, they must happen
+// before the response is sent.
+type Session struct {
+	c     *Context
+	state *sessionState
+	data  sessionData
+}
+
+// Sessions returns a middleware making c.Session, c.Flash and c.Flashes
+// available, backed by an encrypted cookie.
+//
+// Usage:
+//
+//	r.Use(glaze.Sessions(glaze.SessionConfig{Secret: secret, MaxAge: 24 * time.Hour}))
+//
+//	c.Session().Set("user_id", user.ID)
+func Sessions(cfg SessionConfig) HandlerFunc {
+	if len(cfg.Secret) < 32 {
+		panic("session: Secret must be at least 32 bytes")
+	}
+	if cfg.CookieName == "" {
+		cfg.CookieName = "glaze_session"
+	}
+	if cfg.Path == "" {
+		cfg.Path = "/"
+	}
+	if cfg.SameSite == 0 {
+		cfg.SameSite = http.SameSiteLaxMode
+	}
+	key := sha256.Sum256(cfg.Secret)
+	block, _ := aes.NewCipher(key[:])
+	aead, _ := cipher.NewGCM(block)
+
+	return func(c *Context) {
+		c.Set(sessionKey{}, &sessionState{cfg: &cfg, aead: aead})
+		c.Next()
+	}
+}
+
+// Session return the session of the request. It panic when the Sessions
+// middleware is not used.
+func (c *Context) Session() *Session {
+	v, _ := c.Get(sessionKey{})
+	state, ok := v.(*sessionState)
+	if !ok {
+		panic("glaze: Session need the Sessions middleware")
+	}
+	if state.session == nil {
+		state.session = &Session{c: c, state: state}
+		state.session.load()
+	}
+	return state.session
+}
+
+// hasSession report whether the Sessions middleware is used.
+func (c *Context) hasSession() bool {
+	_, ok := c.Get(sessionKey{})
+	return ok
+}
+
+// Get return the value of key, nil when missing.
+func (s *Session) Get(key string) any {
+	return s.data.Values[key]
+}
+
+// Set store value, it must be JSON encodable.
+func (s *Session) Set(key string, value any) {
+	if s.data.Values == nil {
+		s.data.Values = make(map[string]any)
+	}
+	s.data.Values[key] = value
+	s.save()
+}
+
+// Delete remove key.
+func (s *Session) Delete(key string) {
+	if _, ok := s.data.Values[key]; ok {
+		delete(s.data.Values, key)
+		s.save()
+	}
+}
+
+// Clear remove every value and flash message and delete the cookie, on logout.
+func (s *Session) Clear() {
+	s.data = sessionData{}
+	s.save()
+}
+
+// Renew restart the session lifetime, call it on login so a session
+// stolen before does not last. It also rotate the cookie value.
+func (s *Session) Renew() {
+	s.data.IssuedAt = time.Now().Unix()
+	s.save()
+}
+
+// load decrypt the request cookie, invalid and expired cookies give an
+// empty session.
+func (s *Session) load() {
+	cfg := s.state.cfg
+	s.data = sessionData{IssuedAt: time.Now().Unix()}
+	cookie, err := s.c.Request.Cookie(cfg.CookieName)
+	if err != nil {
+		return
+	}
+	var data sessionData
+	if err := s.state.decode(cookie.Value, &data); err != nil {
+		return
+	}
+	if cfg.MaxAge > 0 && time.Since(time.Unix(data.IssuedAt, 0)) > cfg.MaxAge {
+		return
+	}
+	s.data = data
+}
+
+// save replace the session cookie of the response header.
+func (s *Session) save() {
+	c, cfg := s.c, s.state.cfg
+	if c.Writer.Written() {
+		c.engine.logf(LogLevelError, "session: %s modified after the response was written\n", c.Request.URL.Path)
+		return
+	}
+
+	cookie := &http.Cookie{
+		Name:     cfg.CookieName,
+		Path:     cfg.Path,
+		Domain:   cfg.Domain,
+		Secure:   c.Scheme() == "https",
+		HttpOnly: true,
+		SameSite: cfg.SameSite,
+	}
+	if len(s.data.Values) == 0 && len(s.data.Flashes) == 0 {
+		cookie.MaxAge = -1
+	} else {
+		value, err := s.state.encode(&s.data)
+		if err == nil && len(value) > maxSessionCookieSize {
+			err = errors.New("cookie over 4 KB")
+		}
+		if err != nil {
+			c.engine.logf(LogLevelError, "session: %s: %v\n", c.Request.URL.Path, err)
+			return
+		}
+		cookie.Value = value
+		if cfg.MaxAge > 0 {
+			remaining := cfg.MaxAge - time.Since(time.Unix(s.data.IssuedAt, 0))
+			cookie.MaxAge = max(int(remaining.Seconds()), 1)
+		}
+	}
+
+	// keep one session cookie, the other Set-Cookie headers are untouched
+	h := c.Writer.Header()
+	prefix := cfg.CookieName + "="
+	var kept []string
+	for _, v := range h.Values("Set-Cookie") {
+		if !strings.HasPrefix(v, prefix) {
+			kept = append(kept, v)
+		}
+	}
+	h["Set-Cookie"] = append(kept, cookie.String())
+}
+
+// encode encrypt data as base64(nonce + sealed JSON).
+func (st *sessionState) encode(data *sessionData) (string, error) {
+	plain, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, st.aead.NonceSize(), st.aead.NonceSize()+len(plain)+st.aead.Overhead())
+	rand.Read(nonce)
+	return base64.RawURLEncoding.EncodeToString(st.aead.Seal(nonce, nonce, plain, []byte(st.cfg.CookieName))), nil
+}
+
+// decode decrypt a value made by encode.
+func (st *sessionState) decode(value string, data *sessionData) error {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(raw) < st.aead.NonceSize() {
+		return errors.New("session: invalid cookie")
+	}
+	nonce, sealed := raw[:st.aead.NonceSize()], raw[st.aead.NonceSize():]
+	plain, err := st.aead.Open(nil, nonce, sealed, []byte(st.cfg.CookieName))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(plain, data)
+}
+
+// Flash is a one-time message shown on the next page.
+type Flash struct {
+	Level   string `json:"l"` // "success", "error"...
+	Message string `json:"m"`
+}
+
+// Flash add a message to the session for the next request, usually
+// before a redirect (POST-redirect-GET).
+//
+// Usage:
+//
+//	c.Flash("success", "Profile saved")
+//	http.Redirect(c.Writer, c.Request, "/profile", http.StatusSeeOther)
+func (c *Context) Flash(level, message string) {
+	s := c.Session()
+	s.data.Flashes = append(s.data.Flashes, Flash{Level: level, Message: message})
+	s.save()
+}
+
+// Flashes return the flash messages and remove them from the session,
+// they are shown once. Pages rendered with c.HTML get them automatically
+// as the "flashes" view data, when the data is nil or a map:
+//
+//	{{range .flashes}}<div class="alert {{.Level}}">{{.Message}}</div>{{end}}
+func (c *Context) Flashes() []Flash {
+	s := c.Session()
+	flashes := s.data.Flashes
+	if len(flashes) > 0 {
+		s.data.Flashes = nil
+		s.save()
+	}
+	return flashes
+}
+
+// flashViewData add the pending flashes to the view data of an HTML page.
+func (c *Context) flashViewData(data any) {
+	switch data.(type) {
+	case nil, M, map[string]any:
+	default:
+		return
+	}
+	if _, set := c.viewData["flashes"]; set || !c.hasSession() {
+		return
+	}
+	if len(c.Session().data.Flashes) > 0 {
+		c.SetViewData("flashes", c.Flashes())
+	}
+}
@@ -0,0 +1,211 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultSignatureBodySize = 1 << 20 // 1 MB
+
+var (
+	ErrSignatureMissing = errors.New("signature: missing signature")
+	ErrSignatureInvalid = errors.New("signature: invalid signature")
+	ErrSignatureExpired = errors.New("signature: timestamp outside tolerance")
+)
+
+// SignatureConfig holds the configuration of the request signature middleware.
+type SignatureConfig struct {
+	// Secret is the shared HMAC key.
+	Secret []byte
+
+	// Hash is the HMAC hash function, default sha256.New.
+	Hash func() hash.Hash
+
+	// Header carry the signature, Prefix is stripped from its value ("sha256=").
+	Header string
+	Prefix string
+
+	// Base64 decode the signature as standard base64 instead of hex.
+	Base64 bool
+
+	// TimestampHeader carry the unix timestamp of the request (Slack style).
+	TimestampHeader string
+
+	// Tolerance reject timestamps older or newer than it, 0 disable the check.
+	Tolerance time.Duration
+
+	// Extract override how timestamp and signatures are read from the request,
+	// used by providers packing them in one header (Stripe).
+	Extract func(r *http.Request) (timestamp string, signatures []string, err error)
+
+	// Message build the signed content, default is the raw body.
+	Message func(timestamp string, body []byte) []byte
+
+	// MaxBodySize limit the body read for verification, default 1 MB.
+	MaxBodySize int64
+
+	// OnError render the failure, default respond 401.
+	OnError func(c *Context, err error)
+}
+
+// GitHubSignature returns the config of GitHub webhooks (X-Hub-Signature-256).
+func GitHubSignature(secret []byte) SignatureConfig {
+	return SignatureConfig{
+		Secret: secret,
+		Header: "X-Hub-Signature-256",
+		Prefix: "sha256=",
+	}
+}
+
+// SlackSignature returns the config of Slack request signing (v0 scheme).
+func SlackSignature(secret []byte, tolerance time.Duration) SignatureConfig {
+	return SignatureConfig{
+		Secret:          secret,
+		Header:          "X-Slack-Signature",
+		Prefix:          "v0=",
+		TimestampHeader: "X-Slack-Request-Timestamp",
+		Tolerance:       tolerance,
+		Message: func(ts string, body []byte) []byte {
+			return append([]byte("v0:"+ts+":"), body...)
+		},
+	}
+}
+
+// StripeSignature returns the config of Stripe webhooks (Stripe-Signature: t=..,v1=..).
+func StripeSignature(secret []byte, tolerance time.Duration) SignatureConfig {
+	return SignatureConfig{
+		Secret:    secret,
+		Tolerance: tolerance,
+		Extract: func(r *http.Request) (ts string, sigs []string, err error) {
+			for _, part := range strings.Split(r.Header.Get("Stripe-Signature"), ",") {
+				k, v, _ := strings.Cut(strings.TrimSpace(part), "=")
+				switch k {
+				case "t":
+					ts = v
+				case "v1":
+					sigs = append(sigs, v)
+				}
+			}
+			if ts == "" || len(sigs) == 0 {
+				return "", nil, ErrSignatureMissing
+			}
+			return ts, sigs, nil
+		},
+		Message: func(ts string, body []byte) []byte {
+			return append([]byte(ts+"."), body...)
+		},
+	}
+}
+
+// VerifySignature returns a middleware that verify the HMAC signature of the
+// raw request body before the next handlers run. The body is restored
+// afterward, so binding still work.
+//
+// Usage:
+//
+//	r.Post("/webhooks/github", glaze.VerifySignature(glaze.GitHubSignature(secret)), handler)
+func VerifySignature(cfg SignatureConfig) HandlerFunc {
+	if len(cfg.Secret) == 0 {
+		panic("signature: Secret is required")
+	}
+	if cfg.Hash == nil {
+		cfg.Hash = sha256.New
+	}
+	if cfg.MaxBodySize <= 0 {
+		cfg.MaxBodySize = defaultSignatureBodySize
+	}
+	if cfg.OnError == nil {
+		cfg.OnError = func(c *Context, err error) {
+			c.String(http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
+		}
+	}
+	if cfg.Message == nil {
+		cfg.Message = func(_ string, body []byte) []byte { return body }
+	}
+	if cfg.Extract == nil {
+		if cfg.Header == "" {
+			panic("signature: Header or Extract is required")
+		}
+		cfg.Extract = func(r *http.Request) (string, []string, error) {
+			sig := r.Header.Get(cfg.Header)
+			if sig == "" || !strings.HasPrefix(sig, cfg.Prefix) {
+				return "", nil, ErrSignatureMissing
+			}
+			ts := ""
+			if cfg.TimestampHeader != "" {
+				if ts = r.Header.Get(cfg.TimestampHeader); ts == "" {
+					return "", nil, ErrSignatureMissing
+				}
+			}
+			return ts, []string{strings.TrimPrefix(sig, cfg.Prefix)}, nil
+		}
+	}
+
+	return func(c *Context) {
+		if err := verifySignature(c, &cfg); err != nil {
+			cfg.OnError(c, err)
+			c.Abort()
+		}
+	}
+}
+
+// verifySignature read the body, check the signature and restore the body.
+func verifySignature(c *Context, cfg *SignatureConfig) error {
+	ts, sigs, err := cfg.Extract(c.Request)
+	if err != nil {
+		return err
+	}
+	if cfg.Tolerance > 0 {
+		sec, err := strconv.ParseInt(ts, 10, 64)
+		if err != nil {
+			return ErrSignatureInvalid
+		}
+		if d := time.Since(time.Unix(sec, 0)); d > cfg.Tolerance || d < -cfg.Tolerance {
+			return ErrSignatureExpired
+		}
+	}
+
+	var body []byte
+	if c.Request.Body != nil {
+		body, err = io.ReadAll(io.LimitReader(c.Request.Body, cfg.MaxBodySize+1))
+		c.Request.Body.Close()
+		if err != nil {
+			return err
+		}
+		if int64(len(body)) > cfg.MaxBodySize {
+			return ErrSignatureInvalid
+		}
+	}
+	// handlers can still read and bind the body
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	mac := hmac.New(cfg.Hash, cfg.Secret)
+	mac.Write(cfg.Message(ts, body))
+	expected := mac.Sum(nil)
+
+	for _, sig := range sigs {
+		var got []byte
+		if cfg.Base64 {
+			got, err = base64.StdEncoding.DecodeString(sig)
+		} else {
+			got, err = hex.DecodeString(sig)
+		}
+		if err == nil && hmac.Equal(got, expected) {
+			return nil
+		}
+	}
+	return ErrSignatureInvalid
+}
@@ -0,0 +1,69 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"fmt"
+	"time"
+)
+
+// SlowReport is a request whose handler chain took longer than the
+// threshold set with WithSlowThreshold.
+type SlowReport struct {
+	Method    string
+	Route     string // route template, "/users/:id"
+	Path      string // request path
+	Handler   string // name of the route handler
+	Status    int
+	Latency   time.Duration
+	Threshold time.Duration
+}
+
+// String format the report as a log line.
+func (r SlowReport) String() string {
+	return fmt.Sprintf("slow request: %s %s (%s) took %v > %v, handler %s, status %d",
+		r.Method, r.Route, r.Path, r.Latency, r.Threshold, r.Handler, r.Status)
+}
+
+// SlowReporter is implemented by the Reporter receiving the slow requests,
+// they are logged otherwise.
+type SlowReporter interface {
+	ReportSlow(c *Context, report SlowReport)
+}
+
+// WithSlowThreshold log the routed requests whose handler chain take more
+// than d, with the route template and handler name, to catch performance
+// regressions in production without tracing. A Reporter implementing
+// SlowReporter receive them instead of the log. Default 0, disabled.
+//
+// Usage:
+//
+//	e := glaze.New(glaze.WithSlowThreshold(500 * time.Millisecond))
+func WithSlowThreshold(d time.Duration) ConfigsFunc {
+	return func(e *Engine) {
+		e.slowThreshold = d
+	}
+}
+
+// checkSlow report the request if it took longer than the threshold.
+func (e *Engine) checkSlow(c *Context, start time.Time) {
+	latency := time.Since(start)
+	if latency <= e.slowThreshold || c.route == nil {
+		return
+	}
+	report := SlowReport{
+		Method:    c.Request.Method,
+		Route:     c.route.Path,
+		Path:      c.Request.URL.Path,
+		Handler:   c.route.Handler,
+		Status:    c.Writer.Status(),
+		Latency:   latency,
+		Threshold: e.slowThreshold,
+	}
+	if r, ok := e.reporter.(SlowReporter); ok {
+		r.ReportSlow(c, report)
+		return
+	}
+	e.logf(LogLevelInfo, "%s\n", report)
+}
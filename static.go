@@ -0,0 +1,158 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"mime"
+	"net/http"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// precompressedEncodings list the sidecar files looked up next to a static
+// file, in server preference order. Brotli is smaller, so it wins over gzip
+// when the client accept both.
+var precompressedEncodings = []struct {
+	encoding string
+	ext      string
+}{
+	{"br", ".br"},
+	{"gzip", ".gz"},
+}
+
+// File writes the specified file into the response body.
+// If a precompressed sidecar (file.br or file.gz) exists and the client
+// accept that encoding, the sidecar is served instead.
+func (c *Context) File(filePath string) {
+	fs, name := fileSystemOf(filePath)
+	serveFile(c, fs, name, nil)
+}
+
+// FileFromFS writes the specified file from http.FileSystem into the response body.
+// Precompressed sidecars are handled the same way as File.
+func (c *Context) FileFromFS(filePath string, fs http.FileSystem) {
+	serveFile(c, fs, filePath, nil)
+}
+
+// fileSystemOf return the directory of a local file and its name in it.
+func fileSystemOf(filePath string) (http.FileSystem, string) {
+	dir, file := filepath.Split(filePath)
+	if dir == "" {
+		dir = "."
+	}
+	return http.Dir(dir), "/" + file
+}
+
+// StaticFile registers a single route that serve a single file of the local filesystem,
+// with a strong ETag and Last-Modified like Static.
+//
+// Example:
+//
+//	r.StaticFile("/favicon.ico", "./resources/favicon.ico")
+func (r *Route) StaticFile(relativePath, filePath string) Routes {
+	fs, name := fileSystemOf(filePath)
+	return r.StaticFileFS(relativePath, name, fs)
+}
+
+// StaticFileFS works just like StaticFile but a custom http.FileSystem can be used instead.
+func (r *Route) StaticFileFS(relativePath, filePath string, fs http.FileSystem) Routes {
+	etags := new(etagCache)
+	return r.staticFileHandler(relativePath, func(c *Context) {
+		serveFile(c, fs, filePath, etags)
+	})
+}
+
+// staticFileHandler registers GET and HEAD routes for a static file handler.
+func (r *Route) staticFileHandler(relativePath string, handler HandlerFunc) Routes {
+	if strings.Contains(relativePath, ":") {
+		panic("URL parameters can not be used when serving a static file")
+	}
+	r.Get(relativePath, handler)
+	r.Head(relativePath, handler)
+	return r.engineInfo()
+}
+
+// serveFile serve name from fs, preferring a precompressed sidecar when possible.
+// The ETag of the served file is set from etags, unless it is nil.
+func serveFile(c *Context, fs http.FileSystem, name string, etags *etagCache) {
+	if !strings.HasPrefix(name, "/") {
+		name = "/" + name
+	}
+	name = path.Clean(name)
+	if !safeFileName(name) {
+		c.defaultResponse(http.StatusNotFound)
+		return
+	}
+
+	f, err := fs.Open(name)
+	if err != nil {
+		c.defaultResponse(http.StatusNotFound)
+		return
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil || stat.IsDir() {
+		c.defaultResponse(http.StatusNotFound)
+		return
+	}
+
+	header := c.Writer.Header()
+	acceptEncoding := c.Request.Header.Get("Accept-Encoding")
+	vary := false
+	for _, pre := range precompressedEncodings {
+		sidecar, err := fs.Open(name + pre.ext)
+		if err != nil {
+			continue
+		}
+		sstat, err := sidecar.Stat()
+		if err != nil || sstat.IsDir() {
+			sidecar.Close()
+			continue
+		}
+
+		// the response now depend on Accept-Encoding, even if this
+		// client get the identity version
+		if !vary {
+			header.Add("Vary", "Accept-Encoding")
+			vary = true
+		}
+		if !acceptsEncoding(acceptEncoding, pre.encoding) {
+			sidecar.Close()
+			continue
+		}
+		defer sidecar.Close()
+
+		// content type come from the original file, not the sidecar
+		ctype := mime.TypeByExtension(path.Ext(name))
+		if ctype == "" {
+			ctype = "application/octet-stream"
+		}
+		header.Set("Content-Type", ctype)
+		header.Set("Content-Encoding", pre.encoding)
+		setETag(c, etags, name+pre.ext, sidecar, sstat)
+		http.ServeContent(c.Writer, c.Request, name, sstat.ModTime(), sidecar)
+		return
+	}
+
+	setETag(c, etags, name, f, stat)
+	http.ServeContent(c.Writer, c.Request, name, stat.ModTime(), f)
+}
+
+// acceptsEncoding report whether the Accept-Encoding header value
+// allow the given content coding (q=0 means not acceptable).
+func acceptsEncoding(header, encoding string) bool {
+	wildcard := false
+	for _, el := range ParseHeader(header) {
+		coding, q := el.Value, el.Q()
+		if strings.EqualFold(coding, encoding) {
+			return q > 0
+		}
+		if coding == "*" {
+			wildcard = q > 0
+		}
+	}
+	return wildcard
+}
@@ -0,0 +1,133 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheRule is the Cache-Control of the static files matching it, see
+// StaticConfig.CacheRules.
+type CacheRule struct {
+	// Match is a path.Match pattern of the file base name ("*.html"), or
+	// of the path below the prefix when it has a slash ("/fonts/*").
+	// Empty match every file.
+	Match string
+
+	// Fingerprinted match only the names with a hash segment of 8 hex
+	// characters or more, "app.3f9a2c1b7d.js".
+	Fingerprinted bool
+
+	CacheControl string
+}
+
+// match report whether the file name, a clean path below the prefix, match the rule.
+func (r CacheRule) match(name string) bool {
+	if r.Fingerprinted && !isFingerprinted(path.Base(name)) {
+		return false
+	}
+	if r.Match == "" {
+		return true
+	}
+	subject := path.Base(name)
+	if strings.Contains(r.Match, "/") {
+		subject = name
+	}
+	ok, _ := path.Match(r.Match, subject)
+	return ok
+}
+
+// isFingerprinted report whether a dot separated part of base, not the
+// first and not the extension, is a hex hash of 8 characters or more.
+func isFingerprinted(base string) bool {
+	parts := strings.Split(base, ".")
+	for i := 1; i < len(parts)-1; i++ {
+		if len(parts[i]) >= 8 && isHex(parts[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+func isHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+			return false
+		}
+	}
+	return true
+}
+
+// setCacheControl set the Cache-Control of the served file name, from the
+// first matching rule or CacheControl.
+func (cfg StaticConfig) setCacheControl(c *Context, name string) {
+	value := cfg.CacheControl
+	for _, rule := range cfg.CacheRules {
+		if rule.match(name) {
+			value = rule.CacheControl
+			break
+		}
+	}
+	if value != "" {
+		c.Writer.Header().Set("Cache-Control", value)
+	}
+}
+
+// etagCache keep the strong ETag of the served files, hashed from their
+// content again only when their size or modification time change.
+type etagCache struct {
+	mu    sync.Mutex
+	files map[string]etagEntry
+}
+
+type etagEntry struct {
+	size    int64
+	modTime time.Time
+	etag    string
+}
+
+// etag return the ETag of the file name, "" when it can not be read.
+// The file is rewound for serving.
+func (ec *etagCache) etag(name string, f http.File, stat fs.FileInfo) string {
+	ec.mu.Lock()
+	entry, ok := ec.files[name]
+	ec.mu.Unlock()
+	if ok && entry.size == stat.Size() && entry.modTime.Equal(stat.ModTime()) {
+		return entry.etag
+	}
+
+	h := sha256.New()
+	_, err := io.Copy(h, f)
+	if _, serr := f.Seek(0, io.SeekStart); err != nil || serr != nil {
+		return ""
+	}
+	entry = etagEntry{size: stat.Size(), modTime: stat.ModTime(), etag: `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`}
+
+	ec.mu.Lock()
+	if ec.files == nil {
+		ec.files = make(map[string]etagEntry)
+	}
+	ec.files[name] = entry
+	ec.mu.Unlock()
+	return entry.etag
+}
+
+// setETag set the ETag header of the file when etags is not nil and the
+// handler did not set one.
+func setETag(c *Context, etags *etagCache, name string, f http.File, stat fs.FileInfo) {
+	if etags == nil || c.Writer.Header().Get("ETag") != "" {
+		return
+	}
+	if etag := etags.etag(name, f, stat); etag != "" {
+		c.Writer.Header().Set("ETag", etag)
+	}
+}
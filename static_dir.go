@@ -0,0 +1,266 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"html/template"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// StaticConfig holds the configuration of Static and StaticFS.
+type StaticConfig struct {
+	// Index is the file served for a directory, default "index.html".
+	Index string
+
+	// Browse generate a listing of the directories without index file,
+	// sortable by name, size and modification time. Default 404.
+	Browse bool
+
+	// ShowHidden serve and list the dot files (.env, .git/...),
+	// default they are hidden and answer 404.
+	ShowHidden bool
+
+	// CacheControl is the Cache-Control header of the served files and
+	// listings, none by default.
+	CacheControl string
+
+	// CacheRules set the Cache-Control of the files matching them, the
+	// first matching rule win over CacheControl.
+	CacheRules []CacheRule
+
+	// Fallback is the file served, without redirect, for the GET paths
+	// matching no file, the history API mode of single page apps. Paths
+	// with an extension (/app.js) still answer 404.
+	Fallback string
+
+	// FallbackExclude are the path prefixes answering 404 instead of
+	// the Fallback, like "/api".
+	FallbackExclude []string
+}
+
+// Static serve the files of the root directory under relativePath, for GET
+// and HEAD requests. Directories serve their index file, or a listing when
+// cfg.Browse is set; paths of directories without trailing slash are
+// redirected. Routes registered under relativePath are served first.
+//
+// Files are sent with Last-Modified and a strong ETag hashed from their
+// content, conditional requests (If-None-Match, If-Modified-Since) answer
+// 304 Not Modified.
+//
+// Usage:
+//
+//	r.Static("/assets", "./public")
+//	r.Static("/share", "/srv/share", glaze.StaticConfig{Browse: true, CacheControl: "no-cache"})
+//
+// Hashed assets cached forever, the pages revalidated:
+//
+//	r.Static("/", "./dist", glaze.StaticConfig{CacheRules: []glaze.CacheRule{
+//	    {Fingerprinted: true, CacheControl: "public, max-age=31536000, immutable"},
+//	    {Match: "*.html", CacheControl: "no-cache"},
+//	}})
+//
+// Single page app, /api/* routes are served first and other /api paths 404:
+//
+//	r.Static("/", "./dist", glaze.StaticConfig{Fallback: "index.html", FallbackExclude: []string{"/api"}})
+func (r *Route) Static(relativePath, root string, cfg ...StaticConfig) Routes {
+	return r.StaticFS(relativePath, http.Dir(root), cfg...)
+}
+
+// StaticFS works just like Static but a custom http.FileSystem can be used instead,
+// http.FS(embedded) for an embed.FS.
+func (r *Route) StaticFS(relativePath string, fsys http.FileSystem, cfg ...StaticConfig) Routes {
+	if strings.Contains(relativePath, ":") {
+		panic("URL parameters can not be used when serving a static folder")
+	}
+	var conf StaticConfig
+	if len(cfg) > 0 {
+		conf = cfg[0]
+	}
+	if conf.Index == "" {
+		conf.Index = "index.html"
+	}
+	prefix := r.jointAbsolutePath(relativePath)
+	etags := new(etagCache)
+	r.engine.addMount(prefix, readMethods, r.meta, r.joinHandler(HandlersChain{func(c *Context) {
+		serveStatic(c, fsys, prefix, conf, etags)
+	}}))
+	return r.engineInfo()
+}
+
+// serveStatic serve the file of the request path below prefix.
+func serveStatic(c *Context, fsys http.FileSystem, prefix string, cfg StaticConfig, etags *etagCache) {
+	if m := c.Request.Method; m != http.MethodGet && m != http.MethodHead {
+		c.Writer.Header().Set("Allow", "GET, HEAD")
+		c.defaultResponse(http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := path.Clean("/" + strings.TrimPrefix(c.Request.URL.Path, strings.TrimSuffix(prefix, "/")))
+	if !safeFileName(name) || !cfg.ShowHidden && isHiddenPath(name) {
+		c.defaultResponse(http.StatusNotFound)
+		return
+	}
+	stat, err := statFile(fsys, name)
+	if err != nil {
+		staticNotFound(c, fsys, name, cfg, etags)
+		return
+	}
+	if !stat.IsDir() {
+		cfg.setCacheControl(c, name)
+		serveFile(c, fsys, name, etags)
+		return
+	}
+
+	// directory: relative links of the index need the trailing slash
+	if !strings.HasSuffix(c.Request.URL.Path, "/") {
+		// a single leading slash, "//host/" would be a protocol relative URL
+		target := "/" + strings.TrimLeft(c.Request.URL.Path, "/") + "/"
+		if c.Request.URL.RawQuery != "" {
+			target += "?" + c.Request.URL.RawQuery
+		}
+		http.Redirect(c.Writer, c.Request, target, http.StatusMovedPermanently)
+		return
+	}
+	index := path.Join(name, cfg.Index)
+	if st, err := statFile(fsys, index); err == nil && !st.IsDir() {
+		cfg.setCacheControl(c, index)
+		serveFile(c, fsys, index, etags)
+		return
+	}
+	if cfg.Browse {
+		if cfg.CacheControl != "" {
+			c.Writer.Header().Set("Cache-Control", cfg.CacheControl)
+		}
+		listDirectory(c, fsys, name, cfg.ShowHidden)
+		return
+	}
+	staticNotFound(c, fsys, name, cfg, etags)
+}
+
+// staticNotFound serve the Fallback file when the path allow it, else 404.
+func staticNotFound(c *Context, fsys http.FileSystem, name string, cfg StaticConfig, etags *etagCache) {
+	if cfg.Fallback == "" || path.Ext(name) != "" {
+		c.defaultResponse(http.StatusNotFound)
+		return
+	}
+	reqPath := c.Request.URL.Path
+	for _, prefix := range cfg.FallbackExclude {
+		prefix = strings.TrimSuffix(prefix, "/")
+		if reqPath == prefix || strings.HasPrefix(reqPath, prefix+"/") {
+			c.defaultResponse(http.StatusNotFound)
+			return
+		}
+	}
+	fallback := path.Clean("/" + cfg.Fallback)
+	cfg.setCacheControl(c, fallback)
+	serveFile(c, fsys, fallback, etags)
+}
+
+func statFile(fsys http.FileSystem, name string) (fs.FileInfo, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return f.Stat()
+}
+
+// isHiddenPath report whether a segment of the clean path start with a dot.
+func isHiddenPath(name string) bool {
+	return strings.Contains(name, "/.")
+}
+
+// dirEntry is a line of the directory listing.
+type dirEntry struct {
+	Name    string
+	URL     string
+	IsDir   bool
+	Size    int64
+	ModTime time.Time
+}
+
+// listDirectory write the HTML listing of the directory name. The sort
+// query parameter is name, size or time; order is asc or desc.
+func listDirectory(c *Context, fsys http.FileSystem, name string, showHidden bool) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		c.defaultResponse(http.StatusNotFound)
+		return
+	}
+	defer f.Close()
+	infos, err := f.Readdir(-1)
+	if err != nil {
+		c.defaultResponse(http.StatusInternalServerError)
+		return
+	}
+
+	entries := make([]dirEntry, 0, len(infos))
+	for _, info := range infos {
+		if !showHidden && strings.HasPrefix(info.Name(), ".") {
+			continue
+		}
+		e := dirEntry{Name: info.Name(), IsDir: info.IsDir(), Size: info.Size(), ModTime: info.ModTime()}
+		e.URL = "./" + (&url.URL{Path: e.Name}).EscapedPath()
+		if e.IsDir {
+			e.Name += "/"
+			e.URL += "/"
+		}
+		entries = append(entries, e)
+	}
+
+	sortBy, order := c.Query("sort"), c.Query("order")
+	less := func(a, b dirEntry) bool { return a.Name < b.Name }
+	switch sortBy {
+	case "size":
+		less = func(a, b dirEntry) bool { return a.Size < b.Size }
+	case "time":
+		less = func(a, b dirEntry) bool { return a.ModTime.Before(b.ModTime) }
+	default:
+		sortBy = "name"
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.IsDir != b.IsDir {
+			return a.IsDir // directories first
+		}
+		if order == "desc" {
+			return less(b, a)
+		}
+		return less(a, b)
+	})
+
+	next := "desc"
+	if order == "desc" {
+		next = "asc"
+	}
+	writeContentType(c.Writer, htmlContentType)
+	c.Writer.WriteHeader(http.StatusOK)
+	if c.Request.Method == http.MethodHead {
+		return
+	}
+	dirListingTemplate.Execute(c.Writer, map[string]any{
+		"Path":    c.Request.URL.Path,
+		"Parent":  name != "/",
+		"Entries": entries,
+		"Sort":    sortBy,
+		"Next":    next,
+	})
+}
+
+var dirListingTemplate = template.Must(template.New("dir").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>Index of {{.Path}}</title></head>
+<body><h1>Index of {{.Path}}</h1>
+<table>
+<tr><th><a href="?sort=name&amp;order={{.Next}}">Name</a></th><th><a href="?sort=size&amp;order={{.Next}}">Size</a></th><th><a href="?sort=time&amp;order={{.Next}}">Modified</a></th></tr>
+{{if .Parent}}<tr><td><a href="../">../</a></td><td></td><td></td></tr>
+{{end}}{{range .Entries}}<tr><td><a href="{{.URL}}">{{.Name}}</a></td><td>{{if not .IsDir}}{{.Size}}{{end}}</td><td>{{.ModTime.UTC.Format "2006-01-02 15:04:05"}}</td></tr>
+{{end}}</table>
+</body></html>
+`))
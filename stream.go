@@ -0,0 +1,91 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Stream call step until it return false, the client disconnect or the
+// server shut down, flushing the response after each step. Flushes go
+// through the Compress middleware, so streamed chunks are not held back
+// in the compressor. It return true when the client is gone.
+//
+// Usage:
+//
+//	c.Stream(func(w io.Writer) bool {
+//	    row, ok := <-rows
+//	    if !ok {
+//	        return false
+//	    }
+//	    fmt.Fprintln(w, row)
+//	    return true
+//	})
+func (c *Context) Stream(step func(w io.Writer) bool) bool {
+	ctx := c.Request.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return true
+		case <-c.Done():
+			return false
+		default:
+		}
+		next := step(c.Writer)
+		c.Writer.Flush()
+		if !next {
+			return false
+		}
+	}
+}
+
+// SSEvent send a server-sent event and flush it. Strings are sent as is,
+// other data as JSON; event can be empty for unnamed messages. The first
+// event set the text/event-stream headers, event streams are never
+// compressed by the Compress middleware.
+//
+// Usage:
+//
+//	for msg := range messages {
+//	    c.SSEvent("message", msg)
+//	}
+func (c *Context) SSEvent(event string, data any) error {
+	if !c.Writer.Written() {
+		h := c.Writer.Header()
+		h.Set("Content-Type", MIME_EVENT_STREAM)
+		h.Set("Cache-Control", "no-cache")
+		h.Set("X-Accel-Buffering", "no") // nginx
+	}
+
+	var payload string
+	switch v := data.(type) {
+	case string:
+		payload = v
+	case []byte:
+		payload = string(v)
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		payload = string(b)
+	}
+
+	var b strings.Builder
+	if event != "" {
+		fmt.Fprintf(&b, "event: %s\n", strings.ReplaceAll(event, "\n", ""))
+	}
+	for _, line := range strings.Split(payload, "\n") {
+		fmt.Fprintf(&b, "data: %s\n", line)
+	}
+	b.WriteByte('\n')
+	if _, err := io.WriteString(c.Writer, b.String()); err != nil {
+		return err
+	}
+	c.Writer.Flush()
+	return nil
+}
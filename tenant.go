@@ -0,0 +1,156 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+)
+
+var (
+	// ErrTenantRequired is rendered when no source give a tenant.
+	ErrTenantRequired = NewError(http.StatusBadRequest, "tenant_required", "tenant required")
+
+	// ErrTenantNotFound is returned by TenantConfig.Lookup for unknown tenants.
+	ErrTenantNotFound = NewError(http.StatusNotFound, "tenant_not_found", "tenant not found")
+)
+
+// TenantConfig holds the configuration of the Tenants middleware. The
+// sources are tried in order: Param, Header, then Domain.
+type TenantConfig struct {
+	// Param is the path parameter of the tenant, "tenant" for
+	// "/t/:tenant/orders". Empty to skip.
+	Param string
+
+	// Header is the request header of the tenant, "X-Tenant". Empty to skip.
+	Header string
+
+	// Domain resolve the tenant from the subdomain of the host (see
+	// Context.Host), "acme" for acme.example.com with Domain "example.com".
+	// Empty to skip.
+	Domain string
+
+	// Lookup load the tenant configuration, returned by Tenant.Config.
+	// It return ErrTenantNotFound (404) for unknown tenants, other errors
+	// are rendered like handler errors (see HTTPError and MapError).
+	// Cache the configurations here, it is called for every request.
+	// Optional, every tenant is accepted without it.
+	Lookup func(c *Context, id string) (any, error)
+
+	// Optional let the requests without tenant through, Context.Tenant
+	// return nil. Default false, they are rejected with ErrTenantRequired.
+	Optional bool
+}
+
+// Tenant is the tenant of a request.
+type Tenant struct {
+	ID     string
+	Config any // from TenantConfig.Lookup
+}
+
+// tenantKey is the context key of the request tenant.
+type tenantKey struct{}
+
+// Tenants returns a middleware resolving the tenant of the request, read
+// with c.Tenant in handlers and TenantFromContext in services.
+//
+// Usage:
+//
+//	r.Use(glaze.Tenants(glaze.TenantConfig{
+//	    Header: "X-Tenant",
+//	    Domain: "example.com",
+//	    Lookup: func(c *glaze.Context, id string) (any, error) {
+//	        return tenants.Get(c.Request.Context(), id) // glaze.ErrTenantNotFound when unknown
+//	    },
+//	}))
+func Tenants(cfg TenantConfig) HandlerFunc {
+	if cfg.Param == "" && cfg.Header == "" && cfg.Domain == "" {
+		panic("tenant: Param, Header or Domain is required")
+	}
+	suffix := "." + strings.Trim(cfg.Domain, ".")
+
+	return func(c *Context) {
+		id := cfg.resolve(c, suffix)
+		if id == "" {
+			if !cfg.Optional {
+				c.renderError(ErrTenantRequired)
+				c.Abort()
+				return
+			}
+			c.Next()
+			return
+		}
+
+		t := &Tenant{ID: id}
+		if cfg.Lookup != nil {
+			conf, err := cfg.Lookup(c, id)
+			if err != nil {
+				c.renderError(err)
+				c.Abort()
+				return
+			}
+			t.Config = conf
+		}
+		c.Set(tenantKey{}, t)
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), tenantKey{}, t))
+		c.Next()
+	}
+}
+
+// resolve return the tenant id of the first source giving one.
+func (cfg *TenantConfig) resolve(c *Context, suffix string) string {
+	if cfg.Param != "" {
+		if id := c.Param(cfg.Param); id != "" {
+			return id
+		}
+	}
+	if cfg.Header != "" {
+		if id := strings.TrimSpace(c.GetHeader(cfg.Header)); id != "" {
+			return id
+		}
+	}
+	if cfg.Domain != "" {
+		host := c.Host()
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		host = strings.ToLower(strings.TrimSuffix(host, "."))
+		if sub, ok := strings.CutSuffix(host, suffix); ok && sub != "" && !strings.Contains(sub, ".") {
+			return sub
+		}
+	}
+	return ""
+}
+
+// Tenant return the tenant of the request resolved by the Tenants
+// middleware, nil without tenant.
+func (c *Context) Tenant() *Tenant {
+	v, _ := c.Get(tenantKey{})
+	t, _ := v.(*Tenant)
+	return t
+}
+
+// TenantFromContext return the tenant of a request context, for the code
+// receiving only c.Request.Context().
+func TenantFromContext(ctx context.Context) *Tenant {
+	t, _ := ctx.Value(tenantKey{}).(*Tenant)
+	return t
+}
+
+// TenantConfigOf return the configuration of the request tenant as T.
+//
+// Usage:
+//
+//	conf, ok := glaze.TenantConfigOf[*TenantSettings](c)
+func TenantConfigOf[T any](c *Context) (T, bool) {
+	var zero T
+	t := c.Tenant()
+	if t == nil {
+		return zero, false
+	}
+	conf, ok := t.Config.(T)
+	return conf, ok
+}
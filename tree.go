@@ -4,64 +4,89 @@
 package glaze
 
 import (
+	"reflect"
+	"runtime"
 	"strings"
 )
 
-// node represents a single path segment in the routing tree.
-// Each node can be either a static segment ("user") or a dynamic parameter (":id").
+// node is a node of the routing tree of a method, a compressed radix tree
+// over the clean path ("users/:id/posts", no leading, trailing or repeated
+// slashes). Static nodes match their path bytes, static paths sharing a
+// prefix share the nodes of that prefix. The nodes at the start of a
+// segment (root, "users/", params with the slash after their value) can
+// also have a parameter child (":id") and a catch-all child ("*filepath")
+// matching the rest of the path.
 type node struct {
-	segment   string           // path segment name
-	param     bool             // true if this is a parameter node (":id")
-	handlers  []HandlerFunc    // handlers executed if this route matches
-	children  map[string]*node // child nodes for static segments
-	paramNode *node            // child node dedicated to parameter segments
+	path      string        // static bytes matched by this node, "" for the root and params
+	segment   string        // parameter name of param and catch-all nodes
+	param     bool          // true if this is a parameter node (":id" or "*filepath")
+	handlers  []HandlerFunc // handlers executed if this route matches
+	route     *RouteInfo    // registered route information, nil if not a route
+	indices   string        // first byte of the path of each static child
+	children  []*node       // static child nodes, in indices order
+	paramNode *node         // child node dedicated to parameter segments
+	catchAll  *node         // child node of the catch-all segment, always a leaf
 }
 
 // addRoute registers a new route in the routing tree.
-func (r *Engine) addRoute(method, path string, handlers ...HandlerFunc) {
+func (r *Engine) addRoute(method, path string, meta map[string]any, handlers ...HandlerFunc) {
 	if r.trees[method] == nil {
 		// init root node if not exists for this method
-		r.trees[method] = &node{children: make(map[string]*node)}
+		r.trees[method] = &node{}
 	}
 	current := r.trees[method]
 	parts := splitClean(path)
 
-	for _, part := range parts {
-		if strings.HasPrefix(part, ":") {
-			// check conflict: param cannot coexist with static child
-			if _, exists := current.children[part]; exists {
-				panic("conflict: param '" + part + "' collides with static route in " + method + " " + path)
-			}
+	// static bytes since the last parameter, like "users/", param
+	// nodes match the slash after their value
+	static := ""
+	for i, part := range parts {
+		if i > 0 && !strings.HasPrefix(parts[i-1], ":") {
+			static += "/"
+		}
+		if !strings.HasPrefix(part, ":") && !strings.HasPrefix(part, "*") {
+			static += part
+			continue
+		}
+		current = current.insertStatic(static, method, path)
+		static = ""
 
-			// if no paramNode yet → create one
-			if current.paramNode == nil {
-				current.paramNode = &node{
-					segment:  part[1:], // remove ":" to store only the param name
-					param:    true,
-					children: make(map[string]*node),
-				}
+		if strings.HasPrefix(part, "*") {
+			// catch-all: last segment, tried after static and param children
+			if i != len(parts)-1 {
+				panic("catch-all '" + part + "' must be the last segment in " + method + " " + path)
 			}
-
-			// move deeper into paramNode
-			current = current.paramNode
-			continue
-		} else {
-			// check conflict: static cannot coexist with paramNode
-			if current.paramNode != nil {
-				panic("conflict: static '" + part + "' collides with param in " + method + " " + path)
+			if part == "*" {
+				panic("catch-all need a name, '*filepath', in " + method + " " + path)
+			}
+			if current.catchAll == nil {
+				current.catchAll = &node{segment: part[1:], param: true}
 			}
+			if current.catchAll.segment != part[1:] {
+				panic("conflict: catch-all '" + part + "' collides with '*" + current.catchAll.segment + "' in " + method + " " + path)
+			}
+			current = current.catchAll
+			continue
+		}
 
-			// if child not exists → create one
-			next := current.children[part]
-			if next == nil {
-				next = &node{segment: part, children: make(map[string]*node)}
-				current.children[part] = next
+		// if no paramNode yet → create one, static segments
+		// registered before it still win over it
+		if current.paramNode == nil {
+			current.paramNode = &node{
+				segment: part[1:], // remove ":" to store only the param name
+				param:   true,
 			}
+		}
 
-			// move deeper into static child
-			current = next
+		if current.paramNode.segment != part[1:] {
+			r.lintIssues = append(r.lintIssues, LintIssue{method, path,
+				"param '" + part + "' is shadowed by ':" + current.paramNode.segment + "', c.Param(\"" + part[1:] + "\") is empty"})
 		}
+
+		// move deeper into paramNode
+		current = current.paramNode
 	}
+	current = current.insertStatic(static, method, path)
 
 	// after loop, current points to final node
 	// check if handlers already exist → duplicate route
@@ -71,53 +96,240 @@ func (r *Engine) addRoute(method, path string, handlers ...HandlerFunc) {
 
 	// assign handlers to this node
 	current.handlers = handlers
+	current.route = &RouteInfo{
+		Method:   method,
+		Path:     path,
+		Meta:     meta,
+		Handler:  lastHandlerName(handlers),
+		Handlers: len(handlers),
+	}
 
 	// add to route list for inspection/debug
-	r.routeList = append(r.routeList, RouteInfo{
-		Method: method,
-		Path:   path,
-	})
+	r.routeList = append(r.routeList, *current.route)
+}
+
+// insertStatic return the node matching the static bytes s below n,
+// splitting the node sharing a prefix with s when needed.
+func (n *node) insertStatic(s, method, path string) *node {
+	for s != "" {
+		// check conflict: new static segment cannot coexist with paramNode
+		if n.paramNode != nil {
+			if segment, _, _ := strings.Cut(s, "/"); !n.hasStatic(segment) {
+				panic("conflict: static '" + segment + "' collides with param in " + method + " " + path)
+			}
+		}
+
+		i := strings.IndexByte(n.indices, s[0])
+		if i < 0 {
+			child := &node{path: s}
+			n.indices += s[:1]
+			n.children = append(n.children, child)
+			return child
+		}
+
+		child := n.children[i]
+		l := commonPrefix(s, child.path)
+		if l < len(child.path) {
+			// split: child keep the common prefix, the rest move below it
+			lower := *child
+			lower.path = child.path[l:]
+			*child = node{path: child.path[:l], indices: lower.path[:1], children: []*node{&lower}}
+		}
+		s = s[l:]
+		n = child
+	}
+	return n
+}
+
+// hasStatic report whether a static route below n has the segment seg,
+// n being at the start of a segment.
+func (n *node) hasStatic(seg string) bool {
+	for {
+		i := strings.IndexByte(n.indices, seg[0])
+		if i < 0 {
+			return false
+		}
+		child := n.children[i]
+		if len(child.path) > len(seg) {
+			return strings.HasPrefix(child.path, seg) && child.path[len(seg)] == '/'
+		}
+		if !strings.HasPrefix(seg, child.path) {
+			return false
+		}
+		if seg = seg[len(child.path):]; seg == "" {
+			// a route end here or continue with another segment
+			return child.route != nil || strings.IndexByte(child.indices, '/') >= 0
+		}
+		n = child
+	}
+}
+
+func commonPrefix(a, b string) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return i
 }
 
 // findRoute searches for a matching route in the tree.
-func (r *Engine) findRoute(method, path string) ([]HandlerFunc, map[string]string) {
+// Param values are appended to params when it is not nil.
+// When static and param segments do not lead to a route, the deepest
+// catch-all seen on the way match the rest of the path, without its
+// leading slash ("css/app.css" for "/static/*filepath").
+// It returns nil node when nothing match, it does not allocate
+// unless the path has repeated slashes.
+func (r *Engine) findRoute(method, path string, params *Params) *node {
 	root := r.trees[method]
 	if root == nil {
 		// no route registered for this method
-		return nil, nil
+		return nil
 	}
 
-	parts := splitClean(path)
+	var (
+		fallback *node // deepest catch-all seen
+		restAt   int   // clean path offset matched by fallback
+		mark     int   // params count when fallback was seen
+	)
+	clean := cleanPath(path)
+	s := clean
 	current := root
-	var params map[string]string
-
-	for _, part := range parts {
-		// first try exact static match
-		if next, ok := current.children[part]; ok {
-			current = next
-			continue
+walk:
+	for {
+		if current.catchAll != nil {
+			fallback, restAt = current.catchAll, len(clean)-len(s)
+			if params != nil {
+				mark = len(*params)
+			}
+		}
+		if s == "" {
+			break
 		}
 
-		// fallback: check if paramNode exists
+		// param: the whole segment, unless a static route has it
 		if current.paramNode != nil {
-			current = current.paramNode
+			part, next := s, ""
+			if i := strings.IndexByte(s, '/'); i >= 0 {
+				part, next = s[:i], s[i:]
+			}
+			if len(current.children) == 0 || !current.hasStatic(part) {
+				if s = next; s != "" {
+					s = s[1:]
+				}
+				current = current.paramNode
 
-			// allocate params map only when needed
-			if params == nil {
-				params = make(map[string]string)
+				// store actual value to param name
+				if params != nil {
+					*params = append(*params, Param{Key: current.segment, Value: part})
+				}
+				continue
 			}
+		}
 
-			// store actual value to param name
-			params[current.segment] = part
-			continue
+		// static child starting with the next byte
+		for i := 0; i < len(current.indices); i++ {
+			if current.indices[i] != s[0] {
+				continue
+			}
+			child := current.children[i]
+			if strings.HasPrefix(s, child.path) {
+				s = s[len(child.path):]
+				current = child
+				continue walk
+			}
+			// "/static" and "/static/" match "/static/*filepath" with an empty value
+			if child.catchAll != nil && len(child.path) == len(s)+1 &&
+				child.path[len(s)] == '/' && strings.HasPrefix(child.path, s) {
+				fallback, restAt = child.catchAll, len(clean)
+				if params != nil {
+					mark = len(*params)
+				}
+			}
+			break
 		}
 
 		// neither static nor param match → route not found
-		return nil, nil
+		current = nil
+		break
+	}
+
+	if (current == nil || current.handlers == nil) && fallback != nil {
+		if params != nil {
+			*params = append((*params)[:mark], Param{Key: fallback.segment, Value: rawRest(path, restAt)})
+		}
+		return fallback
 	}
 
-	// reached final node, return handlers and params
-	return current.handlers, params
+	// reached final node, nil when nothing match
+	return current
+}
+
+// findCaseInsensitive search the clean path s below n ignoring the case of
+// the static bytes, and return out followed by s with the case of the
+// registered path. Param and catch-all values are kept as sent. The
+// static children are tried first, then the param and the catch-all.
+func (n *node) findCaseInsensitive(s string, out []byte) ([]byte, bool) {
+	if s == "" {
+		return out, n.handlers != nil || n.catchAll != nil
+	}
+	for _, child := range n.children {
+		if len(s) >= len(child.path) && strings.EqualFold(s[:len(child.path)], child.path) {
+			if res, ok := child.findCaseInsensitive(s[len(child.path):], append(out, child.path...)); ok {
+				return res, true
+			}
+		}
+	}
+	if n.paramNode != nil {
+		part, next := s, ""
+		if i := strings.IndexByte(s, '/'); i >= 0 {
+			part, next = s[:i+1], s[i+1:]
+		}
+		if res, ok := n.paramNode.findCaseInsensitive(next, append(out, part...)); ok {
+			return res, true
+		}
+	}
+	if n.catchAll != nil {
+		return append(out, s...), true
+	}
+	return nil, false
+}
+
+// cleanPath return path without leading, trailing and repeated slashes,
+// the form matched by the tree. Only paths with repeated slashes allocate.
+func cleanPath(path string) string {
+	path = strings.Trim(path, "/")
+	i := 1
+	for i < len(path) && (path[i] != '/' || path[i-1] != '/') {
+		i++
+	}
+	if i >= len(path) {
+		return path
+	}
+	b := make([]byte, i, len(path))
+	copy(b, path[:i])
+	for ; i < len(path); i++ {
+		if path[i] == '/' && path[i-1] == '/' {
+			continue
+		}
+		b = append(b, path[i])
+	}
+	return string(b)
+}
+
+// rawRest return the request path after offset bytes of its clean path,
+// without leading slash. Trailing and repeated slashes are kept.
+func rawRest(path string, offset int) string {
+	i := 0
+	for i < len(path) && path[i] == '/' {
+		i++
+	}
+	for n := 0; n < offset && i < len(path); i++ {
+		if path[i] == '/' && path[i-1] == '/' {
+			continue
+		}
+		n++
+	}
+	return strings.TrimLeft(path[i:], "/")
 }
 
 func splitClean(p string) []string {
@@ -134,3 +346,15 @@ func splitClean(p string) []string {
 	}
 	return out
 }
+
+// lastHandlerName return the function name of the last handler, the route handler.
+func lastHandlerName(handlers []HandlerFunc) string {
+	if len(handlers) == 0 {
+		return ""
+	}
+	return nameOfFunction(handlers[len(handlers)-1])
+}
+
+func nameOfFunction(f any) string {
+	return runtime.FuncForPC(reflect.ValueOf(f).Pointer()).Name()
+}
@@ -0,0 +1,369 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const defaultMaxFileSize = 32 << 20 // 32 MB
+
+// UploadedFile is the reference of a file stored by Context.Upload.
+type UploadedFile struct {
+	Field       string `json:"field"`
+	Filename    string `json:"filename"`     // name sent by the client, not safe as a path
+	ContentType string `json:"content_type"` // sniffed from the content
+	Size        int64  `json:"size"`
+	Key         string `json:"key"`      // storage key
+	Location    string `json:"location"` // returned by the Storage, path or URL
+}
+
+// Storage is the backend receiving the files of Context.Upload.
+type Storage interface {
+	// Put store r under key and return its location. size is -1 because
+	// files are streamed from the request.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (string, error)
+	// Delete remove a stored file, used to roll back a failed upload.
+	Delete(ctx context.Context, key string) error
+}
+
+// DiskStorage store the files in the directory Dir.
+type DiskStorage struct {
+	Dir string
+}
+
+// Put write r to Dir/key and return the file path.
+func (s DiskStorage) Put(_ context.Context, key string, r io.Reader, _ int64, _ string) (string, error) {
+	name := filepath.Join(s.Dir, filepath.FromSlash(path.Clean("/"+key)))
+	if err := os.MkdirAll(filepath.Dir(name), 0o750); err != nil {
+		return "", err
+	}
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o640)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		os.Remove(name)
+		return "", err
+	}
+	return name, f.Close()
+}
+
+// Delete remove Dir/key.
+func (s DiskStorage) Delete(_ context.Context, key string) error {
+	return os.Remove(filepath.Join(s.Dir, filepath.FromSlash(path.Clean("/"+key))))
+}
+
+// UploadConfig holds the configuration of Context.Upload.
+type UploadConfig struct {
+	// Storage receive the files, required unless the route MultipartLimits
+	// has a TempDir. Use DiskStorage or an adapter of an S3 compatible client.
+	Storage Storage
+
+	// MaxFileSize is the size limit of one file, default the route
+	// MultipartLimits.MaxFileSize or 32 MB.
+	MaxFileSize int64
+
+	// MaxFiles is the limit of files in the request, default 10.
+	MaxFiles int
+
+	// Accept are the allowed MIME types of every file, sniffed from the
+	// content, like "image/png" or "image/*". Empty allow all types.
+	Accept []string
+
+	// Key return the storage key of a file, default a random name with
+	// the extension of the client filename.
+	Key func(c *Context, field, filename string) string
+}
+
+// uploadField is a struct field receiving files.
+type uploadField struct {
+	value    reflect.Value
+	name     string
+	multiple bool
+	required bool
+	max      int
+	accept   []string
+	count    int
+}
+
+var uploadedFileType = reflect.TypeFor[UploadedFile]()
+
+// Upload stream a multipart request into dst, a pointer to struct, then
+// Validate it. Files are checked (count, size, sniffed MIME type) while
+// they are streamed to cfg.Storage, and the fields tagged `file` receive
+// their UploadedFile reference; other parts fill the `form` fields like
+// Bind, as well as the path, query and header fields.
+//
+// The `file` tag is the part name and options:
+//
+//	required      at least one file
+//	max=N         limit of files of a []UploadedFile field
+//	accept=a b    allowed MIME types of the field, space separated
+//
+// Check failures are ValidationErrors with the rules required, max, size
+// and accept. On any error the already stored files are deleted.
+//
+// Usage:
+//
+//	type NewPost struct {
+//	    Title  string               `form:"title" validate:"required"`
+//	    Cover  *glaze.UploadedFile  `file:"cover,required,accept=image/png image/jpeg"`
+//	    Photos []glaze.UploadedFile `file:"photos,max=5,accept=image/*"`
+//	}
+//
+//	var in NewPost
+//	if err := c.Upload(&in, glaze.UploadConfig{Storage: glaze.DiskStorage{Dir: "./uploads"}}); err != nil {
+//	    ...
+//	}
+func (c *Context) Upload(dst any, cfg UploadConfig) (err error) {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("glaze: Upload need a non nil pointer to struct")
+	}
+	limits := c.multipartLimits()
+	if cfg.Storage == nil && limits.TempDir != "" {
+		cfg.Storage = DiskStorage{Dir: limits.TempDir}
+	}
+	if cfg.Storage == nil {
+		return errors.New("glaze: Upload need a Storage")
+	}
+	if cfg.MaxFileSize <= 0 {
+		cfg.MaxFileSize = limits.MaxFileSize
+	}
+	if cfg.MaxFileSize <= 0 {
+		cfg.MaxFileSize = defaultMaxFileSize
+	}
+	c.limitMultipartBody(limits)
+	if cfg.MaxFiles <= 0 {
+		cfg.MaxFiles = 10
+	}
+	if cfg.Key == nil {
+		cfg.Key = randomUploadKey
+	}
+
+	fields := uploadFields(rv.Elem())
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		return err
+	}
+
+	ctx := c.Request.Context()
+	var stored []string
+	defer func() {
+		if err != nil {
+			for _, key := range stored {
+				cfg.Storage.Delete(context.WithoutCancel(ctx), key)
+			}
+		}
+	}()
+
+	values := url.Values{}
+	valuesSize := int64(0)
+	files := 0
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		name := part.FormName()
+		if part.FileName() == "" {
+			// plain value, bounded by the engine multipart memory
+			data, err := io.ReadAll(io.LimitReader(part, limits.Memory-valuesSize+1))
+			if err != nil {
+				return err
+			}
+			if valuesSize += int64(len(data)); valuesSize > limits.Memory {
+				return errors.New("glaze: multipart values too large")
+			}
+			values.Add(name, string(data))
+			continue
+		}
+
+		f := fields[name]
+		if f == nil {
+			continue // file not expected, skipped
+		}
+		files++
+		f.count++
+		if files > cfg.MaxFiles {
+			return ValidationErrors{{Field: name, Rule: "max", Param: strconv.Itoa(cfg.MaxFiles), Value: []UploadedFile{}}}
+		}
+		if f.count > f.max {
+			return ValidationErrors{{Field: name, Rule: "max", Param: strconv.Itoa(f.max), Value: []UploadedFile{}}}
+		}
+
+		file, err := storeUpload(c, cfg, f, part.FileName(), part)
+		if file.Key != "" {
+			stored = append(stored, file.Key)
+		}
+		if err != nil {
+			return err
+		}
+		f.set(file)
+	}
+
+	var missing ValidationErrors
+	for _, f := range fields {
+		if f.required && f.count == 0 {
+			missing = append(missing, FieldError{Field: f.name, Rule: "required"})
+		}
+	}
+	if len(missing) > 0 {
+		sort.Slice(missing, func(i, j int) bool { return missing[i].Field < missing[j].Field })
+		return missing
+	}
+
+	if err := bindValues(rv.Elem(), "form", func(name string) []string { return values[name] }); err != nil {
+		return err
+	}
+	if err := c.bindParams(rv.Elem()); err != nil {
+		return err
+	}
+	return Validate(dst)
+}
+
+// storeUpload check and stream one file to the storage.
+func storeUpload(c *Context, cfg UploadConfig, f *uploadField, filename string, r io.Reader) (UploadedFile, error) {
+	file := UploadedFile{Field: f.name, Filename: filename}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return file, err
+	}
+	head = head[:n]
+	file.ContentType, _, _ = mime.ParseMediaType(http.DetectContentType(head))
+	for _, accept := range [][]string{cfg.Accept, f.accept} {
+		if len(accept) > 0 && !mimeAllowed(file.ContentType, accept) {
+			return file, ValidationErrors{{Field: f.name, Rule: "accept", Param: strings.Join(accept, " "), Value: file.ContentType}}
+		}
+	}
+
+	limited := &sizeLimitReader{r: io.MultiReader(bytes.NewReader(head), r), limit: cfg.MaxFileSize}
+	file.Key = cfg.Key(c, f.name, filename)
+	file.Location, err = cfg.Storage.Put(c.Request.Context(), file.Key, limited, -1, file.ContentType)
+	file.Size = limited.n
+	if limited.exceeded {
+		return file, ValidationErrors{{Field: f.name, Rule: "size", Param: strconv.FormatInt(cfg.MaxFileSize, 10), Value: file.Size}}
+	}
+	if err != nil {
+		return file, fmt.Errorf("glaze: store %s: %w", filename, err)
+	}
+	return file, nil
+}
+
+// uploadFields return the fields tagged `file` by part name.
+func uploadFields(v reflect.Value) map[string]*uploadField {
+	fields := map[string]*uploadField{}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("file")
+		if tag == "" || tag == "-" || !sf.IsExported() {
+			continue
+		}
+		ft := sf.Type
+		f := &uploadField{value: v.Field(i), max: 1}
+		switch {
+		case ft == uploadedFileType, ft.Kind() == reflect.Pointer && ft.Elem() == uploadedFileType:
+		case ft.Kind() == reflect.Slice && ft.Elem() == uploadedFileType:
+			f.multiple = true
+			f.max = int(^uint(0) >> 1)
+		default:
+			panic("glaze: file field " + sf.Name + " must be UploadedFile, *UploadedFile or []UploadedFile")
+		}
+
+		opts := strings.Split(tag, ",")
+		f.name = opts[0]
+		for _, opt := range opts[1:] {
+			key, param, _ := strings.Cut(opt, "=")
+			switch key {
+			case "required":
+				f.required = true
+			case "max":
+				n, err := strconv.Atoi(param)
+				if err != nil || !f.multiple {
+					panic("glaze: invalid file option " + strconv.Quote(opt) + " on " + sf.Name)
+				}
+				f.max = n
+			case "accept":
+				f.accept = strings.Fields(param)
+			default:
+				panic("glaze: unknown file option " + strconv.Quote(opt) + " on " + sf.Name)
+			}
+		}
+		fields[f.name] = f
+	}
+	return fields
+}
+
+// set store the file reference into the field.
+func (f *uploadField) set(file UploadedFile) {
+	switch {
+	case f.multiple:
+		f.value.Set(reflect.Append(f.value, reflect.ValueOf(file)))
+	case f.value.Kind() == reflect.Pointer:
+		f.value.Set(reflect.ValueOf(&file))
+	default:
+		f.value.Set(reflect.ValueOf(file))
+	}
+}
+
+// mimeAllowed report whether ctype match one of the patterns, "image/*" match all images.
+func mimeAllowed(ctype string, patterns []string) bool {
+	for _, p := range patterns {
+		if p == ctype || p == "*/*" {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(p, "/*"); ok && strings.HasPrefix(ctype, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func randomUploadKey(_ *Context, _, filename string) string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b) + strings.ToLower(path.Ext(filename))
+}
+
+// sizeLimitReader fail the read after limit bytes.
+type sizeLimitReader struct {
+	r        io.Reader
+	limit    int64
+	n        int64
+	exceeded bool
+}
+
+func (l *sizeLimitReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.n += int64(n)
+	if l.n > l.limit {
+		l.exceeded = true
+		return n, errors.New("glaze: file too large")
+	}
+	return n, err
+}
@@ -0,0 +1,129 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// nameMeta is the route metadata key of the route name.
+const nameMeta = "glaze.name"
+
+// Name returns a copy of the group whose next route is registered under
+// name, for links built with Engine.URL and Context.RedirectToRoute that
+// survive path changes. Names are unique, so register one route from it.
+//
+// Usage:
+//
+//	r.Name("user").Get("/users/:id", showUser)
+//
+//	link := e.MustURL("user", map[string]string{"id": "7"}, nil) // "/users/7"
+func (r *Route) Name(name string) *Route {
+	return r.Meta(nameMeta, name)
+}
+
+// addRouteName register the path of a named route.
+func (e *Engine) addRouteName(meta map[string]any, method, path string) {
+	name, ok := meta[nameMeta].(string)
+	if !ok {
+		return
+	}
+	// the methods of one path can share the name
+	if prev, exists := e.namedRoutes[name]; exists && prev != path {
+		panic(fmt.Sprintf("glaze: route name %q used by %s and %s %s", name, prev, method, path))
+	}
+	if e.namedRoutes == nil {
+		e.namedRoutes = make(map[string]string)
+	}
+	e.namedRoutes[name] = path
+}
+
+// URL return the path of the route name with its parameters replaced by
+// params (escaped), followed by query. Catch-all values keep their slashes
+// and can be empty. Unknown names, missing and unused parameters are errors.
+func (e *Engine) URL(name string, params map[string]string, query url.Values) (string, error) {
+	path, ok := e.namedRoutes[name]
+	if !ok {
+		return "", fmt.Errorf("glaze: unknown route name %q", name)
+	}
+
+	segments := strings.Split(path, "/")
+	names := make(map[string]bool, len(params))
+	for i, s := range segments {
+		if strings.HasPrefix(s, "*") {
+			v, ok := params[s[1:]]
+			if !ok {
+				return "", fmt.Errorf("glaze: route %q need the parameter %q", name, s[1:])
+			}
+			parts := strings.Split(strings.TrimPrefix(v, "/"), "/")
+			for j, p := range parts {
+				parts[j] = url.PathEscape(p)
+			}
+			segments[i] = strings.Join(parts, "/")
+			names[s[1:]] = true
+			continue
+		}
+		if !strings.HasPrefix(s, ":") {
+			continue
+		}
+		v, ok := params[s[1:]]
+		if !ok || v == "" {
+			return "", fmt.Errorf("glaze: route %q need the parameter %q", name, s[1:])
+		}
+		segments[i] = url.PathEscape(v)
+		names[s[1:]] = true
+	}
+	var unknown []string
+	for k := range params {
+		if !names[k] {
+			unknown = append(unknown, k)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return "", fmt.Errorf("glaze: route %q has no parameter %s", name, strings.Join(unknown, ", "))
+	}
+
+	u := strings.Join(segments, "/")
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	return u, nil
+}
+
+// MustURL is URL panicking on errors, for the links of templates and
+// the routes known at compile time.
+func (e *Engine) MustURL(name string, params map[string]string, query url.Values) string {
+	u, err := e.URL(name, params, query)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+// RedirectToRoute redirect to the route name, see Engine.URL. The status is
+// 302 for GET and HEAD requests and 303 (POST-redirect-GET) otherwise.
+// Nothing is written when the URL can not be built.
+//
+// Usage:
+//
+//	if err := c.RedirectToRoute("user", map[string]string{"id": id}, nil); err != nil {
+//	    c.Error(err)
+//	}
+func (c *Context) RedirectToRoute(name string, params map[string]string, query url.Values) error {
+	u, err := c.engine.URL(name, params, query)
+	if err != nil {
+		return err
+	}
+	status := http.StatusSeeOther
+	if m := c.Request.Method; m == http.MethodGet || m == http.MethodHead {
+		status = http.StatusFound
+	}
+	http.Redirect(c.Writer, c.Request, u, status)
+	return nil
+}
@@ -0,0 +1,301 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// FieldError is a validation rule failed by a field.
+type FieldError struct {
+	Field string // JSON or form name of the field, "address.city" for nested structs
+	Rule  string // failed rule: required, min, max, len, oneof, email, url (size, accept for Upload)
+	Param string // rule parameter, "3" for min=3
+	Value any    // field value
+}
+
+// Error return an english message, "name must be at least 3 characters".
+func (e FieldError) Error() string {
+	return e.Field + " " + e.Message()
+}
+
+// Message return the built-in english message without the field name. The
+// templates of WithValidationMessage and the i18n bundle are applied by
+// Context.FieldMessage.
+func (e FieldError) Message() string {
+	unit := ""
+	switch e.kind() {
+	case "string":
+		unit = " characters"
+	case "array":
+		unit = " items"
+	}
+
+	switch e.Rule {
+	case "required":
+		return "is required"
+	case "min":
+		return "must be at least " + e.Param + unit
+	case "max":
+		return "must be at most " + e.Param + unit
+	case "len":
+		return "must be exactly " + e.Param + unit
+	case "oneof":
+		return "must be one of [" + e.Param + "]"
+	case "email":
+		return "must be a valid email address"
+	case "url":
+		return "must be a valid URL"
+	case "size":
+		return "must be at most " + e.Param + " bytes"
+	case "accept":
+		return "must be of type [" + e.Param + "]"
+	}
+	return "failed rule " + e.Rule
+}
+
+// kind return "string" or "array" (slices, arrays and maps) when the
+// length rules count characters or items, "" otherwise.
+func (e FieldError) kind() string {
+	switch reflect.Indirect(reflect.ValueOf(e.Value)).Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return "array"
+	}
+	return ""
+}
+
+// format replace the {param} and {value} placeholders of tmpl.
+func (e FieldError) format(tmpl string) string {
+	return strings.NewReplacer("{param}", e.Param, "{value}", fmt.Sprint(e.Value)).Replace(tmpl)
+}
+
+// WithValidationMessage replace the english message of a rule in the
+// validation error responses, {param} and {value} are replaced with the
+// rule parameter and the field value. Translated messages are read from
+// the i18n bundle first, see Context.FieldMessage.
+//
+// Usage:
+//
+//	e := glaze.New(glaze.WithValidationMessage("min", "is too short, {param} minimum"))
+func WithValidationMessage(rule, template string) ConfigsFunc {
+	return func(e *Engine) {
+		if e.validationMsgs == nil {
+			e.validationMsgs = make(map[string]string)
+		}
+		e.validationMsgs[rule] = template
+	}
+}
+
+// ValidationErrors is the list of field errors returned by Validate.
+type ValidationErrors []FieldError
+
+// Error join the field messages.
+func (v ValidationErrors) Error() string {
+	msgs := make([]string, len(v))
+	for i, e := range v {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Fields return the messages by field name, for JSON responses.
+func (v ValidationErrors) Fields() map[string]string {
+	out := make(map[string]string, len(v))
+	for _, e := range v {
+		if _, ok := out[e.Field]; !ok {
+			out[e.Field] = e.Message()
+		}
+	}
+	return out
+}
+
+// Validate check the `validate` struct tags of v, a struct or a pointer
+// to struct. Rules are comma separated:
+//
+//	required      not the zero value, not empty for strings, slices and maps
+//	omitempty     skip the other rules when the value is zero
+//	min=N, max=N  number value, or length of strings (in runes), slices and maps
+//	len=N         exact length
+//	oneof=a b c   value is one of the space separated words
+//	email, url    valid address
+//
+// Nested structs are validated too. It return ValidationErrors or nil.
+//
+// Usage:
+//
+//	type CreateUser struct {
+//	    Name  string `json:"name" validate:"required,min=3,max=50"`
+//	    Email string `json:"email" validate:"required,email"`
+//	    Role  string `json:"role" validate:"omitempty,oneof=admin member"`
+//	}
+func Validate(v any) error {
+	var errs ValidationErrors
+	validateStruct(reflect.ValueOf(v), "", &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func validateStruct(v reflect.Value, prefix string, errs *ValidationErrors) {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		fv := v.Field(i)
+		name := prefix + fieldName(sf)
+
+		if tag := sf.Tag.Get("validate"); tag != "" && tag != "-" {
+			validateField(fv, name, tag, errs)
+		}
+
+		// nested structs, not time.Time like types without exported fields
+		inner := fv
+		for inner.Kind() == reflect.Pointer && !inner.IsNil() {
+			inner = inner.Elem()
+		}
+		if inner.Kind() == reflect.Struct {
+			if sf.Anonymous {
+				validateStruct(inner, prefix, errs)
+			} else {
+				validateStruct(inner, name+".", errs)
+			}
+		}
+	}
+}
+
+func validateField(v reflect.Value, name, tag string, errs *ValidationErrors) {
+	rules := strings.Split(tag, ",")
+	zero := v.IsZero() || isEmpty(v)
+	for _, rule := range rules {
+		if rule == "omitempty" && zero {
+			return
+		}
+	}
+
+	value := v.Interface()
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	for _, rule := range rules {
+		rule, param, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		ok := true
+		switch rule {
+		case "", "omitempty":
+			continue
+		case "required":
+			ok = !zero
+		case "min", "max", "len":
+			ok = checkSize(v, rule, param)
+		case "oneof":
+			ok = false
+			s := stringValue(v)
+			for _, word := range strings.Fields(param) {
+				if s == word {
+					ok = true
+					break
+				}
+			}
+		case "email":
+			addr, err := mail.ParseAddress(stringValue(v))
+			ok = err == nil && addr.Address == stringValue(v)
+		case "url":
+			u, err := url.ParseRequestURI(stringValue(v))
+			ok = err == nil && u.Scheme != "" && u.Host != ""
+		default:
+			panic("glaze: unknown validate rule " + strconv.Quote(rule) + " on " + name)
+		}
+		if !ok {
+			*errs = append(*errs, FieldError{Field: name, Rule: rule, Param: param, Value: value})
+			if rule == "required" {
+				return
+			}
+		}
+	}
+}
+
+// checkSize compare a number value or a length with the rule parameter.
+func checkSize(v reflect.Value, rule, param string) bool {
+	limit, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		panic("glaze: invalid " + rule + " parameter " + strconv.Quote(param))
+	}
+
+	var n float64
+	switch v.Kind() {
+	case reflect.String:
+		n = float64(utf8.RuneCountInString(v.String()))
+	case reflect.Slice, reflect.Array, reflect.Map:
+		n = float64(v.Len())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n = float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		n = v.Float()
+	default:
+		return true
+	}
+
+	switch rule {
+	case "min":
+		return n >= limit
+	case "max":
+		return n <= limit
+	}
+	return n == limit
+}
+
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
+
+func stringValue(v reflect.Value) string {
+	if v.Kind() == reflect.String {
+		return v.String()
+	}
+	if !v.IsValid() || v.Kind() == reflect.Pointer && v.IsNil() {
+		return ""
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// fieldName return the JSON name of a field, or its form name.
+func fieldName(sf reflect.StructField) string {
+	for _, key := range []string{"json", "form"} {
+		if tag := sf.Tag.Get(key); tag != "" && tag != "-" {
+			if name, _, _ := strings.Cut(tag, ","); name != "" {
+				return name
+			}
+		}
+	}
+	return sf.Name
+}
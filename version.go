@@ -0,0 +1,112 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// VersionConfig holds the configuration of Versioned.
+type VersionConfig struct {
+	// Vendor is the name in the media types, "myapp" accept
+	// application/vnd.myapp.v2+json and application/vnd.myapp+json;version=2.
+	Vendor string
+
+	// Default is the version of requests without a vendor media type.
+	Default string
+}
+
+// Versioned returns a handler dispatching to the handler of the API version
+// requested in the Accept header, used for the requests asking for no
+// version in particular (application/json, */*...) too with cfg.Default.
+// A request only accepting unknown versions get 406 Not Acceptable.
+//
+// The response Content-Type is the vendor media type when the client asked
+// one, and Vary contain Accept. Handlers read the version with c.APIVersion.
+//
+// Usage:
+//
+//	r.Get("/users", glaze.Versioned(glaze.VersionConfig{Vendor: "myapp", Default: "1"},
+//	    map[string]glaze.HandlerFunc{
+//	        "1": listUsersV1,
+//	        "2": listUsersV2,
+//	    }))
+func Versioned(cfg VersionConfig, handlers map[string]HandlerFunc) HandlerFunc {
+	if _, ok := handlers[cfg.Default]; !ok {
+		panic("glaze: Versioned default version " + strconv.Quote(cfg.Default) + " has no handler")
+	}
+	prefix := "application/vnd." + strings.ToLower(cfg.Vendor)
+
+	return func(c *Context) {
+		c.Writer.Header().Add("Vary", "Accept")
+
+		version, mediaType, asked := negotiateVersion(c.GetHeader("Accept"), prefix, handlers)
+		if version == "" {
+			if asked {
+				c.defaultResponse(http.StatusNotAcceptable)
+				return
+			}
+			version = cfg.Default
+		}
+		if mediaType != "" {
+			c.Writer.Header().Set("Content-Type", mediaType)
+		}
+		c.apiVersion = version
+		handlers[version](c)
+	}
+}
+
+// APIVersion return the version selected by Versioned, "" out of a
+// versioned handler.
+func (c *Context) APIVersion() string {
+	return c.apiVersion
+}
+
+// negotiateVersion return the preferred version of the vendor media types
+// in accept having a handler, with the media type to answer with
+// (application/vnd.myapp.v2+json). asked report whether accept contain a
+// vendor media type with a version.
+func negotiateVersion(accept, prefix string, handlers map[string]HandlerFunc) (version, mediaType string, asked bool) {
+	type offer struct {
+		version, mediaType string
+		q                  float64
+	}
+	var offers []offer
+	for _, el := range ParseHeader(accept) {
+		mediaRange := strings.ToLower(el.Value)
+		if !strings.HasPrefix(mediaRange, prefix) {
+			continue
+		}
+		rest := mediaRange[len(prefix):]
+		name, suffix, _ := strings.Cut(rest, "+")
+		if name != "" && !strings.HasPrefix(name, ".v") {
+			continue // another vendor with the same prefix
+		}
+
+		q, v := el.Q(), strings.TrimPrefix(name, ".v")
+		if v == "" {
+			v = el.Params["version"]
+		}
+		if q <= 0 || v == "" {
+			continue // refused, or no version asked: the default
+		}
+		asked = true
+		if _, ok := handlers[v]; !ok {
+			continue
+		}
+		mt := prefix + ".v" + v
+		if suffix != "" {
+			mt += "+" + suffix
+		}
+		offers = append(offers, offer{v, mt, q})
+	}
+	if len(offers) == 0 {
+		return "", "", asked
+	}
+	sort.SliceStable(offers, func(i, j int) bool { return offers[i].q > offers[j].q })
+	return offers[0].version, offers[0].mediaType, true
+}
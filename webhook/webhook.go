@@ -0,0 +1,286 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+// Package webhook deliver signed webhooks to the endpoints of your users,
+// with retries and exponential backoff.
+//
+// Every attempt is a POST of the JSON payload with the headers:
+//
+//	Webhook-Id          delivery ID, the same for every retry (deduplication)
+//	Webhook-Event       event type, "invoice.paid"
+//	Webhook-Timestamp   unix time of the attempt
+//	Webhook-Signature   sha256=hex(HMAC-SHA256(secret, timestamp + "." + body))
+//
+// Receivers built with glaze verify it with the VerifySignature middleware
+// and SignatureConfig.
+//
+// Usage:
+//
+//	sender := webhook.New(webhook.Config{
+//	    Secret: secret,
+//	    OnAttempt: func(a webhook.Attempt) {
+//	        log.Printf("webhook %s to %s: attempt %d status %d err %v", a.ID, a.URL, a.Attempt, a.Status, a.Err)
+//	    },
+//	})
+//
+//	c.Go(func(ctx context.Context) {
+//	    sender.Send(ctx, endpoint.URL, "invoice.paid", invoice)
+//	})
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	crand "crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/nrhox/glaze"
+)
+
+const (
+	HeaderID        = "Webhook-Id"
+	HeaderEvent     = "Webhook-Event"
+	HeaderTimestamp = "Webhook-Timestamp"
+	HeaderSignature = "Webhook-Signature"
+
+	signaturePrefix = "sha256="
+)
+
+// Config holds the configuration of a Sender.
+type Config struct {
+	// Secret is the HMAC key shared with the receiver.
+	Secret []byte
+
+	// Client send the requests, default a client with a 10s timeout.
+	Client *http.Client
+
+	// MaxAttempts is the number of attempts of a delivery, default 5.
+	MaxAttempts int
+
+	// Backoff return the wait before the retry of attempt n (from 1),
+	// default exponential from 1s to 1m with jitter. A Retry-After
+	// header of the receiver is used instead when present.
+	Backoff func(attempt int) time.Duration
+
+	// UserAgent of the requests, default "glaze-webhook".
+	UserAgent string
+
+	// OnAttempt is called after every attempt, for delivery logs.
+	OnAttempt func(Attempt)
+}
+
+// Attempt is the outcome of one delivery attempt.
+type Attempt struct {
+	ID       string // delivery ID
+	Event    string
+	URL      string
+	Attempt  int           // from 1
+	Status   int           // response status, 0 on network errors
+	Duration time.Duration // of the request
+	Err      error         // nil on success
+	Retry    bool          // another attempt will follow
+}
+
+// DeliveryError is returned by Send when every attempt failed, or the
+// receiver rejected the delivery with a 4xx status.
+type DeliveryError struct {
+	ID       string
+	Attempts int
+	Status   int // last response status, 0 on network errors
+	Err      error
+}
+
+func (e *DeliveryError) Error() string {
+	return fmt.Sprintf("webhook: delivery %s failed after %d attempts: %v", e.ID, e.Attempts, e.Err)
+}
+
+func (e *DeliveryError) Unwrap() error {
+	return e.Err
+}
+
+// Sender deliver webhooks, it is safe for concurrent use.
+type Sender struct {
+	cfg Config
+}
+
+// New create a Sender, cfg.Secret is required.
+func New(cfg Config) *Sender {
+	if len(cfg.Secret) == 0 {
+		panic("webhook: Secret is required")
+	}
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 5
+	}
+	if cfg.Backoff == nil {
+		cfg.Backoff = defaultBackoff
+	}
+	if cfg.UserAgent == "" {
+		cfg.UserAgent = "glaze-webhook"
+	}
+	return &Sender{cfg: cfg}
+}
+
+// defaultBackoff double from 1s to 1m, with up to 20% of jitter.
+func defaultBackoff(attempt int) time.Duration {
+	d := time.Second << min(attempt-1, 6)
+	if d > time.Minute {
+		d = time.Minute
+	}
+	return d + rand.N(d/5+1)
+}
+
+// Send deliver payload (JSON encoded, or sent as is for []byte and
+// json.RawMessage) to url, retrying network errors, 408, 429 and 5xx
+// responses. It return the delivery ID, and a *DeliveryError when the
+// delivery failed. ctx cancel the retries.
+func (s *Sender) Send(ctx context.Context, url, event string, payload any) (string, error) {
+	var body []byte
+	switch p := payload.(type) {
+	case []byte:
+		body = p
+	case json.RawMessage:
+		body = p
+	default:
+		var err error
+		if body, err = json.Marshal(payload); err != nil {
+			return "", fmt.Errorf("webhook: encode payload: %w", err)
+		}
+	}
+	id := newID()
+
+	var last Attempt
+	for n := 1; ; n++ {
+		var wait time.Duration
+		last, wait = s.attempt(ctx, id, url, event, body, n)
+		last.Retry = last.Err != nil && retryable(last.Status) && n < s.cfg.MaxAttempts && ctx.Err() == nil
+		s.report(last)
+		if last.Err == nil {
+			return id, nil
+		}
+		if !last.Retry {
+			break
+		}
+		if wait <= 0 {
+			wait = s.cfg.Backoff(n)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return id, &DeliveryError{ID: id, Attempts: n, Status: last.Status, Err: ctx.Err()}
+		case <-timer.C:
+		}
+	}
+	return id, &DeliveryError{ID: id, Attempts: last.Attempt, Status: last.Status, Err: last.Err}
+}
+
+func (s *Sender) report(a Attempt) {
+	if s.cfg.OnAttempt != nil {
+		s.cfg.OnAttempt(a)
+	}
+}
+
+// attempt send one signed request, it return the Retry-After wait of
+// the receiver.
+func (s *Sender) attempt(ctx context.Context, id, url, event string, body []byte, n int) (Attempt, time.Duration) {
+	a := Attempt{ID: id, Event: event, URL: url, Attempt: n}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		a.Err = err
+		return a, 0
+	}
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", s.cfg.UserAgent)
+	req.Header.Set(HeaderID, id)
+	req.Header.Set(HeaderEvent, event)
+	req.Header.Set(HeaderTimestamp, ts)
+	req.Header.Set(HeaderSignature, signaturePrefix+Sign(s.cfg.Secret, ts, body))
+
+	start := time.Now()
+	resp, err := s.cfg.Client.Do(req)
+	a.Duration = time.Since(start)
+	if err != nil {
+		a.Err = err
+		return a, 0
+	}
+	// drain a bit so the connection is reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+
+	a.Status = resp.StatusCode
+	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+		return a, 0
+	}
+	a.Err = errors.New("webhook: receiver responded " + resp.Status)
+	return a, parseRetryAfter(resp.Header.Get("Retry-After"))
+}
+
+// retryable report whether a failed attempt with status is retried,
+// status is 0 for network errors.
+func retryable(status int) bool {
+	return status == 0 || status == http.StatusRequestTimeout ||
+		status == http.StatusTooManyRequests || status >= 500
+}
+
+// parseRetryAfter return the wait of a Retry-After header, in seconds
+// or as HTTP date, capped to 1 hour.
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	var d time.Duration
+	if sec, err := strconv.Atoi(v); err == nil {
+		d = time.Duration(sec) * time.Second
+	} else if t, err := http.ParseTime(v); err == nil {
+		d = time.Until(t)
+	}
+	return min(max(d, 0), time.Hour)
+}
+
+// Sign return the hex HMAC-SHA256 of timestamp + "." + body.
+func Sign(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignatureConfig returns the glaze.VerifySignature config of the webhooks
+// sent by a Sender with secret, timestamps older than tolerance are rejected.
+//
+// Usage:
+//
+//	r.Post("/webhooks", glaze.VerifySignature(webhook.SignatureConfig(secret, 5*time.Minute)), handler)
+func SignatureConfig(secret []byte, tolerance time.Duration) glaze.SignatureConfig {
+	return glaze.SignatureConfig{
+		Secret:          secret,
+		Header:          HeaderSignature,
+		Prefix:          signaturePrefix,
+		TimestampHeader: HeaderTimestamp,
+		Tolerance:       tolerance,
+		Message: func(ts string, body []byte) []byte {
+			return append([]byte(ts+"."), body...)
+		},
+	}
+}
+
+// newID return a random delivery ID.
+func newID() string {
+	b := make([]byte, 16)
+	crand.Read(b)
+	return "wh_" + hex.EncodeToString(b)
+}
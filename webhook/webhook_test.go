@@ -0,0 +1,89 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nrhox/glaze"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSendRetriesAndVerify(t *testing.T) {
+	secret := []byte("s3cret")
+	var calls atomic.Int32
+	var ids []string
+
+	r := glaze.New()
+	r.Post("/hook", glaze.VerifySignature(SignatureConfig(secret, time.Minute)), func(c *glaze.Context) {
+		ids = append(ids, c.GetHeader(HeaderID))
+		if calls.Add(1) < 3 {
+			c.String(http.StatusServiceUnavailable, "busy")
+			return
+		}
+		body, _ := io.ReadAll(c.Request.Body)
+		assert.Equal(t, `{"amount":42}`, string(body))
+		assert.Equal(t, "invoice.paid", c.GetHeader(HeaderEvent))
+		c.String(http.StatusNoContent, "")
+	})
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	var attempts []Attempt
+	s := New(Config{
+		Secret:    secret,
+		Backoff:   func(int) time.Duration { return time.Millisecond },
+		OnAttempt: func(a Attempt) { attempts = append(attempts, a) },
+	})
+	id, err := s.Send(context.Background(), srv.URL+"/hook", "invoice.paid", map[string]int{"amount": 42})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{id, id, id}, ids, "same ID on retries")
+	assert.Len(t, attempts, 3)
+	assert.Equal(t, http.StatusServiceUnavailable, attempts[0].Status)
+	assert.True(t, attempts[0].Retry)
+	assert.Equal(t, http.StatusNoContent, attempts[2].Status)
+	assert.NoError(t, attempts[2].Err)
+
+	// bad secret is rejected by the receiver, 4xx are not retried
+	calls.Store(10)
+	attempts = nil
+	bad := New(Config{Secret: []byte("wrong"), OnAttempt: func(a Attempt) { attempts = append(attempts, a) }})
+	_, err = bad.Send(context.Background(), srv.URL+"/hook", "invoice.paid", []byte(`{}`))
+	var derr *DeliveryError
+	assert.True(t, errors.As(err, &derr))
+	assert.Equal(t, http.StatusUnauthorized, derr.Status)
+	assert.Equal(t, 1, derr.Attempts)
+	assert.False(t, attempts[0].Retry)
+}
+
+func TestSendGiveUp(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	s := New(Config{Secret: []byte("k"), MaxAttempts: 3, Backoff: func(int) time.Duration { return time.Millisecond }})
+	_, err := s.Send(context.Background(), srv.URL, "ping", nil)
+	var derr *DeliveryError
+	assert.True(t, errors.As(err, &derr))
+	assert.Equal(t, 3, derr.Attempts)
+	assert.Equal(t, int32(3), calls.Load())
+
+	// context cancel the retries
+	s = New(Config{Secret: []byte("k"), Backoff: func(int) time.Duration { return time.Hour }})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = s.Send(ctx, srv.URL, "ping", nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,470 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// WebSocket message types (RFC 6455 opcodes).
+const (
+	WSText   = 1
+	WSBinary = 2
+	WSClose  = 8
+	WSPing   = 9
+	WSPong   = 10
+
+	wsContinuation = 0
+)
+
+// WebSocket close codes.
+const (
+	WSCloseNormal          = 1000
+	WSCloseGoingAway       = 1001
+	WSCloseProtocolError   = 1002
+	WSCloseUnsupportedData = 1003
+	WSCloseNoStatus        = 1005
+	WSCloseAbnormal        = 1006
+	WSCloseInvalidPayload  = 1007
+	WSClosePolicyViolation = 1008
+	WSCloseMessageTooBig   = 1009
+	WSCloseInternalError   = 1011
+)
+
+const (
+	wsGUID             = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+	defaultWSReadLimit = 32 << 20 // default 32 MB per message
+	wsMaxControl       = 125
+)
+
+// ErrBadHandshake is returned by Upgrade when the request is not a
+// valid websocket handshake, a 4xx response is already sent.
+var ErrBadHandshake = errors.New("websocket: bad handshake")
+
+// WSCloseError is returned by ReadMessage when the peer close the connection.
+type WSCloseError struct {
+	Code int
+	Text string
+}
+
+func (e *WSCloseError) Error() string {
+	return "websocket: close " + strconv.Itoa(e.Code) + " " + e.Text
+}
+
+// WSOptions holds the configuration of a websocket upgrade.
+type WSOptions struct {
+	// Subprotocols supported by the server in order of preference,
+	// the first one also offered by the client is selected.
+	Subprotocols []string
+
+	// CheckOrigin report whether the Origin header is allowed. Default
+	// accept requests without Origin or with the same host as the request,
+	// it protect against cross-site websocket hijacking.
+	CheckOrigin func(r *http.Request) bool
+
+	// ReadLimit is the maximum size of a message, default 32 MB.
+	// Bigger messages close the connection with WSCloseMessageTooBig.
+	ReadLimit int64
+}
+
+// WSConn is a websocket connection created by Context.Upgrade.
+// One goroutine can read and another write at the same time,
+// WriteMessage, Ping and Close are safe for concurrent use.
+type WSConn struct {
+	conn        net.Conn
+	br          *bufio.Reader
+	subprotocol string
+	readLimit   int64
+
+	writeMu sync.Mutex
+	closed  bool // close frame sent
+
+	pingHandler func(data string) error
+	pongHandler func(data string) error
+}
+
+// Upgrade complete the websocket handshake and take over the connection.
+// The handler must not write the response after a successful upgrade.
+//
+// Usage:
+//
+//	r.Get("/ws", func(c *glaze.Context) {
+//	    ws, err := c.Upgrade()
+//	    if err != nil {
+//	        return
+//	    }
+//	    defer ws.Close(glaze.WSCloseNormal, "")
+//	    for {
+//	        typ, msg, err := ws.ReadMessage()
+//	        if err != nil {
+//	            return
+//	        }
+//	        ws.WriteMessage(typ, msg)
+//	    }
+//	})
+func (c *Context) Upgrade(opts ...WSOptions) (*WSConn, error) {
+	var opt WSOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	if opt.CheckOrigin == nil {
+		opt.CheckOrigin = sameOrigin
+	}
+	if opt.ReadLimit <= 0 {
+		opt.ReadLimit = defaultWSReadLimit
+	}
+
+	req := c.Request
+	if req.Method != http.MethodGet ||
+		!headerHasToken(req.Header, "Connection", "upgrade") ||
+		!headerHasToken(req.Header, "Upgrade", "websocket") {
+		c.String(http.StatusBadRequest, "websocket: not a websocket handshake")
+		return nil, ErrBadHandshake
+	}
+	if req.Header.Get("Sec-WebSocket-Version") != "13" {
+		c.Writer.Header().Set("Sec-WebSocket-Version", "13")
+		c.String(http.StatusUpgradeRequired, "websocket: unsupported version")
+		return nil, ErrBadHandshake
+	}
+	key := req.Header.Get("Sec-WebSocket-Key")
+	if raw, err := base64.StdEncoding.DecodeString(key); err != nil || len(raw) != 16 {
+		c.String(http.StatusBadRequest, "websocket: invalid Sec-WebSocket-Key")
+		return nil, ErrBadHandshake
+	}
+	if !opt.CheckOrigin(req) {
+		c.String(http.StatusForbidden, "websocket: origin not allowed")
+		return nil, ErrBadHandshake
+	}
+	subprotocol := selectSubprotocol(req.Header, opt.Subprotocols)
+
+	netConn, brw, err := http.NewResponseController(c.Writer).Hijack()
+	if err != nil {
+		c.String(http.StatusInternalServerError, "websocket: "+err.Error())
+		return nil, err
+	}
+	if rw, ok := c.Writer.(*responseWriter); ok {
+		rw.status = http.StatusSwitchingProtocols
+	}
+	// the server deadlines are still set on the hijacked connection
+	netConn.SetDeadline(time.Time{})
+
+	var b strings.Builder
+	b.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n")
+	b.WriteString("Sec-WebSocket-Accept: " + wsAcceptKey(key) + "\r\n")
+	if subprotocol != "" {
+		b.WriteString("Sec-WebSocket-Protocol: " + subprotocol + "\r\n")
+	}
+	b.WriteString("\r\n")
+	if _, err := netConn.Write([]byte(b.String())); err != nil {
+		netConn.Close()
+		return nil, err
+	}
+
+	ws := &WSConn{
+		conn:        netConn,
+		br:          brw.Reader,
+		subprotocol: subprotocol,
+		readLimit:   opt.ReadLimit,
+	}
+	ws.pingHandler = func(data string) error {
+		return ws.writeFrame(WSPong, []byte(data))
+	}
+	return ws, nil
+}
+
+// Subprotocol return the negotiated subprotocol, empty if none.
+func (ws *WSConn) Subprotocol() string {
+	return ws.subprotocol
+}
+
+// NetConn return the underlying connection.
+func (ws *WSConn) NetConn() net.Conn {
+	return ws.conn
+}
+
+// SetReadDeadline set the deadline of the next reads, zero means no deadline.
+func (ws *WSConn) SetReadDeadline(t time.Time) error {
+	return ws.conn.SetReadDeadline(t)
+}
+
+// SetWriteDeadline set the deadline of the next writes, zero means no deadline.
+func (ws *WSConn) SetWriteDeadline(t time.Time) error {
+	return ws.conn.SetWriteDeadline(t)
+}
+
+// SetPingHandler set the handler of ping frames received by ReadMessage,
+// default reply with a pong carrying the same data.
+func (ws *WSConn) SetPingHandler(h func(data string) error) {
+	ws.pingHandler = h
+}
+
+// SetPongHandler set the handler of pong frames received by ReadMessage,
+// usually to extend the read deadline of a keep-alive.
+func (ws *WSConn) SetPongHandler(h func(data string) error) {
+	ws.pongHandler = h
+}
+
+// Ping send a ping frame, the peer answer with a pong.
+func (ws *WSConn) Ping(data []byte) error {
+	return ws.writeFrame(WSPing, data)
+}
+
+// WriteMessage send a text or binary message.
+func (ws *WSConn) WriteMessage(messageType int, data []byte) error {
+	if messageType != WSText && messageType != WSBinary {
+		return errors.New("websocket: invalid message type " + strconv.Itoa(messageType))
+	}
+	return ws.writeFrame(messageType, data)
+}
+
+// WriteText send a text message.
+func (ws *WSConn) WriteText(s string) error {
+	return ws.writeFrame(WSText, []byte(s))
+}
+
+// Close send a close frame with code and reason, then close the connection.
+func (ws *WSConn) Close(code int, reason string) error {
+	payload := make([]byte, 2, 2+len(reason))
+	binary.BigEndian.PutUint16(payload, uint16(code))
+	payload = append(payload, reason...)
+	if len(payload) > wsMaxControl {
+		payload = payload[:wsMaxControl]
+	}
+
+	ws.writeMu.Lock()
+	var err error
+	if !ws.closed {
+		ws.closed = true
+		ws.conn.SetWriteDeadline(time.Now().Add(time.Second))
+		err = ws.writeFrameLocked(WSClose, payload)
+	}
+	ws.writeMu.Unlock()
+
+	if cerr := ws.conn.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// ReadMessage read the next text or binary message, fragmented messages
+// are assembled. Control frames are handled while reading: ping and pong
+// call their handler, close return a *WSCloseError.
+func (ws *WSConn) ReadMessage() (int, []byte, error) {
+	var (
+		messageType int
+		message     []byte
+	)
+	for {
+		fin, opcode, payload, err := ws.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch opcode {
+		case WSPing:
+			if ws.pingHandler != nil {
+				if err := ws.pingHandler(string(payload)); err != nil {
+					return 0, nil, err
+				}
+			}
+			continue
+		case WSPong:
+			if ws.pongHandler != nil {
+				if err := ws.pongHandler(string(payload)); err != nil {
+					return 0, nil, err
+				}
+			}
+			continue
+		case WSClose:
+			return 0, nil, ws.handleClose(payload)
+		case WSText, WSBinary:
+			if messageType != 0 {
+				return 0, nil, ws.fail(WSCloseProtocolError, "new message inside a fragmented message")
+			}
+			messageType = opcode
+		case wsContinuation:
+			if messageType == 0 {
+				return 0, nil, ws.fail(WSCloseProtocolError, "continuation without message")
+			}
+		default:
+			return 0, nil, ws.fail(WSCloseProtocolError, "unknown opcode "+strconv.Itoa(opcode))
+		}
+
+		if int64(len(message)+len(payload)) > ws.readLimit {
+			return 0, nil, ws.fail(WSCloseMessageTooBig, "message too big")
+		}
+		message = append(message, payload...)
+		if !fin {
+			continue
+		}
+		if messageType == WSText && !utf8.Valid(message) {
+			return 0, nil, ws.fail(WSCloseInvalidPayload, "invalid utf-8")
+		}
+		return messageType, message, nil
+	}
+}
+
+// readFrame read one frame and unmask its payload.
+func (ws *WSConn) readFrame() (fin bool, opcode int, payload []byte, err error) {
+	var head [2]byte
+	if _, err = io.ReadFull(ws.br, head[:]); err != nil {
+		return
+	}
+	fin = head[0]&0x80 != 0
+	opcode = int(head[0] & 0x0f)
+	if head[0]&0x70 != 0 {
+		err = ws.fail(WSCloseProtocolError, "reserved bits set")
+		return
+	}
+	if head[1]&0x80 == 0 {
+		err = ws.fail(WSCloseProtocolError, "client frame not masked")
+		return
+	}
+
+	length := uint64(head[1] & 0x7f)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err = io.ReadFull(ws.br, ext[:]); err != nil {
+			return
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err = io.ReadFull(ws.br, ext[:]); err != nil {
+			return
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if opcode >= WSClose && (length > wsMaxControl || !fin) {
+		err = ws.fail(WSCloseProtocolError, "invalid control frame")
+		return
+	}
+	if length > uint64(ws.readLimit) {
+		err = ws.fail(WSCloseMessageTooBig, "message too big")
+		return
+	}
+
+	var mask [4]byte
+	if _, err = io.ReadFull(ws.br, mask[:]); err != nil {
+		return
+	}
+	payload = make([]byte, length)
+	if _, err = io.ReadFull(ws.br, payload); err != nil {
+		return
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return
+}
+
+// handleClose answer a close frame and return the close error.
+func (ws *WSConn) handleClose(payload []byte) error {
+	closeErr := &WSCloseError{Code: WSCloseNoStatus}
+	if len(payload) >= 2 {
+		closeErr.Code = int(binary.BigEndian.Uint16(payload))
+		closeErr.Text = string(payload[2:])
+	}
+	code := closeErr.Code
+	if code == WSCloseNoStatus {
+		code = WSCloseNormal
+	}
+	ws.Close(code, "")
+	return closeErr
+}
+
+// fail close the connection after a protocol error.
+func (ws *WSConn) fail(code int, reason string) error {
+	ws.Close(code, reason)
+	return &WSCloseError{Code: code, Text: reason}
+}
+
+func (ws *WSConn) writeFrame(opcode int, payload []byte) error {
+	ws.writeMu.Lock()
+	defer ws.writeMu.Unlock()
+	if ws.closed {
+		return net.ErrClosed
+	}
+	return ws.writeFrameLocked(opcode, payload)
+}
+
+// writeFrameLocked write a single unmasked frame, server frames are never masked.
+func (ws *WSConn) writeFrameLocked(opcode int, payload []byte) error {
+	header := make([]byte, 2, 10)
+	header[0] = 0x80 | byte(opcode)
+	switch n := len(payload); {
+	case n <= 125:
+		header[1] = byte(n)
+	case n <= 0xffff:
+		header[1] = 126
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header[1] = 127
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+	buffers := net.Buffers{header, payload}
+	_, err := buffers.WriteTo(ws.conn)
+	return err
+}
+
+// wsAcceptKey compute Sec-WebSocket-Accept for the client key.
+func wsAcceptKey(key string) string {
+	h := sha1.Sum([]byte(key + wsGUID))
+	return base64.StdEncoding.EncodeToString(h[:])
+}
+
+// sameOrigin accept requests without Origin or with the request host.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Host, r.Host)
+}
+
+// selectSubprotocol return the first server protocol offered by the client.
+func selectSubprotocol(h http.Header, supported []string) string {
+	offered := map[string]struct{}{}
+	for _, v := range h.Values("Sec-WebSocket-Protocol") {
+		for _, p := range strings.Split(v, ",") {
+			offered[strings.TrimSpace(p)] = struct{}{}
+		}
+	}
+	for _, p := range supported {
+		if _, ok := offered[p]; ok {
+			return p
+		}
+	}
+	return ""
+}
+
+// headerHasToken report whether the comma separated header contain token.
+func headerHasToken(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+// Copyright 2025 Jalu Nugroho
+// SPDX-License-Identifier: MIT
+
+package glaze
+
+import (
+	"mime"
+	"strings"
+)
+
+// When returns a middleware that run mw only when predicate return true,
+// otherwise the chain continue as if mw was not registered.
+//
+// Usage:
+//
+//	r.Use(glaze.When(glaze.IfPathPrefix("/api"), glaze.Logger()))
+func When(predicate func(*Context) bool, mw HandlerFunc) HandlerFunc {
+	return func(c *Context) {
+		if predicate(c) {
+			mw(c)
+		}
+	}
+}
+
+// IfPathPrefix is a When predicate matching the request path prefix.
+func IfPathPrefix(prefix string) func(*Context) bool {
+	return func(c *Context) bool {
+		return strings.HasPrefix(c.Request.URL.Path, prefix)
+	}
+}
+
+// IfHeader is a When predicate matching requests with the header present.
+func IfHeader(key string) func(*Context) bool {
+	return func(c *Context) bool {
+		return c.Request.Header.Get(key) != ""
+	}
+}
+
+// IfContentType is a When predicate matching the request media type,
+// parameters like charset are ignored.
+func IfContentType(mediaType string) func(*Context) bool {
+	return func(c *Context) bool {
+		mt, _, err := mime.ParseMediaType(c.Request.Header.Get("Content-Type"))
+		return err == nil && strings.EqualFold(mt, mediaType)
+	}
+}
+
+// Not negate a When predicate.
+func Not(predicate func(*Context) bool) func(*Context) bool {
+	return func(c *Context) bool {
+		return !predicate(c)
+	}
+}